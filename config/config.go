@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 //Verbosity is the current log level
@@ -38,12 +40,31 @@ type userFlags struct {
 	Tasks              string
 	AttachmentKey      string
 	ConfigFile         string
+	SpecDir            string
 	Override           string
 	OutputPath         string
 	Filter             string
 	BrowserURL         string
 	AttachmentEndpoint string
 	Suites             string
+	FailOn             string
+	Verify             string
+	Format             string
+	Template           string
+	ReportEvents       bool
+	EventsAccountID    string
+	EventsKey          string
+	EventsEndpoint     string
+	Serve              string
+	ServeToken         string
+	ServeTLSCert       string
+	ServeTLSKey        string
+	Watch              time.Duration
+	WatchCount         int
+	Root               string
+	ApplyFixes         bool
+	Pids               []int32
+	Offline            bool
 	InNewRelicCLI      bool
 }
 
@@ -76,6 +97,8 @@ func (f userFlags) MarshalJSON() ([]byte, error) {
 		Filter           string
 		BrowserURL       string
 		Suites           string
+		Pids             []int32 `json:",omitempty"`
+		Offline          bool    `json:",omitempty"`
 	}{
 		Verbose:          f.Verbose,
 		Quiet:            f.Quiet,
@@ -93,6 +116,8 @@ func (f userFlags) MarshalJSON() ([]byte, error) {
 		Filter:           f.Filter,
 		BrowserURL:       f.BrowserURL,
 		Suites:           f.Suites,
+		Pids:             f.Pids,
+		Offline:          f.Offline,
 	})
 }
 
@@ -157,6 +182,8 @@ func ParseFlags() {
 	flag.StringVar(&Flags.ProxyUser, "proxy-user", defaultString, "Proxy username, if necessary")
 	flag.StringVar(&Flags.ProxyPassword, "proxy-pw", defaultString, "Proxy pasword, if necessary")
 
+	flag.StringVar(&Flags.SpecDir, "spec-dir", defaultString, "Directory holding newer versions of the agent settings specs (e.g. ruby.yml) used to validate config settings instead of the ones built into nrdiag. Specs missing from it are taken from nrdiag")
+
 	flag.StringVar(&Flags.Override, "o", defaultString, "alias for -override")
 	flag.StringVar(&Flags.Override, "override", defaultString, "Specify overrides for detected values. Format <Identifier>.<property>=<value> - example '-o Base/Config/Validate.agentLanguage=PHP'")
 
@@ -165,7 +192,10 @@ func ParseFlags() {
 	flag.BoolVar(&Flags.YesToAll, "y", false, "alias for -yes")
 	flag.BoolVar(&Flags.YesToAll, "yes", false, "Say 'yes' to any prompt that comes up while running.")
 
-	flag.StringVar(&Flags.Filter, "filter", "success,warning,failure,error,info", "Filter results based on status. Accepted values: Success, Warning, Failure, Error, None or Info. Multiple values can be provided in commma separated list. e.g: \"Success,Warning,Failure\"")
+	flag.BoolVar(&Flags.Interactive, "i", false, "alias for -interactive")
+	flag.BoolVar(&Flags.Interactive, "interactive", false, "Guided mode: detects the installed New Relic agents, proposes the matching suites, lets you review which files with secure information to include and browse the results.")
+
+	flag.StringVar(&Flags.Filter, "filter", "success,warning,failure,error,info,skipped", "Filter results based on status. Accepted values: Success, Warning, Failure, Error, None, Info or Skipped. Multiple values can be provided in commma separated list. e.g: \"Success,Warning,Failure\"")
 
 	flag.BoolVar(&Flags.Quiet, "q", false, "Quiet ouput; only prints the high level results and not the explainatory output. Suppresses file addition warnings if '-y' is also used. Does not contradict '-v'")
 	flag.BoolVar(&Flags.VeryQuiet, "qq", false, "Very quiet ouput; only prints a single summary line for output (implies '-q'). Suppresses file addition warnings if '-y' is also used. Does not contradict '-v'. Inclusion filters are ignored.")
@@ -174,6 +204,35 @@ func ParseFlags() {
 
 	flag.BoolVar(&Flags.UsageOptOut, "usage-opt-out", false, "Decline to send anonymous New Relic Diagnostic tool usage data to New Relic for this run")
 
+	flag.StringVar(&Flags.Verify, "verify", defaultString, "Verify the files in a previously created nrdiag-output.zip against the SHA-256 checksums in its manifest, e.g. '-verify nrdiag-output.zip'")
+
+	flag.StringVar(&Flags.Format, "format", defaultString, "Additional output formats to write next to nrdiag-output.json. Accepted values: markdown (writes nrdiag-summary.md) and openmetrics (writes nrdiag.prom for the node_exporter textfile collector). Multiple values can be provided in a comma separated list.")
+	flag.StringVar(&Flags.Template, "template", defaultString, "Path to a Go template file used instead of the built-in template when writing the markdown summary. Implies '-format markdown'")
+
+	flag.BoolVar(&Flags.ReportEvents, "report-events", false, "Post one NrDiagResult custom event per task result to the New Relic Event API so results can be queried with NRQL. Requires -events-account-id")
+	flag.StringVar(&Flags.EventsAccountID, "events-account-id", defaultString, "New Relic account ID to report events to when using -report-events")
+	flag.StringVar(&Flags.EventsKey, "events-key", defaultString, "Insert key used with -report-events. Defaults to the license key found by the Base/Config/ValidateLicenseKey task")
+	flag.StringVar(&Flags.EventsEndpoint, "events-endpoint", defaultString, "Override the Event API URL used with -report-events, e.g. to test against a local server. Defaults to the endpoint for the detected region. An endpoint outside New Relic requires -events-key")
+
+	flag.StringVar(&Flags.Serve, "serve", defaultString, "Run as an HTTP server on the given address exposing an API to list tasks and suites, start runs and download their results, e.g. '127.0.0.1:8765'. Addresses reachable from other hosts, such as ':8765', require -serve-tls-cert and -serve-tls-key")
+	flag.StringVar(&Flags.ServeToken, "serve-token", defaultString, "Token clients must send as 'Authorization: Bearer <token>' when using -serve. Defaults to the NRDIAG_SERVE_TOKEN environment variable, or a generated token saved to nrdiag-serve/serve-token in the output directory")
+
+	flag.StringVar(&Flags.ServeTLSCert, "serve-tls-cert", defaultString, "PEM certificate file to serve the -serve API over HTTPS with, which allows listening on addresses reachable from other hosts. Requires -serve-tls-key")
+	flag.StringVar(&Flags.ServeTLSKey, "serve-tls-key", defaultString, "PEM private key file of the -serve-tls-cert certificate")
+
+	flag.DurationVar(&Flags.Watch, "watch", 0, "Re-run the selected tasks on this interval (e.g. '5m') and print only the tasks whose status changed. The results of every interval are included in nrdiag-output.zip and each change is appended to nrdiag-watch-history.json. Stop with Ctrl+C")
+	flag.IntVar(&Flags.WatchCount, "watch-count", 0, "Number of runs to do with -watch before finishing. Defaults to running until interrupted")
+
+	flag.StringVar(&Flags.Root, "root", defaultString, "Diagnose the host whose filesystem is mounted at this directory, e.g. '-root /host' when running in a container. Files, configs, logs and processes (through <root>/proc) are looked up under it")
+
+	flag.BoolVar(&Flags.ApplyFixes, "apply-fixes", false, "Apply the config fixes suggested by the tasks, which are always written as unified diffs to the nrdiag-fixes directory. Each diff is shown and must be confirmed, even with -y, and the original file is kept next to it with a .nrdiag-backup extension")
+
+	flag.Var((*pidList)(&Flags.Pids), "pid", "Only diagnose the process with this PID, e.g. '-pid 1234'. Can be repeated or given a comma separated list. Configs and logs are looked up from the working directory, environment and arguments of these processes")
+
+	flag.BoolVar(&Flags.Offline, "offline", false, "Run on a host without network access: the tasks that need to reach New Relic or a cloud provider report Skipped instead of waiting for a timeout, and no usage data, version check, upload or event is sent")
+
+	flag.StringVar(&Flags.FailOn, "fail-on", "warning", "Lowest result status that causes a non-zero exit code. Accepted values: Warning, Failure or Error. Exit codes are 10 for Warning, 20 for Failure and 30 for Error, based on the worst status in the filtered results.")
+
 	//if first arg looks like it was build with `go build`, then we are testing against Haberdasher staging or localhost endpoint
 	if strings.Contains(os.Args[0], "newrelic-diagnostics-cli") {
 		flag.StringVar(&Flags.AttachmentEndpoint, "attachment-endpoint", defaultString, "The endpoint to send attachments to. (NR ONLY)")
@@ -188,6 +247,39 @@ func ParseFlags() {
 		os.Exit(1)
 	}
 
+	if !IsValidFailOn(Flags.FailOn) {
+		fmt.Printf("Invalid -fail-on value '%s'. Accepted values: %s\n", Flags.FailOn, strings.Join(failOnValues, ", "))
+		os.Exit(1)
+	}
+
+	if Flags.Watch < 0 || Flags.WatchCount < 0 || (Flags.WatchCount > 0 && Flags.Watch == 0) {
+		fmt.Println("-watch must be a positive interval such as '5m' and -watch-count a positive number of runs to do with -watch")
+		os.Exit(1)
+	}
+
+	// nothing is sent from a host without network access
+	if Flags.Offline {
+		Flags.SkipVersionCheck = true
+		Flags.UsageOptOut = true
+		Flags.ReportEvents = false
+	}
+
+	if Flags.ReportEvents && Flags.EventsAccountID == "" {
+		fmt.Println("An account ID must be provided with -events-account-id when using -report-events")
+		os.Exit(1)
+	}
+
+	if Flags.Template != "" && !Flags.HasFormat("markdown") {
+		Flags.Format = strings.Trim("markdown,"+Flags.Format, ",")
+	}
+
+	for _, format := range strings.Split(Flags.Format, ",") {
+		if format = strings.ToLower(strings.TrimSpace(format)); format != "" && !containsString(formatValues, format) {
+			fmt.Printf("Invalid -format value '%s'. Accepted values: %s\n", format, strings.Join(formatValues, ", "))
+			os.Exit(1)
+		}
+	}
+
 	if Flags.VeryQuiet {
 		Flags.Quiet = true
 
@@ -239,6 +331,61 @@ func (f userFlags) UsagePayload() []ConfigFlag {
 	}
 }
 
+// pidList collects the values of the repeatable -pid flag
+type pidList []int32
+
+func (p *pidList) String() string {
+	if p == nil {
+		return ""
+	}
+	var pids []string
+	for _, pid := range *p {
+		pids = append(pids, strconv.Itoa(int(pid)))
+	}
+	return strings.Join(pids, ",")
+}
+
+func (p *pidList) Set(value string) error {
+	for _, pid := range strings.Split(value, ",") {
+		parsed, err := strconv.ParseInt(strings.TrimSpace(pid), 10, 32)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid PID '%s'", pid)
+		}
+		*p = append(*p, int32(parsed))
+	}
+	return nil
+}
+
+// failOnValues are the statuses accepted by the -fail-on flag, from least to most severe
+var failOnValues = []string{"warning", "failure", "error"}
+
+// formatValues are the output formats accepted by the -format flag
+var formatValues = []string{"markdown", "openmetrics"}
+
+// IsValidFailOn returns true if the supplied value is an accepted -fail-on status
+func IsValidFailOn(value string) bool {
+	return containsString(failOnValues, strings.ToLower(strings.TrimSpace(value)))
+}
+
+// HasFormat returns true if the supplied output format was requested with the -format flag
+func (f userFlags) HasFormat(format string) bool {
+	for _, requested := range strings.Split(f.Format, ",") {
+		if strings.ToLower(strings.TrimSpace(requested)) == strings.ToLower(format) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(slice []string, element string) bool {
+	for _, elem := range slice {
+		if elem == element {
+			return true
+		}
+	}
+	return false
+}
+
 // boolifyFlag is a helper function for falsey/truthy conversion of UserFlag strings
 func boolifyFlag(inputFlag string) bool {
 	if inputFlag == "" {
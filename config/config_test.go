@@ -152,3 +152,48 @@ func Test_boolifyFlag(t *testing.T) {
 		})
 	}
 }
+
+func Test_IsValidFailOn(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"warning", true},
+		{"Failure", true},
+		{" error ", true},
+		{"success", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidFailOn(tt.value); got != tt.want {
+			t.Errorf("IsValidFailOn(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func Test_userFlags_HasFormat(t *testing.T) {
+	f := userFlags{Format: "Markdown, other"}
+	if !f.HasFormat("markdown") || !f.HasFormat("other") {
+		t.Error("Expected requested formats to be found in", f.Format)
+	}
+	if f.HasFormat("json") {
+		t.Error("Did not expect json format to be found in", f.Format)
+	}
+}
+
+func Test_pidList_Set(t *testing.T) {
+	var pids pidList
+	for _, value := range []string{"1234", "42, 43"} {
+		if err := pids.Set(value); err != nil {
+			t.Errorf("Set(%q) error = %v", value, err)
+		}
+	}
+	if got := pids.String(); got != "1234,42,43" {
+		t.Errorf("pidList = %s, want every PID of the repeated flag", got)
+	}
+	for _, value := range []string{"abc", "0", "-5"} {
+		if err := pids.Set(value); err == nil {
+			t.Errorf("Set(%q) expected an error", value)
+		}
+	}
+}
@@ -1,18 +1,25 @@
 package main
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/events"
 	"github.com/newrelic/newrelic-diagnostics-cli/internal/haberdasher"
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/output"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 	"github.com/newrelic/newrelic-diagnostics-cli/usage"
 	"github.com/newrelic/newrelic-diagnostics-cli/version"
 )
 
 func main() {
+	startTime := time.Now()
 	runID := generateRunID()
 	config.ParseFlags()
 	log.Debug("---------------------------------------------------------------------------------------------")
@@ -20,12 +27,28 @@ func main() {
 	log.Debugf("Run ID: %s\n", runID)
 	log.Debug("nrdiag was run with options", os.Args)
 
+	if err := tasks.SetRoot(config.Flags.Root); err != nil {
+		log.Info("Unable to use -root: " + err.Error())
+		os.Exit(exitCodeBadInput)
+	}
+	if config.Flags.Root != "" {
+		log.Infof("Diagnosing the host mounted at %s\n", tasks.GetRoot())
+	}
+	if err := tasks.SetTargetPids(config.Flags.Pids); err != nil {
+		log.Info("Unable to use -pid: " + err.Error())
+		os.Exit(exitCodeBadInput)
+	}
+
+	if config.Flags.Verify != "" {
+		os.Exit(processVerify(config.Flags.Verify))
+	}
+
 	_, err := processHTTPProxy()
 
 	//Error setting proxy and they specifically included one so let's break out of the program before we attempt any non-proxied calls.
 	if err != nil {
 		log.Debug("Proxy configuration found, but unable to use. \nError: " + err.Error() + "\nExiting program.")
-		os.Exit(3)
+		os.Exit(exitCodeProxyError)
 	}
 
 	options, overrides := processOverrides()
@@ -41,7 +64,7 @@ func main() {
 		haberdasher.DefaultClient.SetBaseURL(config.HaberdasherURL)
 	}
 
-	if config.Flags.Version && config.Flags.Quiet {
+	if config.Flags.Version && config.Flags.Quiet && !config.Flags.Offline {
 		// Support for automated version check by newrelic-cli
 		current := version.ProcessAutoVersionCheck()
 		if !current {
@@ -51,7 +74,9 @@ func main() {
 		}
 	}
 
-	go processTasksToRun()
+	if config.Flags.Serve != "" {
+		os.Exit(processServe(config.Flags.Serve, options))
+	}
 
 	// if statments for doing stuff with args
 	if config.Flags.Help {
@@ -59,63 +84,120 @@ func main() {
 	} else if config.Flags.Version {
 		version.ProcessVersion(promptUser)
 	} else if config.Flags.Interactive {
-		// do interactive stuff
+		os.Exit(processInteractive(options, overrides, runID, startTime))
 	} else {
-		// the wait group is way of tracking open threads
-		// anytime you spawn an async function, increment and pass it in
-		// ... the called function is responsible for decrementing when done
-		var wg sync.WaitGroup
+		_, exitCode := processRun(options, overrides, runID, startTime)
+		os.Exit(exitCode)
+	}
+}
 
-		wg.Add(1) // run the tasks in goroutine
-		go processTasks(options, overrides, &wg)
+// processRun runs the tasks selected by the flags and writes and uploads the results. It returns the results and the exit code
+func processRun(options tasks.Options, overrides []override, runID string, startTime time.Time) ([]registration.TaskResult, int) {
+	diagEngine, err := newEngine(config.Flags.Tasks, config.Flags.Suites, os.Args, options, overrides)
+	if err != nil {
+		log.Infof("\nError:\n%s", err.Error())
+		return nil, exitCodeBadInput
+	}
+	// run the tasks in the background
+	diagEngine.Start(context.Background())
 
-		// zip file is passed around as a dependency for other functions
-		zipfile := output.CreateZip()
+	// the wait group is way of tracking open threads
+	// anytime you spawn an async function, increment and pass it in
+	// ... the called function is responsible for decrementing when done
+	var wg sync.WaitGroup
 
-		wg.Add(1) // collect files the tasks produce and add them to the zip file
-		go output.ProcessFilesChannel(zipfile, &wg)
+	// zip file is passed around as a dependency for other functions
+	zipfile := output.CreateZip(config.Flags.OutputPath)
 
-		// this is a synchronous function that reads from the results channel
-		// does not need the wait group since it blocks
-		outputResults := output.WriteLineResults()
+	wg.Add(1) // collect files the tasks produce and add them to the zip file
+	go output.ProcessFilesChannel(zipfile, diagEngine.Files(), &wg)
 
-		if !config.Flags.Quiet {
-			// writes to the screen
-			output.WriteSummary(outputResults)
-		}
+	// this is a synchronous function that reads from the results channel
+	// does not need the wait group since it blocks
+	outputResults := output.WriteLineResults(diagEngine.Results())
 
-		// block on wait group so program does not exit prematurely
-		wg.Wait()
+	if !config.Flags.Quiet {
+		// writes to the screen
+		output.WriteSummary(outputResults)
+	}
+
+	// block on wait group so program does not exit prematurely
+	wg.Wait()
 
-		// creates the output file
-		output.WriteOutputFile(outputResults)
+	// with -watch the results of the last run are written out, while the exit code covers every run
+	exitResults := outputResults
+	if config.Flags.Watch > 0 {
+		outputResults, exitResults = watch(zipfile, options, overrides, outputResults)
+	}
 
-		// copy our output file(s) to the zip file
-		output.CopyOutputToZip(zipfile)
-		// ...and close it out
-		output.CloseZip(zipfile)
+	// creates the output file
+	outputErr := output.WriteOutputFile(config.Flags.OutputPath, outputResults)
+	if config.Flags.HasFormat("markdown") {
+		if err := output.WriteMarkdownSummary(config.Flags.OutputPath, config.Flags.Template, outputResults); err != nil {
+			outputErr = err
+		}
+	}
+	if config.Flags.HasFormat("openmetrics") {
+		if err := output.WriteOpenMetrics(outputResults, time.Since(startTime)); err != nil {
+			outputErr = err
+		}
+	}
 
-		// upload any files (zip and json)
-		processUploads()
+	// suggested config fixes are written as diffs and only applied when asked for
+	fixes, err := output.WriteFixes(outputResults)
+	if err != nil {
+		outputErr = err
+	}
 
-		// deal with haberdasher data
-		if !config.Flags.UsageOptOut {
-			usage.SendUsageData(outputResults, runID)
+	// copy our output file(s) to the zip file
+	output.CopyOutputToZip(zipfile)
+	output.CopyFixesToZip(zipfile, fixes)
+	if config.Flags.Watch > 0 {
+		output.CopySingleFileToZip(zipfile, watchHistoryName)
+	}
+	// ...and close it out
+	output.CloseZip(zipfile)
+
+	if len(fixes) > 0 {
+		if config.Flags.ApplyFixes {
+			output.ApplyFixes(fixes, askUser)
+		} else if !config.Flags.Quiet {
+			log.Info("Suggested config fixes were written to " + filepath.Join(config.Flags.OutputPath, output.FixesDirName) + ". Run with -apply-fixes to review and apply them.\n")
 		}
-		if !config.Flags.SkipVersionCheck {
-			version.ProcessAutoVersionCheck()
+	}
+
+	// upload any files (zip and json)
+	processUploads(outputResults)
+
+	// deal with haberdasher data
+	if !config.Flags.UsageOptOut {
+		usage.SendUsageData(outputResults, runID)
+	}
+	if config.Flags.ReportEvents {
+		if err := events.SendResultEvents(outputResults, runID); err != nil {
+			log.Info("Unable to report results to the New Relic Event API:", err)
+		} else {
+			log.Infof("Reported %d results as %s events\n", len(outputResults), events.EventType)
 		}
+	}
+	if !config.Flags.SkipVersionCheck {
+		version.ProcessAutoVersionCheck()
+	}
 
-		if config.Flags.Suites == "" && config.Flags.Tasks == "" {
-			var command, option string
-			if config.Flags.InNewRelicCLI {
-				command = "newrelic diagnose run"
-				option = "--list-suites"
-			} else {
-				command = os.Args[0]
-				option = "-h suites"
-			}
-			log.Infof("\n\nFor better results, run Diagnostics CLI with the 'suites' option to target a New Relic product. To learn how to use this option, run: '%s %s'\n\n", command, option)
+	if config.Flags.Suites == "" && config.Flags.Tasks == "" {
+		var command, option string
+		if config.Flags.InNewRelicCLI {
+			command = "newrelic diagnose run"
+			option = "--list-suites"
+		} else {
+			command = os.Args[0]
+			option = "-h suites"
 		}
+		log.Infof("\n\nFor better results, run Diagnostics CLI with the 'suites' option to target a New Relic product. To learn how to use this option, run: '%s %s'\n\n", command, option)
+	}
+
+	if outputErr != nil {
+		return outputResults, exitCodeOutputError
 	}
+	return outputResults, getExitCode(exitResults, config.Flags.FailOn)
 }
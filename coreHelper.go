@@ -80,7 +80,11 @@ func promptUser(msg string) bool {
 	if config.Flags.YesToAll {
 		return true
 	}
+	return askUser(msg)
+}
 
+// askUser asks the end user even when -y was given. Rewriting their config files always needs their say so.
+func askUser(msg string) bool {
 	prompt := "Choose 'y' or 'n', then press enter: "
 	yesResponses := []string{"y", "yes"}
 	noResponses := []string{"n", "no"}
@@ -0,0 +1,245 @@
+// Package engine runs the diagnostic tasks and returns their results, so the diagnostics can be used as a library:
+//
+//	results, err := engine.Run(ctx, engine.Options{Suites: []string{"java"}})
+//
+// Every Engine owns its work queue, results and channels, so runs can be repeated in one process. They don't execute at
+// the same time though: the tasks share process wide state, such as the selected suites, tasks.FS, tasks.Processes, the
+// -root and -pid targets and config.Flags, so a run started while another one executes waits for it to complete.
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/suites"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// TaskResult is a task and the result of executing it
+type TaskResult = registration.TaskResult
+
+// Override replaces an option of a single task. The keys Status and Payload replace the result itself and skip executing the task
+type Override struct {
+	Identifier tasks.Identifier
+	Key        string
+	Value      string
+}
+
+// Options select the tasks a run executes. When neither Tasks nor Suites are set all tasks that run by default are executed
+type Options struct {
+	Tasks           []string          // task identifiers, can contain wildcards. Takes precedence over Suites
+	Suites          []string          // suite identifiers as listed by '-h suites'
+	AdditionalTasks []string          // task identifiers queued on top of the selected ones
+	TaskOptions     map[string]string // options passed to every task
+	Overrides       []Override
+	Offline         bool // skip the tasks that need network access, they report the Skipped status. The others get the Offline task option
+}
+
+// Engine executes the tasks selected by its options once
+type Engine struct {
+	options Options
+	suites  []suites.Suite
+	run     *registration.Run
+}
+
+// New creates an engine for the given options. It returns an error if any of the suites doesn't exist
+func New(options Options) (*Engine, error) {
+	e := &Engine{options: options, run: registration.NewRun()}
+	if len(options.Tasks) == 0 && len(options.Suites) > 0 {
+		matchedSuites, unmatchedSuites := suites.DefaultSuiteManager.FindSuitesByIdentifiers(options.Suites)
+		if len(unmatchedSuites) > 0 {
+			return nil, fmt.Errorf("could not find the following task suites: %s", strings.Join(unmatchedSuites, ", "))
+		}
+		e.suites = matchedSuites
+	}
+	return e, nil
+}
+
+// Run executes the tasks selected by the options and returns their results in the order they completed.
+// Files the tasks collect are not kept. If ctx is canceled the results of the tasks completed so far are returned with the context error.
+func Run(ctx context.Context, options Options) ([]TaskResult, error) {
+	e, err := New(options)
+	if err != nil {
+		return nil, err
+	}
+	e.Start(ctx)
+
+	// tasks block until their files and streams are consumed
+	filesDone := make(chan struct{})
+	go func() {
+		defer close(filesDone)
+		for result := range e.Files() {
+			for _, envelope := range result.Result.FilesToCopy {
+				if envelope.Stream != nil {
+					for range envelope.Stream {
+					}
+				}
+			}
+		}
+	}()
+
+	var results []TaskResult
+	for result := range e.Results() {
+		results = append(results, result)
+	}
+	<-filesDone
+	return results, ctx.Err()
+}
+
+// Suites returns the suites selected by the options
+func (e *Engine) Suites() []suites.Suite {
+	return e.suites
+}
+
+// Results receives every task result as it completes, it is closed once the run is done. It must be consumed for the run to progress
+func (e *Engine) Results() <-chan TaskResult {
+	return e.run.ResultsChannel
+}
+
+// Files receives the results that have files to collect, it is closed once the run is done. It must be consumed for the run to progress
+func (e *Engine) Files() <-chan TaskResult {
+	return e.run.FilesChannel
+}
+
+// Result returns the result of a task once the run is done
+func (e *Engine) Result(identifier string) (TaskResult, bool) {
+	result, ok := e.run.Results[identifier]
+	return result, ok
+}
+
+// Start queues the selected tasks and executes them in the background. It waits for the run of any other Engine to
+// complete first. Canceling ctx stops the run once the task currently executing completes.
+func (e *Engine) Start(ctx context.Context) {
+	runMutex.Lock()
+	e.queueTasks()
+	go e.executeTasks(ctx)
+}
+
+// runMutex is held from queueing the tasks of a run until its last task completes, see the package doc
+var runMutex sync.Mutex
+
+func (e *Engine) queueTasks() {
+	defer e.run.CompleteTaskRegistration()
+
+	// Base/Agent/EOL picks its dependencies from the suites selected on the default suite manager
+	suites.DefaultSuiteManager.SelectedSuites = e.suites
+
+	if len(e.options.Tasks) > 0 {
+		e.run.AddTasksByIdentifiers(e.options.Tasks)
+	} else if len(e.suites) > 0 {
+		e.run.AddTasksByIdentifiers(suites.DefaultSuiteManager.FindTasksBySuites(e.suites))
+	} else {
+		e.run.AddAllToQueue()
+	}
+	e.run.AddTasksByIdentifiers(e.options.AdditionalTasks)
+}
+
+func (e *Engine) executeTasks(ctx context.Context) {
+	for task := range e.run.WorkQueue {
+		if ctx.Err() != nil {
+			log.Debug("Run canceled, skipping", task.Identifier())
+			continue
+		}
+		taskResult := e.executeTask(task)
+
+		e.run.Results[task.Identifier().String()] = taskResult
+		e.run.ResultsChannel <- taskResult
+
+		if len(taskResult.Result.FilesToCopy) > 0 {
+			log.Debug(" - writing result to file channel")
+			e.run.FilesChannel <- taskResult
+		}
+	}
+
+	log.Debug("Closing task channel")
+	runMutex.Unlock()
+	close(e.run.ResultsChannel)
+	close(e.run.FilesChannel)
+}
+
+func (e *Engine) executeTask(task tasks.Task) TaskResult {
+	// copy the options so a task never sees the overrides of another one
+	namedTaskOptions := tasks.Options{Options: make(map[string]string)}
+	for key, value := range e.options.TaskOptions {
+		namedTaskOptions.Options[key] = value
+	}
+	// tasks that only need the network for part of their checks skip that part
+	if e.options.Offline {
+		namedTaskOptions.Options["Offline"] = "true"
+	}
+
+	// Check for dependancies on the task and include results if dependent
+	dependentResults := make(map[string]tasks.Result)
+	// use the dependencies resolved when queueing, they can depend on the selected suites
+	for _, depIdent := range e.run.Dependencies(task) {
+		log.Debug("dependency for processing: ", depIdent)
+		dependentResults[depIdent] = e.run.Results[depIdent].Result
+	}
+
+	for _, override := range e.options.Overrides {
+		if strings.EqualFold(override.Identifier.String(), task.Identifier().String()) {
+			log.Debug("Adding override to task namedTaskOptions", override.Key, ":", override.Value)
+			namedTaskOptions.Options[override.Key] = override.Value
+		}
+	}
+
+	log.Debug("Starting", task.Identifier(), "with options", namedTaskOptions)
+	var result tasks.Result
+	// Check for an option key to map to Status or Payload and if so, bypass task execution
+	overrideEnabled := false
+	if status, ok := namedTaskOptions.Options["Status"]; ok {
+		log.Debug("Override Status passed in for ", task.Identifier(), "Value of ", status)
+
+		switch strings.ToLower(status) {
+		case "success":
+			result.Status = tasks.Success
+		case "warning":
+			result.Status = tasks.Warning
+		case "failure":
+			result.Status = tasks.Failure
+		case "info":
+			result.Status = tasks.Info
+		case "error":
+			result.Status = tasks.Error
+		case "none":
+			result.Status = tasks.None
+		case "skipped":
+			result.Status = tasks.Skipped
+		default:
+			log.Info("Attempted to set status override to invalid status", status)
+		}
+
+		result.Summary += "Status set by override to " + status + "\n"
+		overrideEnabled = true
+	}
+
+	if payload, ok := namedTaskOptions.Options["Payload"]; ok {
+		log.Debug("Override Payload passed in for ", task.Identifier())
+		result.Payload = payload
+		result.Summary += "Payload set by override\n"
+		overrideEnabled = true
+	}
+
+	startTime := time.Now()
+	if !overrideEnabled && e.options.Offline && tasks.RequiresNetwork(task) {
+		log.Debug("Offline, skipping", task.Identifier())
+		result = tasks.Result{
+			Status:  tasks.Skipped,
+			Summary: "Skipped: offline. This task needs network access and -offline was used.",
+		}
+	} else if !overrideEnabled {
+		result = task.Execute(namedTaskOptions, dependentResults)
+	}
+
+	return TaskResult{
+		Task:        task,
+		Result:      result,
+		WasOverride: overrideEnabled,
+		Duration:    time.Since(startTime),
+	}
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+func TestRun(t *testing.T) {
+	options := Options{
+		Tasks: []string{"Base/Env/CollectEnvVars"},
+		Overrides: []Override{
+			{Identifier: tasks.IdentifierFromString("Base/Env/CollectEnvVars"), Key: "Status", Value: "warning"},
+		},
+	}
+
+	results, err := Run(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Run() returned %d results, want 1", len(results))
+	}
+	if results[0].Result.Status != tasks.Warning || !results[0].WasOverride {
+		t.Errorf("Run() result = %+v, want the overridden Warning status", results[0])
+	}
+}
+
+func TestRun_dependenciesRunFirst(t *testing.T) {
+	options := Options{
+		Tasks: []string{"Base/Config/Validate"},
+		Overrides: []Override{
+			{Identifier: tasks.IdentifierFromString("Base/Config/Validate"), Key: "Status", Value: "success"},
+		},
+	}
+
+	results, err := Run(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) < 2 {
+		t.Fatalf("Run() returned %d results, want Base/Config/Validate and its dependencies", len(results))
+	}
+	if last := results[len(results)-1].Task.Identifier().String(); last != "Base/Config/Validate" {
+		t.Errorf("last result is %s, want Base/Config/Validate after its dependencies", last)
+	}
+}
+
+func TestRun_concurrentCallsRunOneAtATime(t *testing.T) {
+	statuses := []string{"success", "failure", "info"}
+	want := []tasks.Status{tasks.Success, tasks.Failure, tasks.Info}
+	got := make([]tasks.Status, len(statuses))
+
+	var wg sync.WaitGroup
+	for i, status := range statuses {
+		wg.Add(1)
+		go func(i int, status string) {
+			defer wg.Done()
+			results, err := Run(context.Background(), Options{
+				Tasks:     []string{"Base/Env/CollectEnvVars"},
+				Overrides: []Override{{Identifier: tasks.IdentifierFromString("Base/Env/CollectEnvVars"), Key: "Status", Value: status}},
+			})
+			if err != nil || len(results) != 1 {
+				t.Errorf("Run() = %d results, error %v", len(results), err)
+				return
+			}
+			got[i] = results[0].Result.Status
+		}(i, status)
+	}
+	wg.Wait()
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("run %d got status %s, want %s", i, got[i].StatusToString(), want[i].StatusToString())
+		}
+	}
+}
+
+func TestRun_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := Run(ctx, Options{Tasks: []string{"Base/Config/Validate"}})
+	if err != context.Canceled {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if len(results) != 0 {
+		t.Errorf("Run() returned %d results after being canceled, want 0", len(results))
+	}
+}
+
+func TestNew_unknownSuite(t *testing.T) {
+	if _, err := New(Options{Suites: []string{"java", "cobol"}}); err == nil {
+		t.Error("New() expected an error for an unknown suite")
+	}
+}
+
+func TestRun_offline(t *testing.T) {
+	results, err := Run(context.Background(), Options{
+		Tasks:   []string{"Base/Collector/ConnectUS", "Base/Env/CollectEnvVars"},
+		Offline: true,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	statuses := make(map[string]tasks.Status)
+	for _, result := range results {
+		statuses[result.Task.Identifier().String()] = result.Result.Status
+	}
+	if statuses["Base/Collector/ConnectUS"] != tasks.Skipped {
+		t.Errorf("Base/Collector/ConnectUS status = %s, want Skipped", statuses["Base/Collector/ConnectUS"].StatusToString())
+	}
+	if statuses["Base/Env/CollectEnvVars"] == tasks.Skipped {
+		t.Error("Base/Env/CollectEnvVars was skipped, but it doesn't need network access")
+	}
+}
+
+func TestRun_offlineTaskOption(t *testing.T) {
+	results, err := Run(context.Background(), Options{
+		Tasks:   []string{"Base/Config/ValidateLicenseKey"},
+		Offline: true,
+		// the license keys are replaced so the run doesn't depend on the host
+		Overrides: []Override{{
+			Identifier: tasks.IdentifierFromString("Base/Config/LicenseKey"),
+			Key:        "Status",
+			Value:      "None",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	statuses := make(map[string]tasks.Status)
+	for _, result := range results {
+		statuses[result.Task.Identifier().String()] = result.Result.Status
+	}
+	if status, ok := statuses["Base/Config/ValidateLicenseKey"]; !ok || status == tasks.Skipped {
+		t.Error("Base/Config/ValidateLicenseKey was skipped, but only its account lookup needs network access")
+	}
+}
@@ -0,0 +1,237 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/helpers/httpHelper"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/env"
+)
+
+// EventType is the New Relic event type the results are recorded as, e.g. `SELECT * FROM NrDiagResult`
+const EventType = "NrDiagResult"
+
+const defaultRegion = "us01"
+
+// eventAPIHosts maps the regions reported by Base/Config/RegionDetect to their Event API host
+var eventAPIHosts = map[string]string{
+	"us01": "https://insights-collector.newrelic.com",
+	"eu01": "https://insights-collector.eu01.nr-data.net",
+}
+
+// RequiredTasks are the tasks whose results are needed to send events, they are added to the queue when -report-events is used
+var RequiredTasks = []string{
+	"Base/Config/ProxyDetect",
+	"Base/Config/RegionDetect",
+	"Base/Config/ValidateLicenseKey",
+	"Base/Env/HostInfo",
+}
+
+// newRelicDomains are the domains of the New Relic Event API hosts, the only ones a license key found on the host is sent to
+var newRelicDomains = []string{".newrelic.com", ".nr-data.net"}
+
+// resultEvent is a single NrDiagResult event, attribute names are what shows up in NRQL
+type resultEvent struct {
+	EventType     string `json:"eventType"`
+	Identifier    string `json:"identifier"`
+	Status        string `json:"status"`
+	Summary       string `json:"summary"`
+	URL           string `json:"url,omitempty"`
+	Host          string `json:"host"`
+	RunID         string `json:"runId"`
+	AgentVersions string `json:"agentVersions,omitempty"`
+	NRDiagVersion string `json:"nrdiagVersion"`
+}
+
+// The Event API truncates string attributes to 4096 characters
+const maxAttributeLength = 4096
+
+// SendResultEvents posts one NrDiagResult event per task result to the Event API of the region detected for this host.
+// The request goes through httpHelper so any proxy set by -proxy or Base/Config/ProxyDetect is used.
+func SendResultEvents(results []registration.TaskResult, runID string) error {
+	if config.Flags.EventsAccountID == "" {
+		return errors.New("an account ID is required to report events, use -events-account-id")
+	}
+
+	key, keyHeader := getAPIKey(results)
+	if key == "" {
+		return errors.New("no key found to report events, use -events-key or make sure a valid license key is found by Base/Config/ValidateLicenseKey")
+	}
+
+	endpoint := getEndpoint(results)
+	if config.Flags.EventsKey == "" && !isNewRelicEndpoint(endpoint) {
+		return errors.New("not sending the license key found on this host to " + endpoint + ", use -events-key to report events to an endpoint outside New Relic")
+	}
+	payload, err := json.Marshal(prepareEvents(results, runID))
+	if err != nil {
+		return err
+	}
+
+	wrapper := httpHelper.RequestWrapper{
+		Method:  "POST",
+		URL:     endpoint,
+		Payload: bytes.NewReader(payload),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			keyHeader:      key,
+		},
+		TimeoutSeconds: 15,
+	}
+
+	log.Debug("Sending", len(results), "events to", endpoint)
+	res, err := httpHelper.MakeHTTPRequest(wrapper)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("unexpected status code %d from the Event API: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// getAPIKey returns the key to send events with and the header it goes in. An insert key passed with -events-key
+// takes precedence over the license keys found on the host.
+func getAPIKey(results []registration.TaskResult) (string, string) {
+	if config.Flags.EventsKey != "" {
+		return config.Flags.EventsKey, "X-Insert-Key"
+	}
+
+	for _, result := range results {
+		if result.Task.Identifier().String() != "Base/Config/ValidateLicenseKey" || result.Result.Status != tasks.Success {
+			continue
+		}
+		licenseKeyToSources, ok := result.Result.Payload.(map[string][]string)
+		if !ok {
+			continue
+		}
+		licenseKeys := []string{}
+		for lk := range licenseKeyToSources {
+			licenseKeys = append(licenseKeys, lk)
+		}
+		if len(licenseKeys) > 1 {
+			log.Debug("Multiple license keys found, reporting events with the first one")
+		}
+		if len(licenseKeys) > 0 {
+			sort.Strings(licenseKeys)
+			return licenseKeys[0], "Api-Key"
+		}
+	}
+	return "", ""
+}
+
+// getEndpoint returns the Event API URL for the account, using the region reported by Base/Config/RegionDetect
+func getEndpoint(results []registration.TaskResult) string {
+	if config.Flags.EventsEndpoint != "" {
+		return config.Flags.EventsEndpoint
+	}
+
+	region := defaultRegion
+	for _, result := range results {
+		if result.Task.Identifier().String() != "Base/Config/RegionDetect" || result.Result.Status != tasks.Info {
+			continue
+		}
+		regions, ok := result.Result.Payload.([]string)
+		if !ok || len(regions) == 0 {
+			continue
+		}
+		if len(regions) > 1 {
+			log.Debug("Multiple regions detected, reporting events to", regions[0])
+		}
+		region = regions[0]
+	}
+
+	host, ok := eventAPIHosts[region]
+	if !ok {
+		log.Debug("No Event API host known for region", region, "- falling back to", defaultRegion)
+		host = eventAPIHosts[defaultRegion]
+	}
+	return host + "/v1/accounts/" + config.Flags.EventsAccountID + "/events"
+}
+
+// isNewRelicEndpoint checks the endpoint is an HTTPS URL on a New Relic domain
+func isNewRelicEndpoint(endpoint string) bool {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, domain := range newRelicDomains {
+		if strings.HasSuffix(host, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func prepareEvents(results []registration.TaskResult, runID string) []resultEvent {
+	host := getHostname(results)
+	agentVersions := getAgentVersions(results)
+
+	events := []resultEvent{}
+	for _, result := range results {
+		events = append(events, resultEvent{
+			EventType:     EventType,
+			Identifier:    result.Task.Identifier().String(),
+			Status:        result.Result.StatusToString(),
+			Summary:       limitLength(strings.TrimSpace(result.Result.Summary), maxAttributeLength),
+			URL:           result.Result.URL,
+			Host:          host,
+			RunID:         runID,
+			AgentVersions: agentVersions,
+			NRDiagVersion: config.Version,
+		})
+	}
+	return events
+}
+
+// getHostname prefers the hostname collected by Base/Env/HostInfo and falls back to the OS
+func getHostname(results []registration.TaskResult) string {
+	for _, result := range results {
+		if result.Task.Identifier().String() != "Base/Env/HostInfo" {
+			continue
+		}
+		if hostInfo, ok := result.Result.Payload.(env.HostInfo); ok && hostInfo.Hostname != "" {
+			return hostInfo.Hostname
+		}
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// getAgentVersions lists the versions reported by every */Agent/Version task, e.g. "Java 6.4.0, Ruby 7.0.0"
+func getAgentVersions(results []registration.TaskResult) string {
+	versions := []string{}
+	for _, result := range results {
+		identifier := result.Task.Identifier()
+		if identifier.Subcategory != "Agent" || identifier.Name != "Version" || result.Result.Status != tasks.Info || result.Result.Payload == nil {
+			continue
+		}
+		version := strings.Trim(fmt.Sprint(result.Result.Payload), "[]")
+		if version != "" {
+			versions = append(versions, identifier.Category+" "+version)
+		}
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, ", ")
+}
+
+func limitLength(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
@@ -0,0 +1,152 @@
+package events
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/env"
+)
+
+func taskResult(identifier string, result tasks.Result) registration.TaskResult {
+	return registration.TaskResult{Task: registration.TasksForIdentifierString(identifier)[0], Result: result}
+}
+
+var sampleResults = []registration.TaskResult{
+	taskResult("Base/Env/HostInfo", tasks.Result{Status: tasks.Info, Summary: "Collected host information", Payload: env.HostInfo{Hostname: "web-01"}}),
+	taskResult("Base/Config/ValidateLicenseKey", tasks.Result{Status: tasks.Success, Payload: map[string][]string{"eu01xx0000000000000000000000000000000000": {"newrelic.yml"}}}),
+	taskResult("Base/Config/RegionDetect", tasks.Result{Status: tasks.Info, Payload: []string{"eu01"}}),
+	taskResult("Java/Agent/Version", tasks.Result{Status: tasks.Info, Payload: "6.4.0"}),
+	taskResult("Ruby/Agent/Version", tasks.Result{Status: tasks.Info, Payload: []tasks.Ver{{Major: 7, Minor: 0, Patch: 0, Build: 0}}}),
+	taskResult("Base/Collector/ConnectUS", tasks.Result{Status: tasks.Failure, Summary: "  Unable to connect\n", URL: "https://docs.newrelic.com/networks"}),
+}
+
+func withEventFlags(accountID string, key string, endpoint string) func() {
+	previous := config.Flags
+	config.Flags.EventsAccountID = accountID
+	config.Flags.EventsKey = key
+	config.Flags.EventsEndpoint = endpoint
+	return func() { config.Flags = previous }
+}
+
+func Test_SendResultEvents(t *testing.T) {
+	var receivedHeader http.Header
+	var receivedEvents []resultEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeader = r.Header
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedEvents)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+	defer withEventFlags("12345", "insert-key", server.URL)()
+
+	err := SendResultEvents(sampleResults, "run-1")
+	if err != nil {
+		t.Fatal("Unexpected error sending events:", err)
+	}
+
+	if receivedHeader.Get("X-Insert-Key") != "insert-key" {
+		t.Error("Expected the insert key in the X-Insert-Key header, got headers", receivedHeader)
+	}
+	if len(receivedEvents) != len(sampleResults) {
+		t.Fatalf("Expected %d events, received %d", len(sampleResults), len(receivedEvents))
+	}
+	expected := resultEvent{
+		EventType:     "NrDiagResult",
+		Identifier:    "Base/Collector/ConnectUS",
+		Status:        "Failure",
+		Summary:       "Unable to connect",
+		URL:           "https://docs.newrelic.com/networks",
+		Host:          "web-01",
+		RunID:         "run-1",
+		AgentVersions: "Java 6.4.0, Ruby 7.0.0.0",
+	}
+	if !reflect.DeepEqual(receivedEvents[5], expected) {
+		t.Errorf("Expected event %+v, received %+v", expected, receivedEvents[5])
+	}
+}
+
+func Test_SendResultEventsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"invalid key"}`))
+	}))
+	defer server.Close()
+	defer withEventFlags("12345", "insert-key", server.URL)()
+
+	err := SendResultEvents(sampleResults, "run-1")
+	if err == nil || err.Error() != `unexpected status code 403 from the Event API: {"error":"invalid key"}` {
+		t.Error("Expected an error for a rejected request, got", err)
+	}
+
+	config.Flags.EventsKey = ""
+	if err := SendResultEvents(sampleResults[:1], "run-1"); err == nil {
+		t.Error("Expected an error when no key is available")
+	}
+	if err := SendResultEvents(sampleResults, "run-1"); err == nil {
+		t.Error("Expected an error sending the license key to an endpoint outside New Relic")
+	}
+
+	config.Flags.EventsAccountID = ""
+	if err := SendResultEvents(sampleResults, "run-1"); err == nil {
+		t.Error("Expected an error when no account ID is set")
+	}
+}
+
+func Test_getEndpoint(t *testing.T) {
+	defer withEventFlags("12345", "", "")()
+
+	tests := []struct {
+		name    string
+		results []registration.TaskResult
+		want    string
+	}{
+		{"EU region detected", sampleResults, "https://insights-collector.eu01.nr-data.net/v1/accounts/12345/events"},
+		{"no region detected", sampleResults[:1], "https://insights-collector.newrelic.com/v1/accounts/12345/events"},
+		{"unknown region detected", []registration.TaskResult{taskResult("Base/Config/RegionDetect", tasks.Result{Status: tasks.Info, Payload: []string{"xx99"}})}, "https://insights-collector.newrelic.com/v1/accounts/12345/events"},
+	}
+	for _, tt := range tests {
+		if got := getEndpoint(tt.results); got != tt.want {
+			t.Errorf("Test %v failed: getEndpoint() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_getAPIKey(t *testing.T) {
+	defer withEventFlags("12345", "insert-key", "")()
+
+	key, header := getAPIKey(sampleResults)
+	if key != "insert-key" || header != "X-Insert-Key" {
+		t.Errorf("Expected the -events-key insert key, got %s: %s", header, key)
+	}
+
+	config.Flags.EventsKey = ""
+	key, header = getAPIKey(sampleResults)
+	if key != "eu01xx0000000000000000000000000000000000" || header != "Api-Key" {
+		t.Errorf("Expected the license key, got %s: %s", header, key)
+	}
+}
+
+func Test_isNewRelicEndpoint(t *testing.T) {
+	tests := map[string]bool{
+		"https://insights-collector.newrelic.com/v1/accounts/1/events":       true,
+		"https://insights-collector.eu01.nr-data.net/v1/accounts/1/events":   true,
+		"http://insights-collector.newrelic.com/v1/accounts/1/events":        false,
+		"https://newrelic.com.example.com/v1/accounts/1/events":              false,
+		"https://example.com/insights-collector.newrelic.com/v1/accounts/1/": false,
+		"http://127.0.0.1:8080/v1/accounts/1/events":                         false,
+	}
+	for endpoint, expected := range tests {
+		if isNewRelicEndpoint(endpoint) != expected {
+			t.Errorf("isNewRelicEndpoint(%q) should be %v", endpoint, expected)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/output"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// Exit codes below 10 are reserved for nrdiag's own failures so scripts can tell them apart from
+// the exit codes derived from task results
+const (
+	exitCodeSuccess     = 0
+	exitCodeBadInput    = 1 // invalid flags or arguments
+	exitCodeProxyError  = 3
+	exitCodeOutputError = 4 // unable to write the output files
+	exitCodeVerifyError = 5 // -verify found files that don't match the manifest
+	exitCodeServeError  = 6 // -serve could not listen on the address or the server stopped
+
+	exitCodeWarning = 10
+	exitCodeFailure = 20
+	exitCodeError   = 30
+)
+
+// statusSeverity ranks the statuses that can produce a non-zero exit code. None, Success and Info are never a problem.
+func statusSeverity(status tasks.Status) int {
+	switch status {
+	case tasks.Warning:
+		return 1
+	case tasks.Failure:
+		return 2
+	case tasks.Error:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// worstStatus returns the most severe status found in the results
+func worstStatus(results []registration.TaskResult) tasks.Status {
+	worst := tasks.None
+	for _, result := range results {
+		if statusSeverity(result.Result.Status) > statusSeverity(worst) {
+			worst = result.Result.Status
+		}
+	}
+	return worst
+}
+
+// failOnStatus converts the -fail-on flag value to the status used as exit code threshold
+func failOnStatus(failOn string) tasks.Status {
+	switch strings.ToLower(strings.TrimSpace(failOn)) {
+	case "failure":
+		return tasks.Failure
+	case "error":
+		return tasks.Error
+	default:
+		return tasks.Warning
+	}
+}
+
+// getExitCode returns the exit code for a completed run based on the worst status of the filtered results.
+// Results less severe than the -fail-on threshold exit with 0.
+func getExitCode(results []registration.TaskResult, failOn string) int {
+	worst := worstStatus(output.FilterResults(results))
+	if statusSeverity(worst) < statusSeverity(failOnStatus(failOn)) {
+		return exitCodeSuccess
+	}
+
+	switch worst {
+	case tasks.Warning:
+		return exitCodeWarning
+	case tasks.Failure:
+		return exitCodeFailure
+	case tasks.Error:
+		return exitCodeError
+	default:
+		return exitCodeSuccess
+	}
+}
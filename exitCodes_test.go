@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+func resultsWithStatuses(statuses ...tasks.Status) []registration.TaskResult {
+	var results []registration.TaskResult
+	for _, status := range statuses {
+		results = append(results, registration.TaskResult{Result: tasks.Result{Status: status}})
+	}
+	return results
+}
+
+func Test_getExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []registration.TaskResult
+		failOn  string
+		filter  string
+		want    int
+	}{
+		{"no results", nil, "warning", "", exitCodeSuccess},
+		{"only success and info", resultsWithStatuses(tasks.Success, tasks.Info, tasks.None), "warning", "", exitCodeSuccess},
+		{"worst is warning", resultsWithStatuses(tasks.Success, tasks.Warning), "warning", "", exitCodeWarning},
+		{"worst is failure", resultsWithStatuses(tasks.Warning, tasks.Failure, tasks.Info), "warning", "", exitCodeFailure},
+		{"worst is error", resultsWithStatuses(tasks.Error, tasks.Failure, tasks.Warning), "Warning", "", exitCodeError},
+		{"warning below failure threshold", resultsWithStatuses(tasks.Warning), "failure", "", exitCodeSuccess},
+		{"failure meets failure threshold", resultsWithStatuses(tasks.Warning, tasks.Failure), "failure", "", exitCodeFailure},
+		{"failure below error threshold", resultsWithStatuses(tasks.Failure), "error", "", exitCodeSuccess},
+		{"filtered out failure is ignored", resultsWithStatuses(tasks.Warning, tasks.Failure), "warning", "success,warning", exitCodeWarning},
+	}
+	defer func(filter string) { config.Flags.Filter = filter }(config.Flags.Filter)
+	for _, tt := range tests {
+		config.Flags.Filter = tt.filter
+		if got := getExitCode(tt.results, tt.failOn); got != tt.want {
+			t.Errorf("Test %v failed: getExitCode() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
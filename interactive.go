@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/engine"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/suites"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	baseConfig "github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+)
+
+// agentDetectionTasks are run before anything else in interactive mode to find out which agents are installed
+const agentDetectionTasks = "*/Config/Agent"
+
+// interactiveWizard walks the user through picking suites, reviewing secure files and browsing the results
+type interactiveWizard struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+func newInteractiveWizard(in io.Reader, out io.Writer) *interactiveWizard {
+	return &interactiveWizard{in: bufio.NewScanner(in), out: out}
+}
+
+// processInteractive runs the guided mode and returns the exit code of the diagnostics run
+func processInteractive(options tasks.Options, overrides []override, runID string, startTime time.Time) int {
+	wizard := newInteractiveWizard(os.Stdin, os.Stdout)
+
+	fmt.Fprintln(wizard.out, "Looking for installed New Relic agents...")
+	categories, detectionResults := detectAgents(options)
+	if len(categories) > 0 {
+		fmt.Fprintf(wizard.out, "Found: %s\n", strings.Join(categories, ", "))
+	} else {
+		fmt.Fprintln(wizard.out, "No New Relic agent configuration was found.")
+	}
+
+	selectedSuites := wizard.chooseSuites(proposeSuites(categories, suites.DefaultSuiteManager.Suites), suites.DefaultSuiteManager.Suites)
+	var suiteIdentifiers []string
+	for _, suite := range selectedSuites {
+		suiteIdentifiers = append(suiteIdentifiers, suite.Identifier)
+	}
+	config.Flags.Suites = strings.Join(suiteIdentifiers, ",")
+
+	// the environment collected during detection tells where the Windows install folders are
+	var envVars map[string]string
+	for _, result := range detectionResults {
+		if result.Task.Identifier().String() == "Base/Env/CollectEnvVars" {
+			envVars, _ = result.Result.Payload.(map[string]string)
+		}
+	}
+	secureFiles := wizard.chooseSecureFiles(baseConfig.FindSecureFiles(envVars))
+	options.Options[baseConfig.SecureFilesOption] = strings.Join(secureFiles, "\n")
+
+	results, exitCode := processRun(options, overrides, runID, startTime)
+
+	wizard.browseResults(sortedResults(results))
+	return exitCode
+}
+
+// detectAgents silently runs the */Config/Agent tasks and returns the categories of the agents found, along with the results of the detection tasks
+func detectAgents(options tasks.Options) ([]string, []registration.TaskResult) {
+	detectionOptions := engine.Options{
+		Tasks:       []string{agentDetectionTasks},
+		TaskOptions: make(map[string]string),
+	}
+	for key, value := range options.Options {
+		detectionOptions.TaskOptions[key] = value
+	}
+	// secure files are reviewed later, don't prompt for them while detecting
+	detectionOptions.TaskOptions[baseConfig.SecureFilesOption] = ""
+
+	results, err := engine.Run(context.Background(), detectionOptions)
+	if err != nil {
+		log.Debug("Error detecting agents:", err)
+	}
+
+	var categories []string
+	for _, result := range results {
+		identifier := result.Task.Identifier()
+		if identifier.Subcategory == "Config" && identifier.Name == "Agent" && result.Result.Status == tasks.Success {
+			categories = append(categories, identifier.Category)
+		}
+	}
+	sort.Strings(categories)
+	return categories, results
+}
+
+// proposeSuites returns the first suite running all the tasks of each detected agent category
+func proposeSuites(categories []string, allSuites []suites.Suite) []suites.Suite {
+	var proposed []suites.Suite
+	for _, category := range categories {
+		for _, suite := range allSuites {
+			if suiteCoversCategory(suite, category) {
+				proposed = append(proposed, suite)
+				break
+			}
+		}
+	}
+	return proposed
+}
+
+func suiteCoversCategory(suite suites.Suite, category string) bool {
+	for _, task := range suite.Tasks {
+		if strings.EqualFold(task, category+"/*") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSelection turns input like "1,3" or "all" into indexes of a list of the given length.
+// Numbers start at 1 as displayed to the user, the returned indexes start at 0.
+func parseSelection(input string, count int) ([]int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "a" || input == "all" {
+		var all []int
+		for i := 0; i < count; i++ {
+			all = append(all, i)
+		}
+		return all, nil
+	}
+
+	var indexes []int
+	seen := make(map[int]bool)
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 1 || number > count {
+			return nil, fmt.Errorf("'%s' is not a number between 1 and %d", field, count)
+		}
+		if !seen[number-1] {
+			seen[number-1] = true
+			indexes = append(indexes, number-1)
+		}
+	}
+	return indexes, nil
+}
+
+// ask prints the question and returns the trimmed answer, or "q" if the input is closed
+func (w *interactiveWizard) ask(question string) string {
+	fmt.Fprint(w.out, question)
+	if !w.in.Scan() {
+		fmt.Fprintln(w.out)
+		return "q"
+	}
+	return strings.TrimSpace(w.in.Text())
+}
+
+// chooseSuites lets the user accept the proposed suites or pick others. An empty selection runs all default tasks
+func (w *interactiveWizard) chooseSuites(proposed []suites.Suite, allSuites []suites.Suite) []suites.Suite {
+	if len(proposed) > 0 {
+		fmt.Fprintln(w.out, "\nSuggested task suites:")
+		for _, suite := range proposed {
+			fmt.Fprintf(w.out, "  - %s (%s)\n", suite.DisplayName, suite.Identifier)
+		}
+		answer := strings.ToLower(w.ask("Run these suites? Choose 'y' to accept or 'n' to pick others, then press enter: "))
+		if answer == "" || answer == "y" || answer == "yes" || answer == "q" {
+			return proposed
+		}
+	}
+
+	fmt.Fprintln(w.out, "\nAvailable task suites:")
+	for i, suite := range allSuites {
+		fmt.Fprintf(w.out, "  %2d) %s (%s)\n", i+1, suite.DisplayName, suite.Identifier)
+	}
+	for {
+		answer := w.ask("Enter the numbers of the suites to run separated by commas, or press enter to run all default tasks: ")
+		if answer == "q" {
+			return nil
+		}
+		indexes, err := parseSelection(answer, len(allSuites))
+		if err != nil {
+			fmt.Fprintln(w.out, err.Error())
+			continue
+		}
+		var selected []suites.Suite
+		for _, index := range indexes {
+			selected = append(selected, allSuites[index])
+		}
+		return selected
+	}
+}
+
+// chooseSecureFiles lists the files that may contain secure information and returns the ones the user agrees to include
+func (w *interactiveWizard) chooseSecureFiles(secureFiles []string) []string {
+	if len(secureFiles) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w.out, "\nThese files may contain secure information:")
+	for i, file := range secureFiles {
+		fmt.Fprintf(w.out, "  %2d) %s\n", i+1, file)
+	}
+	for {
+		answer := w.ask("Enter the numbers of the files to include in nrdiag-output.zip separated by commas, 'a' for all, or press enter for none: ")
+		if answer == "q" {
+			return nil
+		}
+		indexes, err := parseSelection(answer, len(secureFiles))
+		if err != nil {
+			fmt.Fprintln(w.out, err.Error())
+			continue
+		}
+		var selected []string
+		for _, index := range indexes {
+			selected = append(selected, secureFiles[index])
+		}
+		return selected
+	}
+}
+
+func sortedResults(results []registration.TaskResult) []registration.TaskResult {
+	sorted := make([]registration.TaskResult, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Task.Identifier().String() < sorted[j].Task.Identifier().String()
+	})
+	return sorted
+}
+
+// browseResults lists the results and shows the summary, URL and payload of the ones the user picks until they quit
+func (w *interactiveWizard) browseResults(results []registration.TaskResult) {
+	if len(results) == 0 {
+		return
+	}
+	for {
+		fmt.Fprintln(w.out, "\nResults:")
+		for i, result := range results {
+			fmt.Fprintf(w.out, "  %3d) %-8s %s\n", i+1, result.Result.StatusToString(), result.Task.Identifier().String())
+		}
+		answer := w.ask("Enter the number of a result to see its details, or 'q' to quit: ")
+		if answer == "q" || answer == "" {
+			return
+		}
+		indexes, err := parseSelection(answer, len(results))
+		if err != nil || len(indexes) != 1 {
+			fmt.Fprintf(w.out, "Please enter a single number between 1 and %d\n", len(results))
+			continue
+		}
+		w.showResult(results[indexes[0]])
+		if w.ask("\nPress enter to go back to the results, or 'q' to quit: ") == "q" {
+			return
+		}
+	}
+}
+
+func (w *interactiveWizard) showResult(result registration.TaskResult) {
+	fmt.Fprintf(w.out, "\n%s - %s\n", result.Task.Identifier().String(), result.Result.StatusToString())
+	fmt.Fprintln(w.out, strings.TrimSpace(result.Result.Summary))
+	if result.Result.URL != "" {
+		fmt.Fprintf(w.out, "See %s for more information.\n", result.Result.URL)
+	}
+	if result.Result.Payload != nil {
+		payload, err := json.MarshalIndent(result.Result.Payload, "", "  ")
+		if err != nil {
+			payload = []byte(fmt.Sprintf("%+v", result.Result.Payload))
+		}
+		fmt.Fprintf(w.out, "Payload:\n%s\n", payload)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/suites"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+var wizardTestSuites = []suites.Suite{
+	{Identifier: "java", DisplayName: "Java Agent", Tasks: []string{"Base/*", "Java/*"}},
+	{Identifier: "infra", DisplayName: "Infrastructure Agent", Tasks: []string{"Base/*", "Infra/*"}},
+	{Identifier: "infra:debug", DisplayName: "Infrastructure Agent (Debug)", Tasks: []string{"Base/*", "Infra/*", "Infra/Agent/Debug"}},
+	{Identifier: "php", DisplayName: "PHP Agent", Tasks: []string{"Base/*", "PHP/*"}},
+}
+
+func Test_proposeSuites(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []string
+		want       []string
+	}{
+		{name: "no agents detected", categories: nil, want: nil},
+		{name: "first matching suite per agent", categories: []string{"Infra", "Java"}, want: []string{"infra", "java"}},
+		{name: "category case differs from suite tasks", categories: []string{"Php"}, want: []string{"php"}},
+		{name: "agent without a suite", categories: []string{"Unknown"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, suite := range proposeSuites(tt.categories, wizardTestSuites) {
+				got = append(got, suite.Identifier)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("proposeSuites() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		count   int
+		want    []int
+		wantErr bool
+	}{
+		{name: "empty selects nothing", input: "", count: 3, want: nil},
+		{name: "all", input: "a", count: 3, want: []int{0, 1, 2}},
+		{name: "comma and space separated", input: "3, 1", count: 3, want: []int{2, 0}},
+		{name: "duplicates are ignored", input: "2,2", count: 3, want: []int{1}},
+		{name: "out of range", input: "4", count: 3, wantErr: true},
+		{name: "not a number", input: "java", count: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSelection(tt.input, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSelection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_interactiveWizard_chooseSuites(t *testing.T) {
+	proposed := wizardTestSuites[:1]
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "accepts the proposed suites", input: "\n", want: []string{"java"}},
+		{name: "picks other suites", input: "n\n2,4\n", want: []string{"infra", "php"}},
+		{name: "asks again after invalid input", input: "n\n9\n4\n", want: []string{"php"}},
+		{name: "no suites runs default tasks", input: "n\n\n", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wizard := newInteractiveWizard(strings.NewReader(tt.input), &bytes.Buffer{})
+			var got []string
+			for _, suite := range wizard.chooseSuites(proposed, wizardTestSuites) {
+				got = append(got, suite.Identifier)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chooseSuites() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_interactiveWizard_chooseSecureFiles(t *testing.T) {
+	files := []string{"/etc/newrelic-infra.yml", "/etc/newrelic/newrelic.cfg"}
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "none by default", input: "\n", want: nil},
+		{name: "all", input: "a\n", want: files},
+		{name: "single file", input: "2\n", want: []string{"/etc/newrelic/newrelic.cfg"}},
+		{name: "closed input includes nothing", input: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wizard := newInteractiveWizard(strings.NewReader(tt.input), &bytes.Buffer{})
+			got := wizard.chooseSecureFiles(files)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chooseSecureFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_interactiveWizard_browseResults(t *testing.T) {
+	results := []registration.TaskResult{
+		{
+			Task: testResultTask{identifier: tasks.IdentifierFromString("Base/Config/Validate")},
+			Result: tasks.Result{
+				Status:  tasks.Failure,
+				Summary: "Unable to parse newrelic.yml",
+				URL:     "https://docs.newrelic.com/",
+				Payload: map[string]string{"file": "newrelic.yml"},
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	wizard := newInteractiveWizard(strings.NewReader("1\n\nq\n"), out)
+	wizard.browseResults(results)
+
+	for _, expected := range []string{"Failure  Base/Config/Validate", "Unable to parse newrelic.yml", "See https://docs.newrelic.com/ for more information.", `"file": "newrelic.yml"`} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("browseResults() output is missing %q:\n%s", expected, out.String())
+		}
+	}
+}
+
+type testResultTask struct {
+	identifier tasks.Identifier
+}
+
+func (t testResultTask) Identifier() tasks.Identifier { return t.identifier }
+func (t testResultTask) Explain() string              { return "" }
+func (t testResultTask) Dependencies() []string       { return nil }
+func (t testResultTask) Execute(tasks.Options, map[string]tasks.Result) tasks.Result {
+	return tasks.Result{}
+}
@@ -0,0 +1,244 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// FixesDirName is the directory, next to nrdiag-output.json, the config fixes suggested by the tasks are written to as one
+// unified diff per file
+const FixesDirName = "nrdiag-fixes"
+
+// BackupSuffix is appended to the name of a config file to back it up before -apply-fixes changes it
+const BackupSuffix = ".nrdiag-backup"
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// FileFixes - the fixes suggested for one config file, with its content before and after them
+type FileFixes struct {
+	File     string
+	Fixes    []tasks.ConfigFix
+	DiffName string
+	Diff     string
+	Original []byte
+	Fixed    []byte
+}
+
+// WriteFixes renders the fixes in the results as unified diffs against the original files in nrdiag-fixes/ and returns
+// them so they can be applied. A fix whose setting isn't in the file anymore is left out.
+func WriteFixes(data []registration.TaskResult) ([]FileFixes, error) {
+	fixes := getFileFixes(data)
+	if len(fixes) == 0 {
+		return nil, nil
+	}
+
+	fixesDir := filepath.Join(config.Flags.OutputPath, FixesDirName)
+	err := os.MkdirAll(fixesDir, 0777)
+	if err != nil {
+		log.Info("Error creating directory", err)
+		log.Info(permissionsError)
+		return nil, err
+	}
+	for _, fileFixes := range fixes {
+		diffFile := filepath.Join(fixesDir, fileFixes.DiffName)
+		log.Debug("Creating fix file:", diffFile)
+		err = ioutil.WriteFile(diffFile, []byte(fileFixes.Diff), 0644)
+		if err != nil {
+			log.Info("Error creating fix file", err)
+			log.Info(permissionsError)
+			return nil, err
+		}
+	}
+	return fixes, nil
+}
+
+// getFileFixes groups the fixes of every result by file, in the order the tasks suggested them, and applies them to
+// the content of each file
+func getFileFixes(data []registration.TaskResult) []FileFixes {
+	var files []string
+	fixesByFile := make(map[string][]tasks.ConfigFix)
+	for _, result := range data {
+		for _, fix := range result.Result.Fixes {
+			if _, ok := fixesByFile[fix.File]; !ok {
+				files = append(files, fix.File)
+			}
+			if !containsFix(fixesByFile[fix.File], fix) {
+				fixesByFile[fix.File] = append(fixesByFile[fix.File], fix)
+			}
+		}
+	}
+
+	var fileFixes []FileFixes
+	for _, file := range files {
+		original, err := tasks.ReadFileBytes(file)
+		if err != nil {
+			log.Debug("Unable to read", file, "to fix it:", err)
+			continue
+		}
+		fixed := original
+		var applied []tasks.ConfigFix
+		for _, fix := range fixesByFile[file] {
+			content, err := tasks.ApplyConfigFixes(file, fixed, []tasks.ConfigFix{fix})
+			if err != nil {
+				log.Debug("Skipping fix:", err)
+				continue
+			}
+			fixed = content
+			applied = append(applied, fix)
+		}
+		if len(applied) == 0 || bytes.Equal(original, fixed) {
+			continue
+		}
+		fileFixes = append(fileFixes, FileFixes{
+			File:     file,
+			Fixes:    applied,
+			DiffName: diffName(file),
+			Diff:     unifiedDiff(file, applied, string(original), string(fixed)),
+			Original: original,
+			Fixed:    fixed,
+		})
+	}
+	return fileFixes
+}
+
+func containsFix(fixes []tasks.ConfigFix, fix tasks.ConfigFix) bool {
+	for _, f := range fixes {
+		if f == fix {
+			return true
+		}
+	}
+	return false
+}
+
+// diffName - the name of the diff file for a config file, e.g. etc_newrelic-infra.yml.diff for /etc/newrelic-infra.yml
+func diffName(file string) string {
+	name := strings.TrimLeft(filepath.ToSlash(file), "/")
+	name = strings.NewReplacer("/", "_", ":", "_").Replace(name)
+	return name + ".diff"
+}
+
+// unifiedDiff renders the change from original to fixed as a unified diff, with a comment line describing each fix.
+// Fixes only change lines in place, so both sides always have the same lines.
+func unifiedDiff(file string, fixes []tasks.ConfigFix, original string, fixed string) string {
+	var b strings.Builder
+	for _, fix := range fixes {
+		fmt.Fprintf(&b, "# %s\n", fix.Description)
+	}
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", file, file)
+
+	oldLines := diffLines(original)
+	newLines := diffLines(fixed)
+	var changed []int
+	for i := range oldLines {
+		if oldLines[i] != newLines[i] {
+			changed = append(changed, i)
+		}
+	}
+
+	for len(changed) > 0 {
+		// a hunk covers the changes closer to each other than twice the context
+		last := 0
+		for last+1 < len(changed) && changed[last+1]-changed[last] <= 2*diffContext {
+			last++
+		}
+		start := changed[0] - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := changed[last] + diffContext + 1
+		if end > len(oldLines) {
+			end = len(oldLines)
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", start+1, end-start, start+1, end-start)
+		for i := start; i < end; {
+			if oldLines[i] == newLines[i] {
+				fmt.Fprintf(&b, " %s\n", oldLines[i])
+				i++
+				continue
+			}
+			// consecutive changed lines are written as a block of removed lines followed by the added ones, as diff does
+			block := i
+			for block < end && oldLines[block] != newLines[block] {
+				block++
+			}
+			for _, line := range oldLines[i:block] {
+				fmt.Fprintf(&b, "-%s\n", line)
+			}
+			for _, line := range newLines[i:block] {
+				fmt.Fprintf(&b, "+%s\n", line)
+			}
+			i = block
+		}
+		changed = changed[last+1:]
+	}
+	return b.String()
+}
+
+func diffLines(content string) []string {
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// CopyFixesToZip - adds the diffs written by WriteFixes to the zip file as nrdiag-fixes/<file>.diff
+func CopyFixesToZip(zipfile *Archive, fixes []FileFixes) {
+	var diffs []tasks.FileCopyEnvelope
+	for _, fileFixes := range fixes {
+		diffs = append(diffs, tasks.FileCopyEnvelope{
+			Path:       filepath.Join(zipfile.outputPath, FixesDirName, fileFixes.DiffName),
+			Identifier: FixesDirName + "/",
+		})
+	}
+	copyFilesToZip(zipfile, diffs, openOutputFile)
+}
+
+// ApplyFixes shows the diff of each file and, once confirmed, backs the file up and writes the fixed content. A file
+// that changed since nrdiag read it is left alone. Returns the number of files changed.
+func ApplyFixes(fixes []FileFixes, confirm func(string) bool) int {
+	applied := 0
+	for _, fileFixes := range fixes {
+		path := tasks.RootPath(fileFixes.File)
+		log.Info("\n" + fileFixes.Diff)
+		if !confirm(fmt.Sprintf("Apply these changes to %s? The current file is saved as %s", path, path+BackupSuffix)) {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			log.Info("Unable to fix", path, err)
+			continue
+		}
+		current, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Info("Unable to fix", path, err)
+			continue
+		}
+		if !bytes.Equal(current, fileFixes.Original) {
+			log.Info("Not fixing " + path + ", it changed since it was checked. Run nrdiag again to get up to date fixes")
+			continue
+		}
+		err = ioutil.WriteFile(path+BackupSuffix, current, info.Mode().Perm())
+		if err != nil {
+			log.Info("Unable to back up", path, err)
+			continue
+		}
+		// WriteFile truncates the existing file, keeping its mode and owner
+		err = ioutil.WriteFile(path, fileFixes.Fixed, info.Mode().Perm())
+		if err != nil {
+			log.Info("Unable to fix", path, err)
+			continue
+		}
+		log.Info("Fixed " + path)
+		applied++
+	}
+	return applied
+}
@@ -0,0 +1,178 @@
+package output
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+const fixesConfig = `common: &default_settings
+  license_key: "'0123456789abcdef0123456789abcdef01234567'"
+  app_name: My Application
+  monitor_mode: true
+  developer_mode: false
+  log_level: info
+  audit_log:
+    enabled: false
+  capture_params: false
+  transaction_tracer:
+    enabled: true
+  error_collector:
+    enabled: true
+  log_levl: debug
+`
+
+func setupFixes(t *testing.T) (string, []registration.TaskResult) {
+	dir, err := ioutil.TempDir("", "nrdiag-fixes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(dir, "newrelic.yml")
+	err = ioutil.WriteFile(configFile, []byte(fixesConfig), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []registration.TaskResult{
+		{
+			Task: registration.TasksForIdentifierString("Base/Config/LicenseKey")[0],
+			Result: tasks.Result{Status: tasks.Warning, Fixes: []tasks.ConfigFix{{
+				File:        configFile,
+				Description: "Remove the quotes inside the license key",
+				Key:         "common.license_key",
+				Value:       "'0123456789abcdef0123456789abcdef01234567'",
+				NewValue:    "0123456789abcdef0123456789abcdef01234567",
+			}}},
+		},
+		{
+			Task: registration.TasksForIdentifierString("Base/Config/Collect")[0],
+			Result: tasks.Result{Status: tasks.Info, Fixes: []tasks.ConfigFix{
+				{File: configFile, Description: "Rename log_levl to log_level", Key: "common.log_levl", Value: "debug", NewKey: "common.log_level"},
+				{File: configFile, Description: "Set proxy_host", Key: "common.proxy_host", Value: "proxy", NewValue: "proxy.local"},
+			}},
+		},
+	}
+	return dir, results
+}
+
+func TestWriteFixes(t *testing.T) {
+	dir, results := setupFixes(t)
+	defer os.RemoveAll(dir)
+	defer func(outputPath string) { config.Flags.OutputPath = outputPath }(config.Flags.OutputPath)
+	config.Flags.OutputPath = dir
+
+	fixes, err := WriteFixes(results)
+	if err != nil {
+		t.Fatal("Unexpected error writing fixes:", err)
+	}
+	if len(fixes) != 1 || len(fixes[0].Fixes) != 2 {
+		t.Fatalf("Expected the two fixes found in the file, got %+v", fixes)
+	}
+
+	configFile := filepath.Join(dir, "newrelic.yml")
+	expected := `# Remove the quotes inside the license key
+# Rename log_levl to log_level
+--- ` + configFile + `
++++ ` + configFile + `
+@@ -1,5 +1,5 @@
+ common: &default_settings
+-  license_key: "'0123456789abcdef0123456789abcdef01234567'"
++  license_key: "0123456789abcdef0123456789abcdef01234567"
+   app_name: My Application
+   monitor_mode: true
+   developer_mode: false
+@@ -11,4 +11,4 @@
+     enabled: true
+   error_collector:
+     enabled: true
+-  log_levl: debug
++  log_level: debug
+`
+	diff, err := ioutil.ReadFile(filepath.Join(dir, FixesDirName, fixes[0].DiffName))
+	if err != nil {
+		t.Fatal("Unable to read the diff:", err)
+	}
+	if string(diff) != expected {
+		t.Errorf("Unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	dir, results := setupFixes(t)
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "newrelic.yml")
+	fixes := getFileFixes(results)
+
+	if applied := ApplyFixes(fixes, func(string) bool { return false }); applied != 0 {
+		t.Errorf("Expected no file to change without confirmation, %d changed", applied)
+	}
+	if _, err := os.Stat(configFile + BackupSuffix); !os.IsNotExist(err) {
+		t.Errorf("Expected no backup without confirmation")
+	}
+
+	if applied := ApplyFixes(fixes, func(string) bool { return true }); applied != 1 {
+		t.Fatalf("Expected the config file to be fixed, %d changed", applied)
+	}
+	backup, _ := ioutil.ReadFile(configFile + BackupSuffix)
+	if string(backup) != fixesConfig {
+		t.Errorf("Expected the backup to hold the original file, got:\n%s", backup)
+	}
+	fixed, _ := ioutil.ReadFile(configFile)
+	if string(fixed) != string(fixes[0].Fixed) {
+		t.Errorf("Expected the fixed content, got:\n%s", fixed)
+	}
+	info, _ := os.Stat(configFile)
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("Expected the file mode to be kept, got %v", info.Mode().Perm())
+	}
+
+	// the file no longer matches what the fixes were made from
+	if applied := ApplyFixes(fixes, func(string) bool { return true }); applied != 0 {
+		t.Errorf("Expected a changed file to be left alone, %d changed", applied)
+	}
+}
+
+func TestCopyFixesToZip(t *testing.T) {
+	dir, results := setupFixes(t)
+	defer os.RemoveAll(dir)
+	defer func(outputPath string) { config.Flags.OutputPath = outputPath }(config.Flags.OutputPath)
+	config.Flags.OutputPath = dir
+	// the diffs are nrdiag's own output, they must be found even when diagnosing another root
+	root, err := ioutil.TempDir("", "nrdiag-root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	fixes, err := WriteFixes(results)
+	if err != nil {
+		t.Fatal("Unexpected error writing fixes:", err)
+	}
+	if err := tasks.SetRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	defer tasks.SetRoot("")
+	zipfile := CreateZip(dir)
+	CopyFixesToZip(zipfile, fixes)
+	CloseZip(zipfile)
+
+	reader, err := zip.OpenReader(filepath.Join(dir, "nrdiag-output.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	var diffs []string
+	for _, file := range reader.File {
+		if matched, _ := filepath.Match("nrdiag-output/"+FixesDirName+"/*.diff", file.Name); matched {
+			diffs = append(diffs, file.Name)
+		}
+	}
+	if len(diffs) != 1 || diffs[0] != "nrdiag-output/"+FixesDirName+"/"+fixes[0].DiffName {
+		t.Errorf("Expected the diff in the zip file, got %v", diffs)
+	}
+}
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+)
+
+// ManifestName is the name of the manifest stored in the zip file
+const ManifestName = "nrdiag-output/nrdiag-manifest.json"
+
+// ManifestEntry describes a single file stored in the zip file and where it came from
+type ManifestEntry struct {
+	OriginalPath string
+	StoredName   string
+	Size         int64
+	ModTime      time.Time
+	SHA256       string
+	Identifier   string
+	Streamed     bool
+	Truncated    bool // set when a file on disk shrank or could not be fully read while it was copied
+}
+
+// Manifest lists every file stored in the zip file so its contents can be verified later with -verify
+type Manifest struct {
+	NRDiagVersion string
+	CreatedAt     time.Time
+	Files         []ManifestEntry
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	count int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.count += int64(len(p))
+	return len(p), nil
+}
+
+// writeManifestToZip adds the manifest of all files copied so far to the zip file
+func writeManifestToZip(zipfile *zip.Writer, entries []ManifestEntry) error {
+	manifest := Manifest{
+		NRDiagVersion: config.Version,
+		CreatedAt:     OutputNow(),
+		Files:         entries,
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "	")
+	if err != nil {
+		return err
+	}
+
+	header := zip.FileHeader{
+		Name:     ManifestName,
+		Method:   zip.Deflate,
+		Modified: manifest.CreatedAt,
+	}
+	writer, err := zipfile.CreateHeader(&header)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(manifestJSON)
+	return err
+}
+
+// VerifyArchive rechecks the size and SHA-256 of every file in a nrdiag zip file against its manifest.
+// It returns a description of every mismatch found, or an error if the archive or its manifest can't be read.
+func VerifyArchive(path string) ([]string, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	zipFiles := make(map[string]*zip.File)
+	for _, file := range reader.File {
+		zipFiles[file.Name] = file
+	}
+
+	manifestFile, ok := zipFiles[ManifestName]
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain %s", path, ManifestName)
+	}
+	manifestReader, err := manifestFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer manifestReader.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(manifestReader).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", ManifestName, err.Error())
+	}
+
+	var problems []string
+	listed := map[string]struct{}{ManifestName: struct{}{}}
+	for _, entry := range manifest.Files {
+		listed[entry.StoredName] = struct{}{}
+		file, ok := zipFiles[entry.StoredName]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: listed in manifest but missing from archive", entry.StoredName))
+			continue
+		}
+		size, checksum, err := hashZipFile(file)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: unable to read: %s", entry.StoredName, err.Error()))
+			continue
+		}
+		if size != entry.Size {
+			problems = append(problems, fmt.Sprintf("%s: size is %d bytes, manifest expects %d", entry.StoredName, size, entry.Size))
+		}
+		if checksum != entry.SHA256 {
+			problems = append(problems, fmt.Sprintf("%s: SHA-256 does not match manifest", entry.StoredName))
+		}
+		log.Debug("verified", entry.StoredName)
+	}
+
+	for _, file := range reader.File {
+		if _, ok := listed[file.Name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: not listed in manifest", file.Name))
+		}
+	}
+
+	return problems, nil
+}
+
+func hashZipFile(file *zip.File) (int64, string, error) {
+	fileReader, err := file.Open()
+	if err != nil {
+		return 0, "", err
+	}
+	defer fileReader.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, fileReader)
+	if err != nil {
+		return size, "", err
+	}
+	return size, hex.EncodeToString(hasher.Sum(nil)), nil
+}
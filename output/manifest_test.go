@@ -0,0 +1,125 @@
+package output
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+func createTestArchive(t *testing.T, dir string, envelopes []tasks.FileCopyEnvelope) (string, *Archive) {
+	zipfile := CreateZip(dir)
+	copyFilesToZip(zipfile, envelopes, openHostFile)
+	CloseZip(zipfile)
+	return filepath.Join(dir, "nrdiag-output.zip"), zipfile
+}
+
+func Test_VerifyArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nrdiag-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stream := make(chan string)
+	go streamData(stream)
+	archivePath, zipfile := createTestArchive(t, dir, []tasks.FileCopyEnvelope{
+		{Path: "../tasks/fixtures/java/newrelic/newrelic.yml", Identifier: "Java/Config/Agent"},
+		{Path: "data.txt", Stream: stream, Identifier: "Base/Log/Collect"},
+	})
+
+	problems, err := VerifyArchive(archivePath)
+	if err != nil {
+		t.Fatal("Unexpected error verifying archive:", err)
+	}
+	if len(problems) != 0 {
+		t.Error("Expected no problems, got:", problems)
+	}
+
+	if len(zipfile.manifest) != 2 {
+		t.Fatalf("Expected 2 manifest entries, got %d", len(zipfile.manifest))
+	}
+	fileEntry, streamEntry := zipfile.manifest[0], zipfile.manifest[1]
+	if fileEntry.StoredName != "nrdiag-output/Java/Config/newrelic.yml" || fileEntry.Identifier != "Java/Config/Agent" || fileEntry.Streamed || fileEntry.Truncated || fileEntry.Size == 0 || len(fileEntry.SHA256) != 64 {
+		t.Error("Unexpected manifest entry for file:", fileEntry)
+	}
+	if !streamEntry.Streamed || streamEntry.Size != int64(len("line 1\n")*5) {
+		t.Error("Unexpected manifest entry for stream:", streamEntry)
+	}
+}
+
+func Test_VerifyArchiveDetectsAlteredFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nrdiag-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// hand build an archive whose manifest doesn't match its contents
+	archivePath := filepath.Join(dir, "altered.zip")
+	file, _ := os.Create(archivePath)
+	w := zip.NewWriter(file)
+	zipfile := &Archive{outputPath: dir, file: file, writer: w, manifest: []ManifestEntry{
+		{StoredName: "nrdiag-output/Base/Log/altered.log", Size: 3, SHA256: "0000"},
+		{StoredName: "nrdiag-output/Base/Log/missing.log", Size: 3, SHA256: "0000"},
+	}}
+	writer, _ := w.Create("nrdiag-output/Base/Log/altered.log")
+	writer.Write([]byte("tampered"))
+	writer, _ = w.Create("nrdiag-output/Base/Log/extra.log")
+	writer.Write([]byte("extra"))
+	CloseZip(zipfile)
+
+	problems, err := VerifyArchive(archivePath)
+	if err != nil {
+		t.Fatal("Unexpected error verifying archive:", err)
+	}
+	expected := []string{
+		"nrdiag-output/Base/Log/altered.log: size is 8 bytes, manifest expects 3",
+		"nrdiag-output/Base/Log/altered.log: SHA-256 does not match manifest",
+		"nrdiag-output/Base/Log/missing.log: listed in manifest but missing from archive",
+		"nrdiag-output/Base/Log/extra.log: not listed in manifest",
+	}
+	if strings.Join(problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected problems:\n%s\nObserved:\n%s", strings.Join(expected, "\n"), strings.Join(problems, "\n"))
+	}
+
+	if _, err := VerifyArchive(filepath.Join(dir, "doesNotExist.zip")); err == nil {
+		t.Error("Expected an error for a missing archive")
+	}
+}
+
+func Test_ArchivesKeepTheirOwnManifest(t *testing.T) {
+	first, err := ioutil.TempDir("", "nrdiag-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(first)
+	second, err := ioutil.TempDir("", "nrdiag-manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(second)
+
+	firstZip, secondZip := CreateZip(first), CreateZip(second)
+	done := make(chan struct{})
+	for _, zipfile := range []*Archive{firstZip, secondZip} {
+		go func(zipfile *Archive) {
+			stream := make(chan string)
+			go streamData(stream)
+			copyFilesToZip(zipfile, []tasks.FileCopyEnvelope{{Path: "data.txt", Stream: stream}}, openHostFile)
+			done <- struct{}{}
+		}(zipfile)
+	}
+	<-done
+	<-done
+	CloseZip(firstZip)
+	CloseZip(secondZip)
+
+	if len(firstZip.manifest) != 1 || len(secondZip.manifest) != 1 {
+		t.Errorf("Expected one manifest entry per archive, got %d and %d", len(firstZip.manifest), len(secondZip.manifest))
+	}
+}
@@ -0,0 +1,205 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/env"
+)
+
+// MarkdownSummaryName is the name of the file written by -format markdown
+const MarkdownSummaryName = "nrdiag-summary.md"
+
+// markdownSummary is the data handed to the markdown template, custom templates passed with -template can use any of these fields
+type markdownSummary struct {
+	RunDate       time.Time
+	NRDiagVersion string
+	Filter        string
+	Suites        string
+	Tasks         string
+	HostFacts     []markdownFact
+	Results       []markdownResult // results included by -filter
+	Issues        []markdownResult // non-successful results included by -filter, as listed by WriteSummary
+	FilteredCount int
+}
+
+type markdownFact struct {
+	Name  string
+	Value string
+}
+
+type markdownResult struct {
+	Identifier string
+	Status     string
+	Summary    string
+	URL        string
+	Override   bool
+}
+
+const defaultMarkdownTemplate = `## New Relic Diagnostics CLI results
+
+| | |
+|---|---|
+| Run date | {{ .RunDate.Format "2006-01-02 15:04:05 MST" }} |
+| nrdiag version | {{ cell .NRDiagVersion }} |
+{{- range .HostFacts }}
+| {{ .Name }} | {{ cell .Value }} |
+{{- end }}
+
+### Results
+
+| Task | Status |
+|---|---|
+{{- range .Results }}
+| ` + "`{{ .Identifier }}`" + ` | {{ .Status }}{{ if .Override }} (override){{ end }} |
+{{- end }}
+{{- if .FilteredCount }}
+
+_{{ .FilteredCount }} results not shown by the filter._
+{{- end }}
+
+### Issues found
+{{ if not .Issues }}
+No Issues Found
+{{ else }}
+{{- range .Issues }}
+#### {{ .Status }} - ` + "`{{ .Identifier }}`" + `
+
+{{ .Summary }}
+{{- if .URL }}
+
+See {{ .URL }} for more information.
+{{- end }}
+{{ end }}
+{{- end }}
+### Settings
+
+| Setting | Value |
+|---|---|
+| Filter | {{ cell .Filter }} |
+| Suites | {{ cell .Suites }} |
+| Tasks | {{ cell .Tasks }} |
+`
+
+var markdownFuncs = template.FuncMap{
+	// cell makes a value safe to use inside a markdown table cell
+	"cell": func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		s = strings.Replace(s, "|", "\\|", -1)
+		return strings.Replace(strings.TrimSpace(s), "\n", "<br>", -1)
+	},
+}
+
+// WriteMarkdownSummary writes the results to nrdiag-summary.md in outputPath, using the template file at templatePath or the
+// built-in template when it is empty
+func WriteMarkdownSummary(outputPath string, templatePath string, data []registration.TaskResult) error {
+	templateText := defaultMarkdownTemplate
+	if templatePath != "" {
+		content, err := ioutil.ReadFile(templatePath)
+		if err != nil {
+			log.Info("Error reading markdown template", err)
+			return err
+		}
+		templateText = string(content)
+	}
+
+	markdownFile := filepath.Clean(outputPath + "/" + MarkdownSummaryName)
+	log.Debug("Creating markdown file:", markdownFile)
+	file, err := os.Create(markdownFile)
+	if err != nil {
+		log.Info("Error creating markdown summary", err)
+		log.Info(permissionsError)
+		return err
+	}
+	defer file.Close()
+
+	err = renderMarkdownSummary(file, templateText, data)
+	if err != nil {
+		log.Info("Error writing markdown summary", err)
+	}
+	return err
+}
+
+func renderMarkdownSummary(w io.Writer, templateText string, data []registration.TaskResult) error {
+	tmpl, err := template.New(MarkdownSummaryName).Funcs(markdownFuncs).Parse(templateText)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, getMarkdownSummary(data))
+}
+
+func getMarkdownSummary(data []registration.TaskResult) markdownSummary {
+	filter := config.Flags.Filter
+	if filter == "" {
+		filter = "all"
+	}
+	summary := markdownSummary{
+		RunDate:       OutputNow(),
+		NRDiagVersion: config.Version,
+		Filter:        filter,
+		Suites:        config.Flags.Suites,
+		Tasks:         config.Flags.Tasks,
+		HostFacts:     getHostFacts(data),
+	}
+
+	filtered := FilterResults(data)
+	summary.FilteredCount = len(data) - len(filtered)
+	for _, result := range filtered {
+		mdResult := markdownResult{
+			Identifier: result.Task.Identifier().String(),
+			Status:     result.Result.StatusToString(),
+			Summary:    strings.TrimSpace(result.Result.Summary),
+			URL:        result.Result.URL,
+			Override:   result.WasOverride,
+		}
+		summary.Results = append(summary.Results, mdResult)
+		if result.Result.IsFailure() {
+			summary.Issues = append(summary.Issues, mdResult)
+		}
+	}
+	return summary
+}
+
+// runtimePlatform is the platform nrdiag was built for, a var so tests can fix it
+var runtimePlatform = runtime.GOOS + "/" + runtime.GOARCH
+
+// getHostFacts lists details about the host from the Base/Env/HostInfo result, if it ran
+func getHostFacts(data []registration.TaskResult) []markdownFact {
+	facts := []markdownFact{
+		{Name: "nrdiag runtime", Value: runtimePlatform},
+	}
+	for _, result := range data {
+		if result.Task.Identifier().String() != "Base/Env/HostInfo" || result.Result.Status == tasks.None {
+			continue
+		}
+		hostInfo, ok := result.Result.Payload.(env.HostInfo)
+		if !ok {
+			continue
+		}
+		var cores int32
+		for _, cpu := range hostInfo.CPUs {
+			cores += cpu.Cores
+		}
+		facts = append(facts,
+			markdownFact{Name: "Hostname", Value: hostInfo.Hostname},
+			markdownFact{Name: "OS", Value: strings.TrimSpace(fmt.Sprintf("%s %s %s", hostInfo.Platform, hostInfo.PlatformVersion, hostInfo.OS))},
+			markdownFact{Name: "Kernel", Value: strings.TrimSpace(hostInfo.KernelVersion + " " + hostInfo.KernelArch)},
+			markdownFact{Name: "CPU cores", Value: fmt.Sprintf("%d", cores)},
+			markdownFact{Name: "Total memory (MB)", Value: fmt.Sprintf("%d", hostInfo.TotalVirtualMemoryMB)},
+		)
+	}
+	return facts
+}
@@ -0,0 +1,103 @@
+package output
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/env"
+)
+
+func generateMarkdownResults() []registration.TaskResult {
+	return []registration.TaskResult{
+		{
+			Task:   registration.TasksForIdentifierString("Base/Env/HostInfo")[0],
+			Result: tasks.Result{Status: tasks.Info, Summary: "Collected host information", Payload: env.HostInfo{Hostname: "web-01", OS: "linux", Platform: "ubuntu", PlatformVersion: "20.04", KernelVersion: "5.4.0", KernelArch: "x86_64", CPUs: []env.CPU{{Cores: 4}}, TotalVirtualMemoryMB: 2048}},
+		},
+		{
+			Task:   registration.TasksForIdentifierString("Base/Config/Collect")[0],
+			Result: tasks.Result{Status: tasks.Success, Summary: "There were 1 file(s) found"},
+		},
+		{
+			Task:   registration.TasksForIdentifierString("Base/Config/LicenseKey")[0],
+			Result: tasks.Result{Status: tasks.Failure, Summary: "No license key found\nCheck your | config", URL: "https://docs.newrelic.com/docs/license-key"},
+		},
+	}
+}
+
+func Test_renderMarkdownSummary(t *testing.T) {
+	OutputNow = func() time.Time {
+		return time.Date(2000, 12, 15, 17, 8, 00, 0, time.UTC)
+	}
+	runtimePlatform = "linux/amd64"
+	defer func(filter string) { config.Flags.Filter = filter }(config.Flags.Filter)
+	config.Flags.Filter = "success,warning,failure,error"
+
+	var observed bytes.Buffer
+	err := renderMarkdownSummary(&observed, defaultMarkdownTemplate, generateMarkdownResults())
+	if err != nil {
+		t.Fatal("Unexpected error rendering markdown:", err)
+	}
+
+	//if you intended to make changes to the markdown summary:
+	// - uncomment the next line of code for one run
+	// - inspect new-summary.md to make sure it looks like what you expect
+	// - replace test-summary.md with new-summary.md
+	// - comment the line and run the test again
+	//ioutil.WriteFile("fixtures/new-summary.md", observed.Bytes(), 0644)
+
+	expected := readFile("fixtures/test-summary.md")
+	if expected != observed.String() {
+		t.Error("Expected:", expected, "Observed:", observed.String())
+	}
+}
+
+func Test_renderMarkdownSummaryCustomTemplate(t *testing.T) {
+	defer func(filter string) { config.Flags.Filter = filter }(config.Flags.Filter)
+	config.Flags.Filter = "failure"
+
+	var observed bytes.Buffer
+	customTemplate := "{{ range .Issues }}{{ .Identifier }}: {{ .URL }}\n{{ end }}{{ .FilteredCount }} filtered"
+	err := renderMarkdownSummary(&observed, customTemplate, generateMarkdownResults())
+	if err != nil {
+		t.Fatal("Unexpected error rendering markdown:", err)
+	}
+
+	expected := "Base/Config/LicenseKey: https://docs.newrelic.com/docs/license-key\n2 filtered"
+	if observed.String() != expected {
+		t.Errorf("Expected %q, Observed %q", expected, observed.String())
+	}
+
+	if err := renderMarkdownSummary(&observed, "{{ .Missing", nil); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func Test_WriteMarkdownSummary(t *testing.T) {
+	outputPath, err := ioutil.TempDir("", "nrdiag-markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outputPath)
+	templatePath := filepath.Join(outputPath, "summary.tmpl")
+	if err := ioutil.WriteFile(templatePath, []byte("{{ .FilteredCount }} filtered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteMarkdownSummary(outputPath, templatePath, nil); err != nil {
+		t.Fatal("Unexpected error writing markdown:", err)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(outputPath, MarkdownSummaryName))
+	if err != nil {
+		t.Fatal("Expected the summary in the given output path:", err)
+	}
+	if string(content) != "0 filtered" {
+		t.Errorf("Expected the summary rendered with the given template, Observed %q", content)
+	}
+}
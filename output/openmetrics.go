@@ -0,0 +1,122 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// OpenMetricsName is the name of the file written by -format openmetrics. Point -output-path to the
+// node_exporter textfile collector directory to have it picked up.
+const OpenMetricsName = "nrdiag.prom"
+
+// WriteOpenMetrics writes gauges for every task result to nrdiag.prom. The file is written to a temporary
+// file first and renamed into place so the exporter never reads a partial file.
+func WriteOpenMetrics(data []registration.TaskResult, runDuration time.Duration) error {
+	err := os.MkdirAll(config.Flags.OutputPath, 0777)
+	if err != nil {
+		log.Info("Error creating directory", err)
+		log.Info(permissionsError)
+		return err
+	}
+
+	metricsFile := filepath.Clean(config.Flags.OutputPath + "/" + OpenMetricsName)
+	log.Debug("Creating metrics file:", metricsFile)
+	// the temp file must be in the same directory for the rename to be atomic. Its name doesn't end
+	// with .prom so the textfile collector ignores it
+	tempFile, err := ioutil.TempFile(filepath.Dir(metricsFile), "."+OpenMetricsName+".tmp")
+	if err != nil {
+		log.Info("Error creating metrics file", err)
+		log.Info(permissionsError)
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	writeErr := writeOpenMetrics(tempFile, data, runDuration)
+	closeErr := tempFile.Close()
+	if writeErr != nil || closeErr != nil {
+		log.Info("Error writing metrics file", writeErr, closeErr)
+		if writeErr != nil {
+			return writeErr
+		}
+		return closeErr
+	}
+	// ioutil.TempFile creates the file as 0600 but the exporter usually runs as a different user
+	os.Chmod(tempFile.Name(), 0644)
+
+	err = os.Rename(tempFile.Name(), metricsFile)
+	if err != nil {
+		log.Info("Error writing metrics file", err)
+	}
+	return err
+}
+
+func writeOpenMetrics(w io.Writer, data []registration.TaskResult, runDuration time.Duration) error {
+	sorted := make([]registration.TaskResult, len(data))
+	copy(sorted, data)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Task.Identifier().String() < sorted[j].Task.Identifier().String()
+	})
+
+	var b strings.Builder
+
+	writeMetricHeader(&b, "nrdiag_version_info", "Version of the Diagnostics CLI that produced these metrics.")
+	fmt.Fprintf(&b, "nrdiag_version_info{version=\"%s\"} 1\n", escapeLabelValue(config.Version))
+
+	writeMetricHeader(&b, "nrdiag_last_run_timestamp_seconds", "Unix time the Diagnostics CLI run finished.")
+	fmt.Fprintf(&b, "nrdiag_last_run_timestamp_seconds %d\n", OutputNow().Unix())
+
+	writeMetricHeader(&b, "nrdiag_run_duration_seconds", "Time taken by the whole Diagnostics CLI run.")
+	fmt.Fprintf(&b, "nrdiag_run_duration_seconds %s\n", formatSeconds(runDuration))
+
+	// one series per possible status so alerts can match on status="failure" going to 1
+	writeMetricHeader(&b, "nrdiag_task_status", "1 for the status the task reported, 0 for every other status.")
+	for _, result := range sorted {
+		identifier := escapeLabelValue(result.Task.Identifier().String())
+		for status := tasks.None; status <= tasks.Skipped; status++ {
+			value := 0
+			if result.Result.Status == status {
+				value = 1
+			}
+			fmt.Fprintf(&b, "nrdiag_task_status{identifier=\"%s\",status=\"%s\"} %d\n", identifier, strings.ToLower(status.StatusToString()), value)
+		}
+	}
+
+	writeMetricHeader(&b, "nrdiag_task_duration_seconds", "Time taken to execute the task.")
+	for _, result := range sorted {
+		fmt.Fprintf(&b, "nrdiag_task_duration_seconds{identifier=\"%s\"} %s\n", escapeLabelValue(result.Task.Identifier().String()), formatSeconds(result.Duration))
+	}
+
+	writeMetricHeader(&b, "nrdiag_task_collected_files", "Number of files the task added to the zip file.")
+	for _, result := range sorted {
+		fmt.Fprintf(&b, "nrdiag_task_collected_files{identifier=\"%s\"} %d\n", escapeLabelValue(result.Task.Identifier().String()), len(result.Result.FilesToCopy))
+	}
+
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMetricHeader(b *strings.Builder, name string, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// escapeLabelValue escapes a label value as required by the exposition format
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(s)
+}
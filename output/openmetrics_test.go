@@ -0,0 +1,121 @@
+package output
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+func generateMetricsResults() []registration.TaskResult {
+	return []registration.TaskResult{
+		{
+			Task:     registration.TasksForIdentifierString("Base/Config/LicenseKey")[0],
+			Result:   tasks.Result{Status: tasks.Failure},
+			Duration: 1500 * time.Millisecond,
+		},
+		{
+			Task:     registration.TasksForIdentifierString("Base/Config/Collect")[0],
+			Result:   tasks.Result{Status: tasks.Success, FilesToCopy: []tasks.FileCopyEnvelope{{Path: "newrelic.yml"}, {Path: "newrelic.js"}}},
+			Duration: 20 * time.Millisecond,
+		},
+	}
+}
+
+func Test_writeOpenMetrics(t *testing.T) {
+	OutputNow = func() time.Time {
+		return time.Date(2000, 12, 15, 17, 8, 00, 0, time.UTC)
+	}
+	defer func(version string) { config.Version = version }(config.Version)
+	config.Version = "1.2.3"
+
+	var observed bytes.Buffer
+	err := writeOpenMetrics(&observed, generateMetricsResults(), 3*time.Second)
+	if err != nil {
+		t.Fatal("Unexpected error writing metrics:", err)
+	}
+
+	expected := `# HELP nrdiag_version_info Version of the Diagnostics CLI that produced these metrics.
+# TYPE nrdiag_version_info gauge
+nrdiag_version_info{version="1.2.3"} 1
+# HELP nrdiag_last_run_timestamp_seconds Unix time the Diagnostics CLI run finished.
+# TYPE nrdiag_last_run_timestamp_seconds gauge
+nrdiag_last_run_timestamp_seconds 976900080
+# HELP nrdiag_run_duration_seconds Time taken by the whole Diagnostics CLI run.
+# TYPE nrdiag_run_duration_seconds gauge
+nrdiag_run_duration_seconds 3.000
+# HELP nrdiag_task_status 1 for the status the task reported, 0 for every other status.
+# TYPE nrdiag_task_status gauge
+nrdiag_task_status{identifier="Base/Config/Collect",status="none"} 0
+nrdiag_task_status{identifier="Base/Config/Collect",status="success"} 1
+nrdiag_task_status{identifier="Base/Config/Collect",status="warning"} 0
+nrdiag_task_status{identifier="Base/Config/Collect",status="failure"} 0
+nrdiag_task_status{identifier="Base/Config/Collect",status="error"} 0
+nrdiag_task_status{identifier="Base/Config/Collect",status="info"} 0
+nrdiag_task_status{identifier="Base/Config/Collect",status="skipped"} 0
+nrdiag_task_status{identifier="Base/Config/LicenseKey",status="none"} 0
+nrdiag_task_status{identifier="Base/Config/LicenseKey",status="success"} 0
+nrdiag_task_status{identifier="Base/Config/LicenseKey",status="warning"} 0
+nrdiag_task_status{identifier="Base/Config/LicenseKey",status="failure"} 1
+nrdiag_task_status{identifier="Base/Config/LicenseKey",status="error"} 0
+nrdiag_task_status{identifier="Base/Config/LicenseKey",status="info"} 0
+nrdiag_task_status{identifier="Base/Config/LicenseKey",status="skipped"} 0
+# HELP nrdiag_task_duration_seconds Time taken to execute the task.
+# TYPE nrdiag_task_duration_seconds gauge
+nrdiag_task_duration_seconds{identifier="Base/Config/Collect"} 0.020
+nrdiag_task_duration_seconds{identifier="Base/Config/LicenseKey"} 1.500
+# HELP nrdiag_task_collected_files Number of files the task added to the zip file.
+# TYPE nrdiag_task_collected_files gauge
+nrdiag_task_collected_files{identifier="Base/Config/Collect"} 2
+nrdiag_task_collected_files{identifier="Base/Config/LicenseKey"} 0
+# EOF
+`
+	if observed.String() != expected {
+		t.Errorf("Expected:\n%s\nObserved:\n%s", expected, observed.String())
+	}
+}
+
+func Test_WriteOpenMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nrdiag-metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(outputPath string) { config.Flags.OutputPath = outputPath }(config.Flags.OutputPath)
+	config.Flags.OutputPath = dir
+
+	// an existing file is replaced as a whole
+	ioutil.WriteFile(filepath.Join(dir, OpenMetricsName), []byte("stale"), 0644)
+
+	err = WriteOpenMetrics(generateMetricsResults(), time.Second)
+	if err != nil {
+		t.Fatal("Unexpected error writing metrics:", err)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 || files[0].Name() != OpenMetricsName {
+		t.Fatal("Expected only the metrics file to be left in the output directory, found", files)
+	}
+	if files[0].Mode().Perm() != 0644 {
+		t.Error("Expected metrics file to be readable by the exporter, mode is", files[0].Mode())
+	}
+	content, _ := ioutil.ReadFile(filepath.Join(dir, OpenMetricsName))
+	if !strings.HasPrefix(string(content), "# HELP nrdiag_version_info") || !strings.HasSuffix(string(content), "# EOF\n") {
+		t.Error("Unexpected metrics file content:", string(content))
+	}
+}
+
+func Test_escapeLabelValue(t *testing.T) {
+	observed := escapeLabelValue("a\"b\\c\nd")
+	expected := `a\"b\\c\nd`
+	if observed != expected {
+		t.Errorf("Expected %s, Observed %s", expected, observed)
+	}
+}
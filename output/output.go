@@ -1,10 +1,10 @@
 package output
 
 import (
-	"archive/zip"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"sync"
@@ -19,6 +19,9 @@ import (
 //WriteOutputHeader takes in array of Result structs, returns color coded results overview in following format: <taskIdentifier>:<result>
 func WriteOutputHeader() {
 	log.Info(ColorString(White, "\nCheck Results\n-------------------------------------------------\n"))
+	for _, targetProcess := range tasks.DescribeTargetProcesses() {
+		log.Info(ColorString(White, "Targeted process: "+targetProcess+"\n"))
+	}
 }
 
 // WriteSummary reports on any non-successful items and tells the user why they weren't successful
@@ -39,7 +42,7 @@ func WriteSummary(data []registration.TaskResult) {
 	}
 
 	filteredCounter := 0
-	var filtered [6]int //Int array corresponding with 6 statuses, to count any filtered results
+	var filtered [7]int //Int array corresponding with 7 statuses, to count any filtered results
 
 	for _, result := range failures {
 
@@ -63,19 +66,31 @@ func WriteSummary(data []registration.TaskResult) {
 	}
 }
 
-//WriteOutputFile will output a JSON file with the results of the run
-func WriteOutputFile(data []registration.TaskResult) {
-	outputJSON(getResultsJSON(data))
+//WriteOutputFile will output a JSON file with the results of the run to outputPath
+func WriteOutputFile(outputPath string, data []registration.TaskResult) error {
+	return outputJSON(outputPath, getResultsJSON(data))
+}
+
+// FilterResults returns the results whose status is included by the -filter flag. When no filter is set (e.g. with -qq) all results are returned.
+func FilterResults(data []registration.TaskResult) []registration.TaskResult {
+	if config.Flags.Filter == "" {
+		return data
+	}
+	var filtered []registration.TaskResult
+	for _, result := range data {
+		if filteredResult(result.Result.StatusToString()) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
 }
 
-// ProcessFilesChannel - reads from the channels for files to copy and deals with them
-func ProcessFilesChannel(zipfile *zip.Writer, wg *sync.WaitGroup) {
+// ProcessFilesChannel - reads from the channels for files to copy and deals with them. Every file zipped is recorded in the nrdiag-manifest.json written by CloseZip
+func ProcessFilesChannel(zipfile *Archive, files <-chan registration.TaskResult, wg *sync.WaitGroup) {
 	//Create output file and wipe out if it already exists
-	err := ioutil.WriteFile(config.Flags.OutputPath+"/nrdiag-filelist.txt", []byte(""), 0644)
+	err := ioutil.WriteFile(filepath.Join(zipfile.outputPath, "nrdiag-filelist.txt"), []byte("List of files in zipfile"), 0644)
 	if err != nil {
 		log.Debug("Error creating filelist", err)
-	} else {
-		ioutil.WriteFile(config.Flags.OutputPath+"/nrdiag-filelist.txt", []byte("List of files in zipfile"), 0644)
 	}
 
 	// This is how we track the file names going into to zip file to prevent duplicates
@@ -84,7 +99,7 @@ func ProcessFilesChannel(zipfile *zip.Writer, wg *sync.WaitGroup) {
 	pathList := make(map[string]struct{})
 	var taskFiles []tasks.FileCopyEnvelope
 
-	for result := range registration.Work.FilesChannel {
+	for result := range files {
 		log.Debug("Copying files from result: ", result.Task.Identifier().String())
 
 		for _, envelope := range result.Result.FilesToCopy {
@@ -113,7 +128,7 @@ func ProcessFilesChannel(zipfile *zip.Writer, wg *sync.WaitGroup) {
 		}
 
 	}
-	copyFilesToZip(zipfile, taskFiles)
+	copyFilesToZip(zipfile, taskFiles, openHostFile)
 
 	log.Debug("Files channel closed")
 	copyFileListToZip(zipfile)
@@ -121,9 +136,9 @@ func ProcessFilesChannel(zipfile *zip.Writer, wg *sync.WaitGroup) {
 	wg.Done()
 }
 
-// CopySingleFileToZip - takes the named file and adds it to the zip file (assumes relative location to OutputPath)
-func CopySingleFileToZip(zipfile *zip.Writer, filename string) {
-	filePath := config.Flags.OutputPath + filename
+// CopySingleFileToZip - takes the named file and adds it to the zip file (assumes relative location to the output path of the zip file)
+func CopySingleFileToZip(zipfile *Archive, filename string) {
+	filePath := filepath.Join(zipfile.outputPath, filename)
 	_, filelistErr := os.Stat(filePath)
 	if os.IsNotExist(filelistErr) {
 		log.Debug("Could not copy file to zip: ", filename)
@@ -135,26 +150,46 @@ func CopySingleFileToZip(zipfile *zip.Writer, filename string) {
 	filelist := []tasks.FileCopyEnvelope{
 		tasks.FileCopyEnvelope{Path: filePath},
 	}
-	copyFilesToZip(zipfile, filelist)
+	copyFilesToZip(zipfile, filelist, openOutputFile)
 }
 
-// CopyOutputToZip - takes the nrdiag-output.json and adds it to the zip file
-func CopyOutputToZip(zipfile *zip.Writer) {
+// CopyOutputToZip - takes the nrdiag-output.json, and any additional output formats requested, and adds them to the zip file
+func CopyOutputToZip(zipfile *Archive) {
 	CopySingleFileToZip(zipfile, "nrdiag-output.json")
+	if config.Flags.HasFormat("markdown") {
+		CopySingleFileToZip(zipfile, MarkdownSummaryName)
+	}
+	if config.Flags.HasFormat("openmetrics") {
+		CopySingleFileToZip(zipfile, OpenMetricsName)
+	}
 }
 
-func copyFileListToZip(zipfile *zip.Writer) {
+// CopyWatchIntervalToZip - adds the results of one -watch run to the zip file as watch/nrdiag-output-<run>.json
+func CopyWatchIntervalToZip(zipfile *Archive, run int, data []registration.TaskResult) {
+	stream := make(chan string, 1)
+	stream <- getResultsJSON(data)
+	close(stream)
+
+	copyFilesToZip(zipfile, []tasks.FileCopyEnvelope{
+		tasks.FileCopyEnvelope{Path: fmt.Sprintf("nrdiag-output-%d.json", run), Identifier: "watch/", Stream: stream},
+	}, openOutputFile)
+}
+
+func copyFileListToZip(zipfile *Archive) {
 	CopySingleFileToZip(zipfile, "nrdiag-filelist.txt")
 }
 
 // WriteLineResults - outputs results to the screen as they complete (from the channel) and then returns the entire set
-func WriteLineResults() []registration.TaskResult {
+func WriteLineResults(results <-chan registration.TaskResult) []registration.TaskResult {
+	if !config.Flags.VeryQuiet {
+		WriteOutputHeader()
+	}
 	filteredCounter := 0
-	var filtered [6]int
+	var filtered [7]int
 
 	var outputResults []registration.TaskResult
 
-	for result := range registration.Work.ResultsChannel {
+	for result := range results {
 		if filteredResult(result.Result.StatusToString()) {
 			payload := ""
 			if result.Result.Status == tasks.Info {
@@ -2,6 +2,8 @@ package output
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -9,6 +11,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/config"
@@ -52,41 +55,71 @@ func getEpoch() int64 {
 	return (now / 1000000)
 }
 
-func outputJSON(json string) {
-	jsonFile := filepath.Clean(config.Flags.OutputPath + "/nrdiag-output.json")
+func outputJSON(outputPath string, json string) error {
+	jsonFile := filepath.Clean(outputPath + "/nrdiag-output.json")
 	log.Debug("Creating json file:", jsonFile)
-	err := os.MkdirAll(config.Flags.OutputPath, 0777)
+	err := os.MkdirAll(outputPath, 0777)
 	if err != nil {
 		log.Info("Error creating directory", err)
 		log.Info(permissionsError)
+		return err
 	}
-	ioutil.WriteFile(jsonFile, []byte(json), 0644)
+	err = ioutil.WriteFile(jsonFile, []byte(json), 0644)
+	if err != nil {
+		log.Info("Error creating output file", err)
+		log.Info(permissionsError)
+	}
+	return err
+}
+
+// Archive is the nrdiag-output.zip of one run, with the directory the run writes its output files to and the manifest
+// of every file added so far. Runs write their own Archive, so -serve and -watch runs share no state.
+type Archive struct {
+	outputPath string
+	file       *os.File
+	writer     *zip.Writer
+	manifest   []ManifestEntry
+	mutex      sync.Mutex
 }
 
-func CreateZip() *zip.Writer {
-	err := os.MkdirAll(config.Flags.OutputPath, 0777)
+// CreateZip creates nrdiag-output.zip in outputPath
+func CreateZip(outputPath string) *Archive {
+	err := os.MkdirAll(outputPath, 0777)
 	if err != nil {
 		log.Info("Error creating directory", err)
 		log.Info(permissionsError)
 	}
-	zipfile, err := os.Create(config.Flags.OutputPath + "/nrdiag-output.zip")
+	zipfile, err := os.Create(filepath.Join(outputPath, "nrdiag-output.zip"))
 	if err != nil {
 		log.Info("Error creating zip file", err)
 		log.Info(permissionsError)
 	}
 
 	// Create a new zip archive.
-	w := zip.NewWriter(zipfile)
-	return w
+	return &Archive{
+		outputPath: outputPath,
+		file:       zipfile,
+		writer:     zip.NewWriter(zipfile),
+	}
 }
 
-func CloseZip(zipfile *zip.Writer) {
+func CloseZip(zipfile *Archive) {
+	zipfile.mutex.Lock()
+	defer zipfile.mutex.Unlock()
+	// The manifest goes in last so it covers every other file in the zip
+	err := writeManifestToZip(zipfile.writer, zipfile.manifest)
+	if err != nil {
+		log.Info("Error writing manifest to zip file: ", err)
+	}
 	// All done, now close the zip file
 	log.Debug("Done executing tasks, closing zip file")
-	zipErr := zipfile.Close()
+	zipErr := zipfile.writer.Close()
 	if zipErr != nil {
 		log.Info("error closing zip file: ", zipErr)
 	}
+	if zipfile.file != nil {
+		zipfile.file.Close()
+	}
 }
 
 func mapContains(set map[string]struct{}, item string) bool {
@@ -94,77 +127,115 @@ func mapContains(set map[string]struct{}, item string) bool {
 	return ok
 }
 
-// CopyFilesToZip - Copies files to the zip archive
-func copyFilesToZip(dst *zip.Writer, filesToZip []tasks.FileCopyEnvelope) {
+// openHostFile opens a file collected by a task. Paths set in agent configs refer to the host being diagnosed, so they
+// are read through tasks.FS under -root like the tasks read them
+func openHostFile(path string) (tasks.File, error) {
+	return tasks.FS.Open(tasks.RootPath(path))
+}
+
+// openOutputFile opens a file nrdiag wrote to the output directory, which is never under -root
+func openOutputFile(path string) (tasks.File, error) {
+	return tasks.OSFileSystem{}.Open(path)
+}
+
+// copyFilesToZip - Copies files to the zip archive and records each one in the archive manifest. A file that can't be
+// read is logged and skipped
+func copyFilesToZip(dst *Archive, filesToZip []tasks.FileCopyEnvelope, open func(string) (tasks.File, error)) {
+	dst.mutex.Lock()
+	defer dst.mutex.Unlock()
 
 	for _, envelope := range filesToZip {
 
+		entry := ManifestEntry{
+			OriginalPath: envelope.Path,
+			StoredName:   filepath.ToSlash("nrdiag-output/" + envelope.StoreName()),
+			Identifier:   envelope.Identifier,
+			Streamed:     envelope.Stream != nil,
+		}
+		hasher := sha256.New()
+
 		if envelope.Stream != nil {
 			header := zip.FileHeader{
-				Name:   filepath.ToSlash("nrdiag-output/" + envelope.StoreName()),
-				Method: zip.Deflate,
+				Name:     entry.StoredName,
+				Method:   zip.Deflate,
+				Modified: OutputNow(),
 			}
+			entry.ModTime = header.Modified
 
-			writer, _ := dst.CreateHeader(&header)
+			writer, _ := dst.writer.CreateHeader(&header)
+			counter := &countingWriter{}
+			multiWriter := io.MultiWriter(writer, hasher, counter)
 			for s := range envelope.Stream {
-				io.WriteString(writer, s)
+				io.WriteString(multiWriter, s)
 			}
+			entry.Size = counter.count
 		} else {
 			log.Debug("adding " + envelope.Path + " to zip")
-			// Get file info from file
-			stat, err := os.Stat(envelope.Path)
+			written, modTime, truncated, err := copyFileToZip(dst.writer, entry.StoredName, envelope.Path, hasher, open)
 			if err != nil {
 				log.Info("Error adding file to Diagnostics CLI zip file: ", err)
-				return
+				continue
 			}
-			// open file handle
-			fileHandle, err := os.Open(envelope.Path)
-			defer fileHandle.Close()
+			entry.Size = written
+			entry.ModTime = modTime
+			entry.Truncated = truncated
+		}
 
-			if err != nil {
-				log.Info("Error opening file handle", err)
-				return
-			}
+		entry.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+		dst.manifest = append(dst.manifest, entry)
 
-			header, err := zip.FileInfoHeader(stat)
-			if err != nil {
-				log.Info("Error copying file", err)
-				return
-			}
-			// Setting filename to deduplicated file name
-			header.Name = envelope.StoreName()
-			header.Name = filepath.ToSlash("nrdiag-output/" + header.Name) //Add folder to filename to unzip into a folder
-			log.Debug("storing name", header.Name)
+		//Add filepath and name to text file
+		addFileToFileList(dst.outputPath, envelope)
+	}
+}
 
-			// Change to deflate to gain better compression
-			// see http://golang.org/pkg/archive/zip/#pkg-constants
-			header.Method = zip.Deflate
+// copyFileToZip stores one file in the zip file under storedName, returning the bytes written, its modification time
+// and whether it shrank or could not be fully read while it was copied
+func copyFileToZip(dst *zip.Writer, storedName string, path string, hasher io.Writer, open func(string) (tasks.File, error)) (int64, time.Time, bool, error) {
+	// open file handle
+	fileHandle, err := open(path)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	defer fileHandle.Close()
 
-			// write zip file header
-			writer, err := dst.CreateHeader(header)
+	// Get file info from file
+	stat, err := fileHandle.Stat()
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	header, err := zip.FileInfoHeader(stat)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	// Setting filename to deduplicated file name
+	header.Name = storedName //Add folder to filename to unzip into a folder
+	log.Debug("storing name", header.Name)
 
-			if err != nil {
-				log.Info("Error writing results to zip file: ", err)
-				return
-			}
+	// Change to deflate to gain better compression
+	// see http://golang.org/pkg/archive/zip/#pkg-constants
+	header.Method = zip.Deflate
 
-			_, err = io.Copy(writer, fileHandle)
-			if err != nil {
-				log.Info("Error writing file into zip: ", err)
-			}
-		}
+	// write zip file header
+	writer, err := dst.CreateHeader(header)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
 
-		//Add filepath and name to text file
-		addFileToFileList(envelope)
+	written, err := io.Copy(io.MultiWriter(writer, hasher), fileHandle)
+	if err != nil {
+		log.Info("Error writing file into zip: ", err)
 	}
+	return written, stat.ModTime(), err != nil || written < stat.Size(), nil
 }
 
 // This takes the fileToCopy item and appends the values to a text file to be included in the zip file to preserve filepaths
-func addFileToFileList(file tasks.FileCopyEnvelope) {
-	f, err := os.OpenFile(config.Flags.OutputPath+"/nrdiag-filelist.txt", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+func addFileToFileList(outputPath string, file tasks.FileCopyEnvelope) {
+	f, err := os.OpenFile(filepath.Join(outputPath, "nrdiag-filelist.txt"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
 	if err != nil {
 		log.Info("Error writing output file", err)
 		log.Info(permissionsError)
+		return
 	}
 	defer f.Close()
 
@@ -191,11 +262,11 @@ func filteredResult(result string) bool {
 	return false
 }
 
-//filteredToString - Takes an array of ints corresponding to the 5 statuses, with a counter for each: array[status] = status count
+//filteredToString - Takes an array of ints corresponding to the 7 statuses, with a counter for each: array[status] = status count
 // returns a string summary of instances:
 // IN: [3,1,0,0,2]
 // OUT: 3 Success, 1 Warning, 2 None
-func filteredToString(filtered [6]int) string {
+func filteredToString(filtered [7]int) string {
 	var outputStrings []string
 	for i, value := range filtered {
 		if value != 0 {
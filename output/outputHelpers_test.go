@@ -2,28 +2,73 @@ package output
 
 import (
 	"archive/zip"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
 
 func Test_copyFilesToZip(t *testing.T) {
-	zipFile := CreateZip()
-	defer os.Remove("output.zip")
+	dir, err := ioutil.TempDir("", "nrdiag-zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	zipFile := CreateZip(dir)
+	defer CloseZip(zipFile)
 	type args struct {
-		dst        *zip.Writer
+		dst        *Archive
 		filesToZip []tasks.FileCopyEnvelope
 	}
 	tests := []struct {
 		name string
 		args args
 	}{
-		{"addFiles", args{zipFile, []tasks.FileCopyEnvelope{tasks.FileCopyEnvelope{Path: "tasks/fixtures/java/newrelic/newrelic.yml"}}}},
+		{"addFiles", args{zipFile, []tasks.FileCopyEnvelope{tasks.FileCopyEnvelope{Path: "../tasks/fixtures/java/newrelic/newrelic.yml"}}}},
 	}
 
 	for _, tt := range tests {
-		copyFilesToZip(tt.args.dst, tt.args.filesToZip)
+		copyFilesToZip(tt.args.dst, tt.args.filesToZip, openHostFile)
+	}
+
+}
+
+func Test_copyFilesToZipReadsTheTaskFileSystem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nrdiag-zip")
+	if err != nil {
+		t.Fatal(err)
 	}
+	defer os.RemoveAll(dir)
+	defer tasks.UseFileSystem(tasks.NewMemFS(map[string]string{
+		"/etc/newrelic/newrelic.cfg": "pidfile=/var/run/newrelic-daemon.pid\n",
+	}))()
+
+	zipFile := CreateZip(dir)
+	// a file that can't be read doesn't stop the files after it
+	copyFilesToZip(zipFile, []tasks.FileCopyEnvelope{
+		{Path: "/etc/newrelic/missing.cfg", Identifier: "PHP/Daemon/Config"},
+		{Path: "/etc/newrelic/newrelic.cfg", Identifier: "PHP/Daemon/Config"},
+	}, openHostFile)
+	CloseZip(zipFile)
 
+	reader, err := zip.OpenReader(filepath.Join(dir, "nrdiag-output.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if file.Name != "nrdiag-output/PHP/Daemon/newrelic.cfg" {
+			continue
+		}
+		content, _ := file.Open()
+		data, _ := ioutil.ReadAll(content)
+		content.Close()
+		if string(data) != "pidfile=/var/run/newrelic-daemon.pid\n" {
+			t.Errorf("Unexpected content %q", data)
+		}
+		return
+	}
+	t.Error("Expected the file from the task file system in the zip")
 }
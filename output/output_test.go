@@ -96,7 +96,7 @@ func Test_WriteLineResults(t *testing.T) {
 	}{}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := WriteLineResults(); !reflect.DeepEqual(got, tt.want) {
+			if got := WriteLineResults(nil); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("WriteLineResults() = %v, want %v", got, tt.want)
 			}
 		})
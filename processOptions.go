@@ -234,6 +234,12 @@ func processOverrides() (tasks.Options, []override) {
 		options.Options["configFile"] = config.Flags.ConfigFile
 	}
 
+	// Pass in the directory of settings specs
+	if config.Flags.SpecDir != "" {
+		log.Debug("Manually setting spec directory to ", config.Flags.SpecDir)
+		options.Options["specDir"] = config.Flags.SpecDir
+	}
+
 	// Pass in AttachmentKey file override value
 	if config.Flags.AttachmentKey != "" {
 		log.Debug("Manually setting attachment to ", config.Flags.AttachmentKey)
@@ -2,147 +2,57 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/output/color"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/engine"
+	"github.com/newrelic/newrelic-diagnostics-cli/events"
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
-	"github.com/newrelic/newrelic-diagnostics-cli/output"
 	"github.com/newrelic/newrelic-diagnostics-cli/registration"
 	"github.com/newrelic/newrelic-diagnostics-cli/suites"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
 
-func processTasksToRun() {
-
-	log.Debugf("There are %d tasks in this queue\n", len(registration.Work.WorkQueue))
+// newEngine - creates the engine running the tasks selected by the tasks and suites flag values, or all tasks if neither is set
+func newEngine(tasksFlag string, suitesFlag string, args []string, options tasks.Options, overrides []override) (*engine.Engine, error) {
+	engineOptions := engine.Options{
+		TaskOptions: options.Options,
+		Overrides:   toEngineOverrides(overrides),
+		Offline:     config.Flags.Offline,
+	}
 
-	if config.Flags.Tasks != "" {
-		taskIdentifiers := processFlagsTasks(config.Flags.Tasks)
-		registration.AddTasksByIdentifiers(taskIdentifiers)
-	} else if config.Flags.Suites != "" {
-		matchedSuites, err := processFlagsSuites(config.Flags.Suites, os.Args)
+	if tasksFlag != "" {
+		engineOptions.Tasks = processFlagsTasks(tasksFlag)
+	} else if suitesFlag != "" {
+		matchedSuites, err := processFlagsSuites(suitesFlag, args)
 		if err != nil {
-			log.Infof("\nError:\n%s", err.Error())
-			os.Exit(1)
+			return nil, err
 		}
 
 		var suiteNameList []string
 		for _, suite := range matchedSuites {
 			suiteNameList = append(suiteNameList, suite.DisplayName)
+			engineOptions.Suites = append(engineOptions.Suites, suite.Identifier)
 		}
 		log.Infof("%s %s\n", color.ColorString(color.White, "\nExecuting following diagnostic task suites:"), strings.Join(suiteNameList, ", "))
-
-		taskIdentifiers := suites.DefaultSuiteManager.FindTasksBySuites(matchedSuites)
-		registration.AddTasksByIdentifiers(taskIdentifiers)
-	} else {
-		registration.AddAllToQueue()
 	}
-	log.Debugf("There are %d tasks in this queue\n", len(registration.Work.WorkQueue))
-	registration.CompleteTaskRegistration()
+	if config.Flags.ReportEvents {
+		// make sure the region and proxy are known before posting events
+		engineOptions.AdditionalTasks = events.RequiredTasks
+	}
+	return engine.New(engineOptions)
 }
 
-func processTasks(options tasks.Options, overrides []override, wg *sync.WaitGroup) {
-	log.Debugf("work queue has %d items\n", len(registration.Work.WorkQueue))
-	taskCount := 0
-	for task := range registration.Work.WorkQueue {
-		taskCount++
-		if taskCount == 1 && !config.Flags.VeryQuiet {
-			// writes to the screen
-			output.WriteOutputHeader()
-		}
-		var taskOptions = make(map[string]string)
-		// Loop through incoming options to assign out to the named task Options to avoid carrying in the wrong options
-		for key, value := range options.Options {
-			taskOptions[key] = value
-		}
-		namedTaskOptions := tasks.Options{Options: taskOptions}
-
-		log.Debug("Running :", task.Identifier())
-		log.Debug("Incoming options are", options)
-
-		// Check for dependancies on the task and include results if dependent
-		dependentResults := make(map[string]tasks.Result)
-		for _, depIdent := range task.Dependencies() {
-			log.Debug("dependency for processing: ", depIdent)
-			dependentResults[depIdent] = registration.Work.Results[depIdent].Result
-		}
-
-		//Parse overrides to detect which task we are running
-		for _, value := range overrides {
-			// Initialize the taskOptions object
-			log.Debugf("override %s: %s", value.Identifier, value.value)
-			if strings.ToLower(value.Identifier.String()) == strings.ToLower(task.Identifier().String()) {
-				log.Debug("Adding override to task namedTaskOptions", value.key, ":", value.value)
-				namedTaskOptions.Options[value.key] = value.value
-			}
-		}
-
-		log.Debug("Starting", task.Identifier(), "with options", namedTaskOptions)
-		var result tasks.Result
-		// Check for an option key to map to Status or Payload and if so, bypass task execution
-		overrideEnabled := false
-		if _, ok := namedTaskOptions.Options["Status"]; ok {
-			log.Debug("Override Status passed in for ", task.Identifier(), "Value of ", namedTaskOptions.Options["Status"])
-
-			switch status := strings.ToLower(namedTaskOptions.Options["Status"]); status {
-			case "success":
-				result.Status = tasks.Success
-			case "warning":
-				result.Status = tasks.Warning
-			case "failure":
-				result.Status = tasks.Failure
-			case "info":
-				result.Status = tasks.Info
-			case "error":
-				result.Status = tasks.Error
-			case "none":
-				result.Status = tasks.None
-			default:
-				log.Info("Attempted to set status override to invalid status", namedTaskOptions.Options["Status"])
-			}
-
-			result.Summary += "Status set by override to " + namedTaskOptions.Options["Status"] + "\n"
-			overrideEnabled = true
-		}
-
-		if _, ok := namedTaskOptions.Options["Payload"]; ok {
-			log.Debug("Override Payload passed in for ", task.Identifier())
-			result.Payload = namedTaskOptions.Options["Payload"]
-			result.Summary += "Payload set by override\n"
-			overrideEnabled = true
-		}
-
-		if !overrideEnabled {
-			result = task.Execute(namedTaskOptions, dependentResults)
-		}
-
-		taskResult := registration.TaskResult{
-			Task:        task,
-			Result:      result,
-			WasOverride: overrideEnabled,
-		}
-
-		registration.Work.Results[task.Identifier().String()] = taskResult //This should be done in output.go but due to async causes issues
-		registration.Work.ResultsChannel <- taskResult
-
-		if len(result.FilesToCopy) > 0 {
-			log.Debug(" - writing result to file channel")
-			registration.Work.FilesChannel <- taskResult
-		}
-
-	} // end of loop to run tasks in
-
-	log.Debug("Closing task channel")
-	close(registration.Work.ResultsChannel)
-	close(registration.Work.FilesChannel)
-
-	log.Debug("Decrementing wait group in processTasks.")
-	wg.Done()
+func toEngineOverrides(overrides []override) []engine.Override {
+	var engineOverrides []engine.Override
+	for _, value := range overrides {
+		log.Debugf("override %s: %s", value.Identifier, value.value)
+		engineOverrides = append(engineOverrides, engine.Override{Identifier: value.Identifier, Key: value.key, Value: value.value})
+	}
+	return engineOverrides
 }
 
 func processFlagsTasks(flagValue string) []string {
@@ -176,7 +86,6 @@ func processFlagsSuites(flagValue string, args []string) ([]suites.Suite, error)
 	sanitizedArgs := sanitizeOSArgs(args)
 
 	matchedSuites, unMatchedSuites := suites.DefaultSuiteManager.FindSuitesByIdentifiers(suiteIdentifiers)
-	suites.DefaultSuiteManager.AddSelectedSuites(matchedSuites)
 	//arguments passed that were intended to be suites but where not include due to misformat
 	unknownArgs := suites.DefaultSuiteManager.CaptureOutOfPlaceArgs(sanitizedArgs, suiteIdentifiers)
 
@@ -201,7 +110,7 @@ func processFlagsSuites(flagValue string, args []string) ([]suites.Suite, error)
 
 }
 
-func processUploads() {
+func processUploads(results []registration.TaskResult) {
 	log.Debug("processing uploads")
 
 	//if neither attachment flags are provided
@@ -210,23 +119,28 @@ func processUploads() {
 		return
 	}
 
+	if config.Flags.Offline {
+		log.Info("Not uploading the results since -offline was used")
+		return
+	}
+
 	//get timestamp to use for both types of attachments
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 
 	if config.Flags.YesToAll {
-		checkAttachmentFlags(timestamp)
+		checkAttachmentFlags(timestamp, results)
 		return
 	}
 
 	question := "We've created nrdiag-output.zip and nrdiag-output.json\n" +
 		"Do you want to upload these to your RPM Account/Support Ticket?"
 	if promptUser(question) {
-		checkAttachmentFlags(timestamp)
+		checkAttachmentFlags(timestamp, results)
 	}
 
 }
 
-func checkAttachmentFlags(timestamp string) {
+func checkAttachmentFlags(timestamp string, results []registration.TaskResult) {
 
 	var ValidLicenseKeys []string
 
@@ -237,7 +151,7 @@ func checkAttachmentFlags(timestamp string) {
 	}
 	//check for validated license keys and upload with those keys
 	if config.Flags.AutoAttach {
-		for _, taskResult := range registration.Work.Results {
+		for _, taskResult := range results {
 			if taskResult.Task.Identifier().String() == "Base/Config/ValidateLicenseKey" && taskResult.Result.Status == tasks.Success {
 				LicenseKeys, err := getLicenseKey(taskResult.Result)
 				if err != nil {
@@ -4,7 +4,6 @@ import (
 	"os"
 	"strings"
 
-	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 	androidAgent "github.com/newrelic/newrelic-diagnostics-cli/tasks/android/agent"
 	androidConfig "github.com/newrelic/newrelic-diagnostics-cli/tasks/android/config"
 	androidLog "github.com/newrelic/newrelic-diagnostics-cli/tasks/android/log"
@@ -125,6 +124,4 @@ func init() {
 	if strings.Contains(os.Args[0], "newrelic-diagnostics-cli") {
 		template.RegisterWith(Register)
 	}
-
-	Work.WorkQueue = make(chan tasks.Task)
 }
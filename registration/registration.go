@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"regexp"
 	"strings"
+	"time"
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
@@ -19,6 +20,7 @@ type TaskResult struct {
 	Task        tasks.Task
 	Result      tasks.Result
 	WasOverride bool
+	Duration    time.Duration // how long the task took to execute
 }
 
 //MarshalJSON - custom JSON marshaling for this task, we'll strip out the passphrase to keep it only in memory, not on disk
@@ -36,23 +38,19 @@ func (tr TaskResult) MarshalJSON() ([]byte, error) {
 	})
 }
 
-// Workload - the work that has to be done for tasks
-type Workload struct {
+// Run - the work that has to be done for one execution of tasks. Each run owns a snapshot of the registered tasks,
+// its work queue, results and channels, so several runs can exist in the same process
+type Run struct {
 	WorkQueue      chan tasks.Task
 	Results        map[string]TaskResult
 	ResultsChannel chan TaskResult
 	FilesChannel   chan TaskResult
-}
-
-// Work is out "main" object that stores what we need to do
-var Work = Workload{
-	Results:        make(map[string]TaskResult),
-	ResultsChannel: make(chan TaskResult, 2),
-	FilesChannel:   make(chan TaskResult, 2),
+	registry       map[string]registeredTask
+	queuedTasks    map[tasks.Identifier]bool
+	dependencies   map[tasks.Identifier][]string
 }
 
 var registeredTasks = make(map[string]registeredTask)
-var queuedTasks = make(map[tasks.Identifier]bool)
 
 // Register - allows registration of tasks, probably only used as a callback
 // Passing false as the second option prevents the task from running by default.
@@ -61,8 +59,34 @@ func Register(t tasks.Task, runByDefault bool) {
 	registeredTasks[strings.ToLower(t.Identifier().String())] = registeredTask{Task: t, runByDefault: runByDefault}
 }
 
+// NewRun - creates a run with an empty work queue from the tasks registered so far. The queue can hold every task, so queueing never blocks
+func NewRun() *Run {
+	registry := make(map[string]registeredTask, len(registeredTasks))
+	for id, regTask := range registeredTasks {
+		registry[id] = regTask
+	}
+	return &Run{
+		WorkQueue:      make(chan tasks.Task, len(registry)),
+		Results:        make(map[string]TaskResult),
+		ResultsChannel: make(chan TaskResult, 2),
+		FilesChannel:   make(chan TaskResult, 2),
+		registry:       registry,
+		queuedTasks:    make(map[tasks.Identifier]bool),
+		dependencies:   make(map[tasks.Identifier][]string),
+	}
+}
+
 //TasksForIdentifierString - this returns the registered task(s) for a given identifier, it can have wildcards
 func TasksForIdentifierString(ident string) []tasks.Task {
+	return tasksForIdentifierString(registeredTasks, ident)
+}
+
+//TasksForIdentifierString - this returns the task(s) of the run for a given identifier, it can have wildcards
+func (r *Run) TasksForIdentifierString(ident string) []tasks.Task {
+	return tasksForIdentifierString(r.registry, ident)
+}
+
+func tasksForIdentifierString(registry map[string]registeredTask, ident string) []tasks.Task {
 	var tasks []tasks.Task
 
 	if strings.Contains(ident, "*") {
@@ -72,13 +96,13 @@ func TasksForIdentifierString(ident string) []tasks.Task {
 		if err != nil {
 			log.Info("Failed to compile identifier regex from '", matchString, "'")
 		}
-		for id, regTask := range registeredTasks {
+		for id, regTask := range registry {
 			if regTask.runByDefault && matcher.MatchString(id) {
 				tasks = append(tasks, regTask.Task)
 			}
 		}
 	} else {
-		if regTask, ok := registeredTasks[strings.ToLower(ident)]; ok {
+		if regTask, ok := registry[strings.ToLower(ident)]; ok {
 			tasks = append(tasks, regTask.Task)
 		}
 	}
@@ -87,55 +111,54 @@ func TasksForIdentifierString(ident string) []tasks.Task {
 }
 
 // AddAllToQueue - adds in all tasks that have been registered
-func AddAllToQueue() {
-	log.Debugf("Adding %d tasks to queue\n", len(registeredTasks))
-	for _, regTask := range registeredTasks {
+func (r *Run) AddAllToQueue() {
+	log.Debugf("Adding %d tasks to queue\n", len(r.registry))
+	for _, regTask := range r.registry {
 		if regTask.runByDefault {
-			AddTaskToQueue(regTask.Task)
+			r.AddTaskToQueue(regTask.Task)
 		}
 	}
-	log.Debugf("Added %d tasks to queue\n", len(Work.WorkQueue))
+	log.Debugf("Added %d tasks to queue\n", len(r.WorkQueue))
 }
 
 //AddTasksByIdentifiers - will use an identifier string to add tasks, can have wildcards
-func AddTasksByIdentifier(ident string) {
+func (r *Run) AddTasksByIdentifier(ident string) {
 	log.Debugf("asked to load %s by string\n", ident)
-	tasks := TasksForIdentifierString(ident)
+	tasks := r.TasksForIdentifierString(ident)
 	if len(tasks) == 0 {
 		log.Info("No valid tasks found! (If you used a '*' with the -t option, be sure to quote or escape the string.)")
 	} else {
 		for _, task := range tasks {
-			AddTaskToQueue(task)
+			r.AddTaskToQueue(task)
 		}
 	}
 }
 
 //AddTasksByIdentifiers - takes slice of tasks identifier strings and adds all matching tasks to work queue
-func AddTasksByIdentifiers(idents []string) {
+func (r *Run) AddTasksByIdentifiers(idents []string) {
 	for _, ident := range idents {
-		AddTasksByIdentifier(ident)
+		r.AddTasksByIdentifier(ident)
 	}
 }
 
 // AddIdentifierToQueue - adds a single Task (identified by name) to the work queue
-func AddIdentifierToQueue(ident tasks.Identifier) {
+func (r *Run) AddIdentifierToQueue(ident tasks.Identifier) {
 	log.Debugf("asked to load %s by Identifier\n", ident.String())
-	regTask := registeredTasks[strings.ToLower(ident.String())]
+	regTask := r.registry[strings.ToLower(ident.String())]
 	if regTask.Task == nil {
 		log.Debug(" * Could not find task!")
 	} else {
-		AddTaskToQueue(regTask.Task)
+		r.AddTaskToQueue(regTask.Task)
 	}
 }
 
 // AddTaskToQueue - adds in a new task and resolves it's dependencies, could be prone to dependency loops
-func AddTaskToQueue(p tasks.Task) {
-	//QueuedTasks := make(map[tasks.Identifier]string)
-	//dent := p.Identifier().String()
+func (r *Run) AddTaskToQueue(p tasks.Task) {
 	// add all the dependencies for this
-	for _, depIdent := range p.Dependencies() {
+	dependencies := p.Dependencies()
+	for _, depIdent := range dependencies {
 		log.Debugf("\tfound dependency %s\n", depIdent)
-		AddTasksByIdentifier(depIdent)
+		r.AddTasksByIdentifier(depIdent)
 	}
 
 	// somewhere in here may be a good place to detect dependency loops...
@@ -143,20 +166,26 @@ func AddTaskToQueue(p tasks.Task) {
 	// since it would be impossible to resolve the dependencies beore it runs
 
 	// if we have already created a key for the results then we aren't in the queue yet
-	log.Debug("Checking queue for ", p.Identifier(), ": ", queuedTasks[p.Identifier()])
-	if _, ok := queuedTasks[p.Identifier()]; !ok {
+	log.Debug("Checking queue for ", p.Identifier(), ": ", r.queuedTasks[p.Identifier()])
+	if _, ok := r.queuedTasks[p.Identifier()]; !ok {
 		log.Debugf("Couldn't find %s in queue set\n", p.Identifier())
-		queuedTasks[p.Identifier()] = true
+		r.queuedTasks[p.Identifier()] = true
+		r.dependencies[p.Identifier()] = dependencies
 		log.Debug("adding to queue")
-		Work.WorkQueue <- p
+		r.WorkQueue <- p
 	} else {
 		log.Debug("already had in queue: ", p.Identifier(), " or ", p.Identifier())
 	}
 	log.Debug("done with add task to queue")
 }
 
+// Dependencies - returns the dependencies a queued task had when it was added to the queue
+func (r *Run) Dependencies(t tasks.Task) []string {
+	return r.dependencies[t.Identifier()]
+}
+
 // CompleteTaskRegistration - does some clean up after the setup process
-func CompleteTaskRegistration() {
+func (r *Run) CompleteTaskRegistration() {
 	log.Debug("Closing task registration.")
-	close(Work.WorkQueue)
+	close(r.WorkQueue)
 }
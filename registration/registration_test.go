@@ -9,53 +9,44 @@ import (
 func TestRegisterSingleTask(t *testing.T) {
 	//baseConfig.LogLevel = baseConfig.Verbose
 
-	// re-init the results struct
-	Work.Results = make(map[string]TaskResult)
+	run := NewRun()
 	// make a large channel so we don't block
-	Work.WorkQueue = make(chan tasks.Task, 100)
-	// re-init the queue
-	queuedTasks = make(map[tasks.Identifier]bool)
+	run.WorkQueue = make(chan tasks.Task, 100)
 
-	AddIdentifierToQueue(tasks.IdentifierFromString("Base/Env/CollectEnvVars"))
-	CompleteTaskRegistration()
+	run.AddIdentifierToQueue(tasks.IdentifierFromString("Base/Env/CollectEnvVars"))
+	run.CompleteTaskRegistration()
 
 	//	dumpTasks(Work.Tasks)
-	//	dumpQueue(Work.WorkQueue)
-	if len(Work.WorkQueue) != 1 {
-		t.Error("WorkQueue expected to have 1 items after adding Base/Env/CollectEnvVars; has:", len(Work.WorkQueue))
+	//	dumpQueue(run.WorkQueue)
+	if len(run.WorkQueue) != 1 {
+		t.Error("WorkQueue expected to have 1 items after adding Base/Env/CollectEnvVars; has:", len(run.WorkQueue))
 	}
 }
 
 func TestRegisterDependentTasks(t *testing.T) {
 	// config.LogLevel = config.Verbose
 
-	// re-init the results struct
-	Work.Results = make(map[string]TaskResult)
+	run := NewRun()
 	// make a large channel so we don't block
-	Work.WorkQueue = make(chan tasks.Task, 100)
-	// re-init the queue
-	queuedTasks = make(map[tasks.Identifier]bool)
+	run.WorkQueue = make(chan tasks.Task, 100)
 
-	AddIdentifierToQueue(tasks.IdentifierFromString("Base/Config/Validate"))
-	CompleteTaskRegistration()
+	run.AddIdentifierToQueue(tasks.IdentifierFromString("Base/Config/Validate"))
+	run.CompleteTaskRegistration()
 
-	if len(Work.WorkQueue) != 4 { //the expected length of the queue may have to continue going up as Base/Config/Validate becomes dependent on new nrdiag tasks that must be run prior to it
-		t.Error("WorkQueue expected to have 3 items after adding Base/Config/Validate; has:", len(Work.WorkQueue))
+	if len(run.WorkQueue) != 4 { //the expected length of the queue may have to continue going up as Base/Config/Validate becomes dependent on new nrdiag tasks that must be run prior to it
+		t.Error("WorkQueue expected to have 3 items after adding Base/Config/Validate; has:", len(run.WorkQueue))
 	}
 }
 
 func TestRegisterAllTasks(t *testing.T) {
 	//baseConfig.LogLevel = baseConfig.Verbose
 
-	// re-init the results struct
-	Work.Results = make(map[string]TaskResult)
+	run := NewRun()
 	// make a large channel so we don't block
-	Work.WorkQueue = make(chan tasks.Task, 200)
+	run.WorkQueue = make(chan tasks.Task, 200)
 
-	queuedTasks = make(map[tasks.Identifier]bool)
-
-	AddAllToQueue()
-	CompleteTaskRegistration()
+	run.AddAllToQueue()
+	run.CompleteTaskRegistration()
 
 	runnableTasks := 0
 	for _, regTask := range registeredTasks {
@@ -63,8 +54,8 @@ func TestRegisterAllTasks(t *testing.T) {
 			runnableTasks++
 		}
 	}
-	if len(Work.WorkQueue) != runnableTasks {
-		t.Error("WorkQueue expected to have same number of items as Tasks: ", len(Work.WorkQueue), " vs. ", runnableTasks, "/", len(registeredTasks))
+	if len(run.WorkQueue) != runnableTasks {
+		t.Error("WorkQueue expected to have same number of items as Tasks: ", len(run.WorkQueue), " vs. ", runnableTasks, "/", len(registeredTasks))
 	}
 }
 
@@ -76,3 +67,20 @@ func TestTasksHaveValidExplain(t *testing.T) {
 		}
 	}
 }
+
+func TestRunsAreIndependent(t *testing.T) {
+	first := NewRun()
+	first.WorkQueue = make(chan tasks.Task, 100)
+	first.AddIdentifierToQueue(tasks.IdentifierFromString("Base/Env/CollectEnvVars"))
+	first.CompleteTaskRegistration()
+
+	// a task queued by one run can be queued again by the next one
+	second := NewRun()
+	second.WorkQueue = make(chan tasks.Task, 100)
+	second.AddIdentifierToQueue(tasks.IdentifierFromString("Base/Env/CollectEnvVars"))
+	second.CompleteTaskRegistration()
+
+	if len(first.WorkQueue) != 1 || len(second.WorkQueue) != 1 {
+		t.Error("Each run expected to queue Base/Env/CollectEnvVars once; queued:", len(first.WorkQueue), "and", len(second.WorkQueue))
+	}
+}
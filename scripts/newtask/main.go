@@ -0,0 +1,477 @@
+// newtask generates a task from the templates in tasks/example/template, along with its test and its registration.
+//
+// Run it from the root of the repository:
+//
+//	go run ./scripts/newtask Java/JVM/Taste -deps Base/Config/Validate -kind payload
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const modulePath = "github.com/newrelic/newrelic-diagnostics-cli"
+
+// kinds of task that can be generated, each one mirrors an example of tasks/example/template
+var kinds = []string{"info", "payload", "files"}
+
+type taskSpec struct {
+	Category     string
+	Subcategory  string
+	Name         string
+	Dependencies []string
+	Kind         string
+	Explain      string
+	RunByDefault bool
+	Suite        string
+}
+
+// Identifier is the Category/Subcategory/Name string of the task
+func (s taskSpec) Identifier() string {
+	return s.Category + "/" + s.Subcategory + "/" + s.Name
+}
+
+// StructName follows the naming of the existing tasks, e.g. JavaJVMTaste
+func (s taskSpec) StructName() string {
+	return upperFirst(s.Category) + upperFirst(s.Subcategory) + upperFirst(s.Name)
+}
+
+// FileName is the name of the task with a lowercase first letter, e.g. taste.go
+func (s taskSpec) FileName() string {
+	return lowerFirst(s.Name) + ".go"
+}
+
+// generatedFiles are the paths created or modified by generate, relative to the root of the repository
+type generatedFiles struct {
+	Created  []string
+	Modified []string
+}
+
+func main() {
+	spec, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	files, err := generate(".", spec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Unable to generate "+spec.Identifier()+": "+err.Error())
+		os.Exit(1)
+	}
+	for _, file := range files.Created {
+		fmt.Println("created  " + file)
+	}
+	for _, file := range files.Modified {
+		fmt.Println("modified " + file)
+	}
+	fmt.Printf("\nRun it with: go build && ./newrelic-diagnostics-cli -t %s\n", spec.Identifier())
+}
+
+// parseArgs reads the identifier and the flags. The identifier can be given before or after the flags
+func parseArgs(args []string) (taskSpec, error) {
+	flags := flag.NewFlagSet("newtask", flag.ContinueOnError)
+	deps := flags.String("deps", "", "Comma separated identifiers of the tasks the new task depends on, e.g. 'Base/Config/Validate,Base/Env/CollectEnvVars'")
+	kind := flags.String("kind", "info", "Kind of task to generate: info (collects information), payload (returns a struct for downstream tasks) or files (adds files to the zip file)")
+	explain := flags.String("explain", "", "Help text of the task, shown with '-h tasks'")
+	runByDefault := flags.Bool("default", true, "Run the task by default. When false it only runs when selected with -t or a suite")
+	suite := flags.String("suite", "", "Identifier of a suite to add the task to, if none of the suite's task patterns match it yet")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: go run ./scripts/newtask Category/Subcategory/Name [flags]")
+		flags.PrintDefaults()
+	}
+
+	var identifier string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		identifier, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		return taskSpec{}, err
+	}
+	if identifier == "" && flags.NArg() > 0 {
+		identifier = flags.Arg(0)
+	}
+
+	parts := strings.Split(identifier, "/")
+	if len(parts) != 3 {
+		flags.Usage()
+		return taskSpec{}, errors.New("the identifier of the task must have the format Category/Subcategory/Name")
+	}
+	for _, part := range parts {
+		if !isIdentifier(part) {
+			return taskSpec{}, fmt.Errorf("'%s' is not a valid part of a task identifier, use letters and digits starting with a letter", part)
+		}
+	}
+	if !containsString(kinds, *kind) {
+		return taskSpec{}, fmt.Errorf("invalid -kind '%s'. Accepted values: %s", *kind, strings.Join(kinds, ", "))
+	}
+
+	spec := taskSpec{
+		Category:     parts[0],
+		Subcategory:  parts[1],
+		Name:         parts[2],
+		Kind:         *kind,
+		Explain:      *explain,
+		RunByDefault: *runByDefault,
+		Suite:        *suite,
+	}
+	for _, dep := range strings.Split(*deps, ",") {
+		if dep = strings.TrimSpace(dep); dep == "" {
+			continue
+		}
+		if len(strings.Split(dep, "/")) != 3 {
+			return taskSpec{}, fmt.Errorf("dependency '%s' must have the format Category/Subcategory/Name", dep)
+		}
+		spec.Dependencies = append(spec.Dependencies, dep)
+	}
+	if spec.Explain == "" {
+		spec.Explain = "TODO: describe what " + spec.Identifier() + " checks"
+	}
+	return spec, nil
+}
+
+// generate writes the task, its test and its registration into the repository at root
+func generate(root string, spec taskSpec) (generatedFiles, error) {
+	var files generatedFiles
+	registerTasksPath := filepath.Join(root, "registration", "registerTasks.go")
+	if _, err := os.Stat(registerTasksPath); err != nil {
+		return files, errors.New("registration/registerTasks.go not found, run the command from the root of the repository")
+	}
+
+	existing, err := findIdentifier(filepath.Join(root, "tasks"), spec.Identifier())
+	if err != nil {
+		return files, err
+	}
+	if existing != "" {
+		return files, fmt.Errorf("a task with this identifier already exists in %s", existing)
+	}
+
+	packageDir, err := findPackageDir(root, spec)
+	if err != nil {
+		return files, err
+	}
+	taskPath := filepath.Join(packageDir, spec.FileName())
+	testPath := strings.TrimSuffix(taskPath, ".go") + "_test.go"
+	for _, path := range []string{taskPath, testPath} {
+		if _, err := os.Stat(path); err == nil {
+			return files, errors.New(path + " already exists")
+		}
+	}
+
+	packageName, registerPath, err := findRegisterFile(packageDir)
+	if err != nil {
+		return files, err
+	}
+	newPackage := registerPath == ""
+	if newPackage {
+		packageName = strings.ToLower(filepath.Base(packageDir))
+		registerPath = filepath.Join(packageDir, packageName+".go")
+	}
+
+	data := templateData{
+		taskSpec:    spec,
+		Package:     packageName,
+		NeedsSuite:  !hasTestSuite(packageDir),
+		SuiteName:   "Test" + upperFirst(spec.Category) + upperFirst(spec.Subcategory),
+		Registrator: registrationFor(spec),
+	}
+	if newPackage {
+		if err := os.MkdirAll(packageDir, 0755); err != nil {
+			return files, err
+		}
+		if err := writeTemplate(registerPath, registerTemplate, data); err != nil {
+			return files, err
+		}
+		if err := addPackageRegistration(registerTasksPath, packageDir, root, spec); err != nil {
+			return files, err
+		}
+		files.Created = append(files.Created, registerPath)
+		files.Modified = append(files.Modified, registerTasksPath)
+	} else {
+		if err := addTaskRegistration(registerPath, data.Registrator); err != nil {
+			return files, err
+		}
+		files.Modified = append(files.Modified, registerPath)
+	}
+
+	if err := writeTemplate(taskPath, taskTemplate, data); err != nil {
+		return files, err
+	}
+	if err := writeTemplate(testPath, testTemplate, data); err != nil {
+		return files, err
+	}
+	files.Created = append(files.Created, taskPath, testPath)
+
+	if spec.Suite != "" {
+		suitesPath := filepath.Join(root, "suites", "suiteDefinitions.go")
+		modified, err := addToSuite(suitesPath, spec.Suite, spec.Identifier())
+		if err != nil {
+			return files, err
+		}
+		if modified {
+			files.Modified = append(files.Modified, suitesPath)
+		}
+	}
+	return files, nil
+}
+
+// findIdentifier returns the file that already declares the identifier, if any
+func findIdentifier(tasksDir string, identifier string) (string, error) {
+	declaration := fmt.Sprintf("IdentifierFromString(%q)", identifier)
+	var found string
+	err := filepath.Walk(tasksDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || found != "" || info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(content, []byte(declaration)) {
+			found = path
+		}
+		return nil
+	})
+	return found, err
+}
+
+// findPackageDir returns tasks/<category>/<subcategory>, reusing the existing directories whatever their case, e.g. tasks/iOS or tasks/dotnetcore
+func findPackageDir(root string, spec taskSpec) (string, error) {
+	dir := filepath.Join(root, "tasks")
+	for _, part := range []string{spec.Category, spec.Subcategory} {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		next := filepath.Join(dir, strings.ToLower(part))
+		for _, entry := range entries {
+			if entry.IsDir() && strings.EqualFold(entry.Name(), part) {
+				next = filepath.Join(dir, entry.Name())
+				break
+			}
+		}
+		dir = next
+	}
+	return dir, nil
+}
+
+var packageRegexp = regexp.MustCompile(`(?m)^package (\w+)`)
+
+// findRegisterFile returns the package name and the file declaring RegisterWith in the package directory, or empty strings for a new package
+func findRegisterFile(packageDir string) (string, string, error) {
+	paths, _ := filepath.Glob(filepath.Join(packageDir, "*.go"))
+	sort.Strings(paths)
+	packageName := ""
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", "", err
+		}
+		if match := packageRegexp.FindSubmatch(content); match != nil {
+			packageName = string(match[1])
+		}
+		if bytes.Contains(content, []byte("func RegisterWith(")) {
+			return packageName, path, nil
+		}
+	}
+	if packageName != "" {
+		return "", "", errors.New(packageDir + " has no RegisterWith function to register the task with")
+	}
+	return "", "", nil
+}
+
+// hasTestSuite returns true if a test of the package already runs the ginkgo specs
+func hasTestSuite(packageDir string) bool {
+	paths, _ := filepath.Glob(filepath.Join(packageDir, "*_test.go"))
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err == nil && bytes.Contains(content, []byte("RunSpecs(")) {
+			return true
+		}
+	}
+	return false
+}
+
+// registrationFor returns the call registering the task, injecting the helpers the kind of task depends on
+func registrationFor(spec taskSpec) string {
+	fields := ""
+	if spec.Kind == "files" {
+		fields = "\n\t\tfindFiles: tasks.FindFiles,\n\t"
+	}
+	return fmt.Sprintf("registrationFunc(%s{%s}, %t)", spec.StructName(), fields, spec.RunByDefault)
+}
+
+// addTaskRegistration adds the registration call at the end of the RegisterWith function of the package
+func addTaskRegistration(registerPath string, registration string) error {
+	content, err := ioutil.ReadFile(registerPath)
+	if err != nil {
+		return err
+	}
+	source := string(content)
+	start := strings.Index(source, "func RegisterWith(")
+	end := strings.Index(source[start:], "\n}")
+	if end == -1 {
+		return errors.New("unable to find the end of RegisterWith in " + registerPath)
+	}
+	end += start
+	source = strings.TrimRight(source[:end], "\n\t ") + "\n\t" + registration + source[end:]
+	return ioutil.WriteFile(registerPath, []byte(source), 0644)
+}
+
+// addPackageRegistration imports a new task package in registerTasks.go and registers its tasks after the other packages
+func addPackageRegistration(registerTasksPath string, packageDir string, root string, spec taskSpec) error {
+	content, err := ioutil.ReadFile(registerTasksPath)
+	if err != nil {
+		return err
+	}
+	relativeDir, err := filepath.Rel(root, packageDir)
+	if err != nil {
+		return err
+	}
+	importPath := modulePath + "/" + filepath.ToSlash(relativeDir)
+	alias := lowerFirst(spec.Category) + upperFirst(spec.Subcategory)
+	importLine := fmt.Sprintf("\t%s %q", alias, importPath)
+
+	lines := strings.Split(string(content), "\n")
+	var result []string
+	imported, registered := false, false
+	inImports := false
+	lastRegistration := -1
+	for i, line := range lines {
+		// the example tasks are registered in a nested block, the other packages directly in init
+		if strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "\t\t") && strings.HasSuffix(line, ".RegisterWith(Register)") {
+			lastRegistration = i
+		}
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "import (" {
+			inImports = true
+		} else if inImports && !imported {
+			// keep the task packages sorted by import path
+			if trimmed == ")" || (strings.Contains(trimmed, modulePath+"/tasks/") && importPathOf(trimmed) > importPath) {
+				result = append(result, importLine)
+				imported = true
+			}
+		}
+		if trimmed == ")" {
+			inImports = false
+		}
+		result = append(result, line)
+		if i == lastRegistration {
+			result = append(result, fmt.Sprintf("\t%s.RegisterWith(Register)", alias))
+			registered = true
+		}
+	}
+	if !imported || !registered {
+		return errors.New("unable to add " + importPath + " to " + registerTasksPath)
+	}
+	return ioutil.WriteFile(registerTasksPath, []byte(strings.Join(result, "\n")), 0644)
+}
+
+func importPathOf(importSpec string) string {
+	start := strings.Index(importSpec, "\"")
+	if start == -1 {
+		return ""
+	}
+	return strings.Trim(importSpec[start:], "\"")
+}
+
+// addToSuite adds the identifier to the tasks of a suite, unless one of the suite's patterns already matches it
+func addToSuite(suitesPath string, suite string, identifier string) (bool, error) {
+	content, err := ioutil.ReadFile(suitesPath)
+	if err != nil {
+		return false, err
+	}
+	source := string(content)
+	start := strings.Index(source, fmt.Sprintf("Identifier:  %q,", suite))
+	if start == -1 {
+		return false, errors.New("suite " + suite + " not found in " + suitesPath)
+	}
+	tasksStart := strings.Index(source[start:], "Tasks: []string{")
+	if tasksStart == -1 {
+		return false, errors.New("unable to find the tasks of suite " + suite)
+	}
+	tasksStart += start
+	tasksEnd := strings.Index(source[tasksStart:], "\n\t\t},")
+	if tasksEnd == -1 {
+		return false, errors.New("unable to find the tasks of suite " + suite)
+	}
+	tasksEnd += tasksStart
+
+	for _, match := range regexp.MustCompile(`"([^"]+)"`).FindAllStringSubmatch(source[tasksStart:tasksEnd], -1) {
+		pattern := "(?i)^" + strings.Replace(regexp.QuoteMeta(match[1]), `\*`, ".*", -1) + "$"
+		if matched, _ := regexp.MatchString(pattern, identifier); matched {
+			return false, nil
+		}
+	}
+	source = source[:tasksEnd] + fmt.Sprintf("\n\t\t\t%q,", identifier) + source[tasksEnd:]
+	return true, ioutil.WriteFile(suitesPath, []byte(source), 0644)
+}
+
+type templateData struct {
+	taskSpec
+	Package     string
+	NeedsSuite  bool
+	SuiteName   string
+	Registrator string
+}
+
+// writeTemplate renders a template to a gofmt'ed file
+func writeTemplate(path string, tmpl *template.Template, data templateData) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated invalid code for %s: %s", path, err)
+	}
+	return ioutil.WriteFile(path, source, 0644)
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
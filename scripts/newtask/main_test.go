@@ -0,0 +1,117 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMain(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "New task generator test suite")
+}
+
+// copyToRoot copies a file of the repository into the same path below root
+func copyToRoot(root string, path string) {
+	content, err := ioutil.ReadFile(filepath.Join("..", "..", path))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0755)).To(Succeed())
+	Expect(ioutil.WriteFile(filepath.Join(root, path), content, 0644)).To(Succeed())
+}
+
+func readFromRoot(root string, path string) string {
+	content, err := ioutil.ReadFile(filepath.Join(root, path))
+	Expect(err).NotTo(HaveOccurred())
+	return string(content)
+}
+
+var _ = Describe("newtask", func() {
+
+	Describe("parseArgs()", func() {
+		It("should accept the identifier before the flags", func() {
+			spec, err := parseArgs([]string{"Java/JVM/Taste", "-deps", "Base/Config/Validate, Base/Env/CollectEnvVars", "-kind", "files"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.StructName()).To(Equal("JavaJVMTaste"))
+			Expect(spec.Dependencies).To(Equal([]string{"Base/Config/Validate", "Base/Env/CollectEnvVars"}))
+			Expect(spec.Kind).To(Equal("files"))
+			Expect(spec.RunByDefault).To(BeTrue())
+		})
+		It("should accept the identifier after the flags", func() {
+			spec, err := parseArgs([]string{"-default=false", "Java/JVM/Taste"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Identifier()).To(Equal("Java/JVM/Taste"))
+			Expect(spec.RunByDefault).To(BeFalse())
+		})
+		It("should reject an invalid identifier or kind", func() {
+			_, err := parseArgs([]string{"Java/Taste"})
+			Expect(err).To(HaveOccurred())
+			_, err = parseArgs([]string{"Java/JVM/Taste-Coffee"})
+			Expect(err).To(HaveOccurred())
+			_, err = parseArgs([]string{"Java/JVM/Taste", "-kind", "coffee"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("generate()", func() {
+		var root string
+
+		BeforeEach(func() {
+			var err error
+			root, err = ioutil.TempDir("", "newtask")
+			Expect(err).NotTo(HaveOccurred())
+			copyToRoot(root, "registration/registerTasks.go")
+			copyToRoot(root, "suites/suiteDefinitions.go")
+			copyToRoot(root, "tasks/java/jvm/jvm.go")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(root)
+		})
+
+		Context("when the package exists", func() {
+			It("should register the task with the package", func() {
+				spec, _ := parseArgs([]string{"Java/JVM/Taste", "-kind", "payload", "-suite", "java"})
+				files, err := generate(root, spec)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files.Created).To(Equal([]string{
+					filepath.Join(root, "tasks/java/jvm/taste.go"),
+					filepath.Join(root, "tasks/java/jvm/taste_test.go"),
+				}))
+				// Java/* already selects the task
+				Expect(files.Modified).To(Equal([]string{filepath.Join(root, "tasks/java/jvm/jvm.go")}))
+
+				Expect(readFromRoot(root, "tasks/java/jvm/jvm.go")).To(ContainSubstring("\tregistrationFunc(JavaJVMTaste{}, true)\n}"))
+				Expect(readFromRoot(root, "tasks/java/jvm/taste.go")).To(ContainSubstring("type JavaJVMTastePayload struct"))
+				Expect(readFromRoot(root, "tasks/java/jvm/taste_test.go")).To(ContainSubstring("RunSpecs(t, \"Java/JVM/* test suite\")"))
+			})
+		})
+
+		Context("when the package is new", func() {
+			It("should create the package and register it", func() {
+				spec, _ := parseArgs([]string{"Node/Widgets/Count", "-kind", "files", "-deps", "Base/Env/CollectEnvVars", "-suite", "java"})
+				_, err := generate(root, spec)
+				Expect(err).NotTo(HaveOccurred())
+
+				registerTasks := readFromRoot(root, "registration/registerTasks.go")
+				Expect(registerTasks).To(ContainSubstring("\tnodeLog \"github.com/newrelic/newrelic-diagnostics-cli/tasks/node/log\"\n\tnodeWidgets \"github.com/newrelic/newrelic-diagnostics-cli/tasks/node/widgets\"\n"))
+				Expect(registerTasks).To(ContainSubstring("\tnodeWidgets.RegisterWith(Register)\n\n"))
+				Expect(readFromRoot(root, "tasks/node/widgets/widgets.go")).To(ContainSubstring("registrationFunc(NodeWidgetsCount{\n\t\tfindFiles: tasks.FindFiles,\n\t}, true)"))
+				Expect(readFromRoot(root, "tasks/node/widgets/count.go")).To(ContainSubstring("if !upstream[\"Base/Env/CollectEnvVars\"].HasPayload() {"))
+				Expect(readFromRoot(root, "suites/suiteDefinitions.go")).To(ContainSubstring("\"Java/*\",\n\t\t\t\"Node/Widgets/Count\",\n\t\t},"))
+			})
+		})
+
+		Context("when the task already exists", func() {
+			It("should return an error", func() {
+				copyToRoot(root, "tasks/java/jvm/vendorsVersions.go")
+				spec, _ := parseArgs([]string{"Java/JVM/VendorsVersions"})
+				_, err := generate(root, spec)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
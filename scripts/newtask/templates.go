@@ -0,0 +1,212 @@
+package main
+
+import "text/template"
+
+// PatternsVar is the variable holding the file name patterns of a files task
+func (s taskSpec) PatternsVar() string {
+	return lowerFirst(s.Name) + "Patterns"
+}
+
+var registerTemplate = template.Must(template.New("register").Parse(`package {{.Package}}
+
+import (
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// RegisterWith - will register any plugins in this package
+func RegisterWith(registrationFunc func(tasks.Task, bool)) {
+	log.Debug("Registering {{.Category}}/{{.Subcategory}}/*")
+
+	{{.Registrator}}
+}
+`))
+
+var taskTemplate = template.Must(template.New("task").Parse(`package {{.Package}}
+
+import (
+{{- if eq .Kind "files"}}
+	"fmt"
+{{end}}
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// {{.StructName}} - {{.Explain}}
+type {{.StructName}} struct {
+{{- if eq .Kind "files"}}
+	findFiles func(patterns []string, paths []string) []string
+{{- end}}
+}
+{{if eq .Kind "payload"}}
+// {{.StructName}}Payload - the data {{.Identifier}} passes to the tasks depending on it
+type {{.StructName}}Payload struct {
+	// TODO: replace with the fields downstream tasks need
+	Found bool
+}
+{{end}}
+{{- if eq .Kind "files"}}
+// {{.PatternsVar}} are the names of the files {{.Identifier}} adds to nrdiag-output.zip
+var {{.PatternsVar}} = []string{
+	// TODO: replace with the files to collect
+	"newrelic_agent\\.log$",
+}
+{{end}}
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p {{.StructName}}) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("{{.Identifier}}")
+}
+
+// Explain - Returns the help text for each individual task
+func (p {{.StructName}}) Explain() string {
+	return {{printf "%q" .Explain}}
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p {{.StructName}}) Dependencies() []string {
+	return []string{
+{{- range .Dependencies}}
+		{{printf "%q" .}},
+{{- end}}
+	}
+}
+
+// Execute - The core work within each task
+func (p {{.StructName}}) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+{{- range .Dependencies}}
+	if !upstream[{{printf "%q" .}}].HasPayload() {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: {{printf "%q" (print . " did not return any data, this task did not run.")}},
+		}
+	}
+{{end}}
+{{- if eq .Kind "info"}}
+	// TODO: gather the information
+	return tasks.Result{
+		Status:  tasks.Info,
+		Summary: "TODO: summarize the information gathered",
+		Payload: "TODO: the data downstream tasks can use",
+	}
+{{- else if eq .Kind "payload"}}
+	// TODO: fill the payload
+	payload := {{.StructName}}Payload{Found: true}
+
+	return tasks.Result{
+		Status:  tasks.Success,
+		Summary: "TODO: summarize what was found",
+		Payload: payload,
+	}
+{{- else}}
+	files := p.findFiles({{.PatternsVar}}, tasks.GetDiscoveryDirectories())
+	if len(files) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No files to collect were found.",
+		}
+	}
+
+	return tasks.Result{
+		Status:      tasks.Success,
+		Summary:     fmt.Sprintf("Found %d file(s) to add to nrdiag-output.zip.", len(files)),
+		FilesToCopy: tasks.StringsToFileCopyEnvelopes(files),
+	}
+{{- end}}
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`package {{.Package}}
+
+import (
+{{- if .NeedsSuite}}
+	"testing"
+{{end}}
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+{{if .NeedsSuite}}
+func {{.SuiteName}}(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "{{.Category}}/{{.Subcategory}}/* test suite")
+}
+{{end}}
+var _ = Describe("{{.Identifier}}", func() {
+
+	var p {{.StructName}}
+
+	// upstreamResults returns a successful result for every dependency, except the failed ones
+	upstreamResults := func(failed ...string) map[string]tasks.Result {
+		upstream := map[string]tasks.Result{}
+		for _, dependency := range p.Dependencies() {
+			upstream[dependency] = tasks.Result{Status: tasks.Success}
+		}
+		for _, dependency := range failed {
+			upstream[dependency] = tasks.Result{Status: tasks.Failure}
+		}
+		return upstream
+	}
+
+	Describe("Identifier()", func() {
+		It("Should return correct identifier", func() {
+			expectedIdentifier := tasks.Identifier{
+				Category:    "{{.Category}}",
+				Subcategory: "{{.Subcategory}}",
+				Name:        "{{.Name}}",
+			}
+			Expect(p.Identifier()).To(Equal(expectedIdentifier))
+		})
+	})
+
+	Describe("Explain()", func() {
+		It("Should return correct explain string", func() {
+			Expect(p.Explain()).To(Equal({{printf "%q" .Explain}}))
+		})
+	})
+
+	Describe("Dependencies()", func() {
+		It("Should return an expected slice of dependencies", func() {
+			Expect(p.Dependencies()).To(Equal([]string{
+{{- range .Dependencies}}
+				{{printf "%q" .}},
+{{- end}}
+			}))
+		})
+	})
+
+	Describe("Execute()", func() {
+{{- if eq .Kind "files"}}
+		DescribeTable("should return the expected status",
+			func(upstream map[string]tasks.Result, foundFiles []string, expectedStatus tasks.Status) {
+				p = {{.StructName}}{
+					findFiles: func([]string, []string) []string { return foundFiles },
+				}
+				result := p.Execute(tasks.Options{}, upstream)
+				Expect(result.Status).To(Equal(expectedStatus))
+				Expect(result.FilesToCopy).To(HaveLen(len(foundFiles)))
+			},
+{{- range .Dependencies}}
+			Entry("when {{.}} did not return any data", upstreamResults({{printf "%q" .}}), []string{}, tasks.None),
+{{- end}}
+			Entry("when no files are found", upstreamResults(), []string{}, tasks.None),
+			Entry("when files are found", upstreamResults(), []string{"/var/log/newrelic/newrelic_agent.log"}, tasks.Success),
+		)
+{{- else}}
+		DescribeTable("should return the expected status",
+			func(upstream map[string]tasks.Result, expectedStatus tasks.Status) {
+				result := p.Execute(tasks.Options{}, upstream)
+				Expect(result.Status).To(Equal(expectedStatus))
+			},
+{{- range .Dependencies}}
+			Entry("when {{.}} did not return any data", upstreamResults({{printf "%q" .}}), tasks.None),
+{{- end}}
+{{- if eq .Kind "info"}}
+			Entry("when the information is gathered", upstreamResults(), tasks.Info),
+{{- else}}
+			Entry("when the payload is returned", upstreamResults(), tasks.Success),
+{{- end}}
+		)
+{{- end}}
+	})
+})
+`))
@@ -0,0 +1,64 @@
+// specgen writes the settings specs of tasks/base/config/specs into a Go file, so they are built into nrdiag
+// while being maintained as YAML files. The -spec-dir flag loads newer versions of the same files at run time.
+//
+// It runs through go generate after editing a spec:
+//
+//	go generate ./tasks/base/config
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", "specs", "Directory holding the <name>.yml spec files")
+	out := flag.String("out", "builtinSpecs.go", "Go file to write")
+	pkg := flag.String("package", "config", "Package of the Go file")
+	flag.Parse()
+
+	source, err := generate(*dir, *pkg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Unable to generate the builtin specs: "+err.Error())
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(*out, source, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// generate returns the formatted Go source declaring builtinSettingsSpecs, the specs of dir keyed by their name
+func generate(dir string, pkg string) ([]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by scripts/specgen from the files of %s; DO NOT EDIT.\n\n", filepath.ToSlash(dir))
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("// builtinSettingsSpecs are the settings specs built into nrdiag, keyed by their file name without extension\n")
+	buf.WriteString("var builtinSettingsSpecs = map[string]string{\n")
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Contains(content, []byte("`")) {
+			return nil, fmt.Errorf("%s contains a backquote, which can't be written in a raw string", path)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".yml")
+		fmt.Fprintf(&buf, "%q: `%s`,\n", name, content)
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
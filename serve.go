@@ -0,0 +1,458 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/output"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/suites"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+const (
+	runStatusRunning   = "running"
+	runStatusCompleted = "completed"
+	runStatusFailed    = "failed"
+)
+
+// serveRunRequest is the JSON body accepted to start a run. Suites, tasks and overrides take the same values as the -suites, -tasks and -override flags
+type serveRunRequest struct {
+	Suites    []string `json:"suites"`
+	Tasks     []string `json:"tasks"`
+	Overrides []string `json:"overrides"`
+}
+
+// serveRun tracks a run started through the API and the results it has produced so far
+type serveRun struct {
+	ID         string                    `json:"id"`
+	Status     string                    `json:"status"`
+	Error      string                    `json:"error,omitempty"`
+	StartedAt  time.Time                 `json:"startedAt"`
+	FinishedAt *time.Time                `json:"finishedAt,omitempty"`
+	Request    serveRunRequest           `json:"request"`
+	Results    []registration.TaskResult `json:"results"`
+	outputPath string
+	mutex      sync.Mutex
+	changed    chan struct{} // closed and replaced every time the run changes, to wake up event streams
+}
+
+type taskDescription struct {
+	Identifier   string   `json:"identifier"`
+	Explain      string   `json:"explain"`
+	Dependencies []string `json:"dependencies"`
+}
+
+// serveTokenFile is where a generated token is saved, in the -serve output directory, so it never shows up in the console or its logs
+const serveTokenFile = "serve-token"
+
+// diagServer exposes the diagnostics over a REST API. Only one run executes at a time since the tasks share the host
+// they diagnose; each run writes its output files to its own directory under outputPath
+type diagServer struct {
+	token      string
+	options    tasks.Options
+	outputPath string
+	runs       map[string]*serveRun
+	activeRun  *serveRun
+	mutex      sync.Mutex
+}
+
+// processServe starts the API server on the given address and blocks until it stops. Without -serve-tls-cert and
+// -serve-tls-key the API is served over plain HTTP, so it only listens on a loopback address
+func processServe(address string, options tasks.Options) int {
+	tlsConfig, err := serveTLSConfig(config.Flags.ServeTLSCert, config.Flags.ServeTLSKey)
+	if err != nil {
+		log.Info("Unable to use the -serve-tls-cert and -serve-tls-key certificate:", err)
+		return exitCodeBadInput
+	}
+	if err := checkServeAddress(address, tlsConfig != nil); err != nil {
+		log.Info("Invalid -serve address:", err)
+		return exitCodeBadInput
+	}
+	outputPath := filepath.Join(config.Flags.OutputPath, "nrdiag-serve")
+
+	token := config.Flags.ServeToken
+	if token == "" {
+		token = os.Getenv("NRDIAG_SERVE_TOKEN")
+	}
+	if token == "" {
+		token = generateToken()
+		tokenFile := filepath.Join(outputPath, serveTokenFile)
+		if err := writeServeToken(tokenFile, token); err != nil {
+			log.Info("Unable to save the generated token, provide one with -serve-token or NRDIAG_SERVE_TOKEN:", err)
+			return exitCodeOutputError
+		}
+		log.Infof("No token provided with -serve-token or NRDIAG_SERVE_TOKEN, the generated token was saved to %s\n", tokenFile)
+	}
+
+	// tasks can't prompt for input
+	runOptions := tasks.Options{Options: map[string]string{}}
+	for key, value := range options.Options {
+		runOptions.Options[key] = value
+	}
+	runOptions.Options["YesToAll"] = "true"
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Info("Unable to serve diagnostics:", err)
+		return exitCodeServeError
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+		scheme = "https"
+	}
+	server := newDiagServer(token, runOptions, outputPath)
+	log.Infof("Serving diagnostics on %s://%s\n", scheme, listener.Addr())
+	err = http.Serve(listener, server.router())
+	log.Info("Server stopped:", err)
+	return exitCodeServeError
+}
+
+// serveTLSConfig loads the certificate the API is served with, or returns nil when neither file is given
+func serveTLSConfig(certFile string, keyFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both -serve-tls-cert and -serve-tls-key must be provided")
+	}
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// checkServeAddress accepts an address reachable from other hosts only when the API is served over TLS. The address
+// keeps its net.Listen meaning, e.g. ":8765" listens on every interface and so requires TLS
+func checkServeAddress(address string, useTLS bool) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if useTLS || isLoopbackHost(host) {
+		return nil
+	}
+	return fmt.Errorf("%s accepts connections from other hosts, which requires serving over TLS with -serve-tls-cert and -serve-tls-key. Use 127.0.0.1:<port> to only accept connections from this host", address)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// writeServeToken saves the token readable only by the user running nrdiag
+func writeServeToken(tokenFile string, token string) error {
+	if err := os.MkdirAll(filepath.Dir(tokenFile), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(tokenFile, []byte(token+"\n"), 0600)
+}
+
+func newDiagServer(token string, options tasks.Options, outputPath string) *diagServer {
+	return &diagServer{
+		token:      token,
+		options:    options,
+		outputPath: outputPath,
+		runs:       make(map[string]*serveRun),
+	}
+}
+
+func (s *diagServer) router() *mux.Router {
+	r := mux.NewRouter()
+	r.Use(s.authenticate)
+	r.HandleFunc("/tasks", s.listTasks).Methods("GET")
+	r.HandleFunc("/suites", s.listSuites).Methods("GET")
+	r.HandleFunc("/runs", s.startRun).Methods("POST")
+	r.HandleFunc("/runs/{id}", s.getRun).Methods("GET")
+	r.HandleFunc("/runs/{id}/events", s.streamRun).Methods("GET")
+	r.HandleFunc("/runs/{id}/output.json", s.downloadFile("nrdiag-output.json", "application/json")).Methods("GET")
+	r.HandleFunc("/runs/{id}/output.zip", s.downloadFile("nrdiag-output.zip", "application/zip")).Methods("GET")
+	return r
+}
+
+// authenticate requires every request to carry the token as "Authorization: Bearer <token>"
+func (s *diagServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		provided := strings.TrimPrefix(authorization, "Bearer ")
+		if provided == authorization || subtle.ConstantTimeCompare([]byte(provided), []byte(s.token)) != 1 {
+			writeJSONError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *diagServer) listTasks(w http.ResponseWriter, r *http.Request) {
+	allTasks := registration.TasksForIdentifierString("*")
+	sort.Sort(tasks.ByIdentifier(allTasks))
+
+	descriptions := []taskDescription{}
+	for _, task := range allTasks {
+		descriptions = append(descriptions, taskDescription{
+			Identifier:   task.Identifier().String(),
+			Explain:      task.Explain(),
+			Dependencies: task.Dependencies(),
+		})
+	}
+	writeJSON(w, http.StatusOK, descriptions)
+}
+
+func (s *diagServer) listSuites(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, suites.DefaultSuiteManager.Suites)
+}
+
+func (s *diagServer) startRun(w http.ResponseWriter, r *http.Request) {
+	var request serveRunRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+	if len(request.Suites) > 0 {
+		if _, unmatched := suites.DefaultSuiteManager.FindSuitesByIdentifiers(request.Suites); len(unmatched) > 0 {
+			writeJSONError(w, http.StatusBadRequest, "unknown suites: "+strings.Join(unmatched, ", "))
+			return
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.activeRun != nil {
+		writeJSONError(w, http.StatusConflict, "run "+s.activeRun.ID+" is still in progress")
+		return
+	}
+
+	run := &serveRun{
+		ID:        generateRunID(),
+		Status:    runStatusRunning,
+		StartedAt: time.Now(),
+		Request:   request,
+		Results:   []registration.TaskResult{},
+		changed:   make(chan struct{}),
+	}
+	run.outputPath = filepath.Join(s.outputPath, run.ID) + string(filepath.Separator)
+	s.runs[run.ID] = run
+	s.activeRun = run
+
+	go s.executeRun(run)
+
+	writeJSON(w, http.StatusAccepted, run.snapshot())
+}
+
+// executeRun runs the requested tasks the same way a command line run does, writing the output files to the run's own directory
+func (s *diagServer) executeRun(run *serveRun) {
+	defer func() {
+		s.mutex.Lock()
+		s.activeRun = nil
+		s.mutex.Unlock()
+	}()
+
+	err := os.MkdirAll(run.outputPath, 0777)
+	if err != nil {
+		run.finish(err)
+		return
+	}
+
+	overrides := parseOverrides(strings.Join(run.Request.Overrides, ","))
+	diagEngine, err := newEngine(strings.Join(run.Request.Tasks, ","), strings.Join(run.Request.Suites, ","), nil, s.options, overrides)
+	if err != nil {
+		run.finish(err)
+		return
+	}
+	diagEngine.Start(context.Background())
+
+	var wg sync.WaitGroup
+	zipfile := output.CreateZip(run.outputPath)
+	wg.Add(1)
+	go output.ProcessFilesChannel(zipfile, diagEngine.Files(), &wg)
+
+	var results []registration.TaskResult
+	for result := range diagEngine.Results() {
+		results = append(results, result)
+		run.addResult(result)
+	}
+	wg.Wait()
+
+	err = output.WriteOutputFile(run.outputPath, results)
+	output.CopyOutputToZip(zipfile)
+	output.CloseZip(zipfile)
+	run.finish(err)
+}
+
+func (s *diagServer) findRun(w http.ResponseWriter, r *http.Request) *serveRun {
+	s.mutex.Lock()
+	run, ok := s.runs[mux.Vars(r)["id"]]
+	s.mutex.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "run not found")
+		return nil
+	}
+	return run
+}
+
+func (s *diagServer) getRun(w http.ResponseWriter, r *http.Request) {
+	if run := s.findRun(w, r); run != nil {
+		writeJSON(w, http.StatusOK, run.snapshot())
+	}
+}
+
+// streamRun sends every result of the run as a Server-Sent Event, starting with the ones already completed, followed by a final "done" event
+func (s *diagServer) streamRun(w http.ResponseWriter, r *http.Request) {
+	run := s.findRun(w, r)
+	if run == nil {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	sent := 0
+	for {
+		run.mutex.Lock()
+		pending := run.Results[sent:]
+		finished := run.Status != runStatusRunning
+		changed := run.changed
+		run.mutex.Unlock()
+
+		for _, result := range pending {
+			writeEvent(w, "result", result)
+			sent++
+		}
+		if finished {
+			writeEvent(w, "done", run.snapshot())
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *diagServer) downloadFile(fileName string, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		run := s.findRun(w, r)
+		if run == nil {
+			return
+		}
+		if run.snapshot().Status == runStatusRunning {
+			writeJSONError(w, http.StatusConflict, "run "+run.ID+" is still in progress")
+			return
+		}
+		filePath := filepath.Join(run.outputPath, fileName)
+		if _, err := os.Stat(filePath); err != nil {
+			writeJSONError(w, http.StatusNotFound, fileName+" was not created for this run")
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
+		http.ServeFile(w, r, filePath)
+	}
+}
+
+func (run *serveRun) addResult(result registration.TaskResult) {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+	run.Results = append(run.Results, result)
+	run.notify()
+}
+
+func (run *serveRun) finish(err error) {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = runStatusCompleted
+	if err != nil {
+		run.Status = runStatusFailed
+		run.Error = err.Error()
+	}
+	run.notify()
+}
+
+// notify wakes up everyone waiting on the run, must be called with the mutex held
+func (run *serveRun) notify() {
+	close(run.changed)
+	run.changed = make(chan struct{})
+}
+
+// snapshot returns a copy of the run that is safe to encode while the run continues
+func (run *serveRun) snapshot() *serveRun {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+	return &serveRun{
+		ID:         run.ID,
+		Status:     run.Status,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Request:    run.Request,
+		Results:    append([]registration.TaskResult{}, run.Results...),
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Debug("Error encoding event", err)
+		return
+	}
+	w.Write([]byte("event: " + event + "\ndata: " + string(payload) + "\n\n"))
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Debug("Error encoding response", err)
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]string{"error": message})
+}
+
+func generateToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Debug("Error generating token", err)
+		return generateRunID()
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, func()) {
+	dir, err := ioutil.TempDir("", "nrdiag-serve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diag := newDiagServer("secret", tasks.Options{Options: map[string]string{"YesToAll": "true"}}, dir)
+	server := httptest.NewServer(diag.router())
+	return server, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func serveRequest(t *testing.T, method string, url string, body string, token string) *http.Response {
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func Test_serveRequiresToken(t *testing.T) {
+	server, cleanup := newTestServer(t)
+	defer cleanup()
+
+	for _, token := range []string{"", "wrong"} {
+		res := serveRequest(t, "GET", server.URL+"/tasks", "", token)
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for token %q, got %d", token, res.StatusCode)
+		}
+	}
+	// the token must be sent as a bearer token
+	req, _ := http.NewRequest("GET", server.URL+"/tasks", nil)
+	req.Header.Set("Authorization", "secret")
+	if res, err := http.DefaultClient.Do(req); err != nil || res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a token without the Bearer prefix, got %v %v", res, err)
+	}
+
+	res := serveRequest(t, "GET", server.URL+"/suites", "", "secret")
+	var suiteList []map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&suiteList)
+	if res.StatusCode != http.StatusOK || len(suiteList) == 0 {
+		t.Errorf("Expected the list of suites, got %d: %v", res.StatusCode, suiteList)
+	}
+}
+
+func Test_serveRun(t *testing.T) {
+	server, cleanup := newTestServer(t)
+	defer cleanup()
+	outputPath := config.Flags.OutputPath
+
+	res := serveRequest(t, "POST", server.URL+"/runs", `{"suites": ["notASuite"]}`, "secret")
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown suite, got %d", res.StatusCode)
+	}
+
+	res = serveRequest(t, "POST", server.URL+"/runs", `{"tasks": ["Base/Env/CollectEnvVars"], "overrides": ["Base/Env/CollectEnvVars.Status=warning"]}`, "secret")
+	if res.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected 202 starting a run, got %d", res.StatusCode)
+	}
+	var started serveRun
+	json.NewDecoder(res.Body).Decode(&started)
+
+	// the event stream replays every result and ends with a done event once the run finishes
+	res = serveRequest(t, "GET", server.URL+"/runs/"+started.ID+"/events", "", "secret")
+	if res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatal("Expected an event stream, got", res.Header.Get("Content-Type"))
+	}
+	var events []string
+	var resultData string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		}
+		if strings.HasPrefix(line, "data: ") && len(events) == 1 {
+			resultData = line
+		}
+	}
+	if strings.Join(events, ",") != "result,done" {
+		t.Fatal("Expected a result and a done event, got", events)
+	}
+	if !strings.Contains(resultData, `"Name":"CollectEnvVars"`) || !strings.Contains(resultData, `"Status":"Warning"`) {
+		t.Error("Unexpected result event:", resultData)
+	}
+
+	res = serveRequest(t, "GET", server.URL+"/runs/"+started.ID, "", "secret")
+	var finished serveRun
+	json.NewDecoder(res.Body).Decode(&finished)
+	if finished.Status != runStatusCompleted || len(finished.Results) != 1 {
+		t.Errorf("Expected a completed run with one result, got %s with %d results", finished.Status, len(finished.Results))
+	}
+	if config.Flags.OutputPath != outputPath {
+		t.Errorf("Expected the run to leave -output-path alone, got %s", config.Flags.OutputPath)
+	}
+
+	res = serveRequest(t, "GET", server.URL+"/runs/"+started.ID+"/output.zip", "", "secret")
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "application/zip" {
+		t.Errorf("Expected to download the zip file, got %d %s", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+	res = serveRequest(t, "GET", server.URL+"/runs/"+started.ID+"/output.json", "", "secret")
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), `"Name": "CollectEnvVars"`) {
+		t.Errorf("Expected to download the output json, got %d", res.StatusCode)
+	}
+
+	// runs can be started again once the previous one completed
+	res = serveRequest(t, "POST", server.URL+"/runs", `{"tasks": ["Base/Env/CollectEnvVars"]}`, "secret")
+	if res.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected 202 starting a second run, got %d", res.StatusCode)
+	}
+	var second serveRun
+	json.NewDecoder(res.Body).Decode(&second)
+	for i := 0; i < 100 && second.Status != runStatusCompleted; i++ {
+		time.Sleep(50 * time.Millisecond)
+		res = serveRequest(t, "GET", server.URL+"/runs/"+second.ID, "", "secret")
+		json.NewDecoder(res.Body).Decode(&second)
+	}
+	if second.Status != runStatusCompleted || len(second.Results) != 1 {
+		t.Errorf("Expected the second run to complete with one result, got %s with %d results", second.Status, len(second.Results))
+	}
+
+	res = serveRequest(t, "GET", server.URL+"/runs/unknown", "", "secret")
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown run, got %d", res.StatusCode)
+	}
+}
+
+func Test_checkServeAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		useTLS  bool
+		valid   bool
+	}{
+		{"127.0.0.1:8765", false, true},
+		{"localhost:8765", false, true},
+		{"[::1]:8765", false, true},
+		{":8765", false, false},
+		{"0.0.0.0:8765", false, false},
+		{"myhost:8765", false, false},
+		{":8765", true, true},
+		{"myhost:8765", true, true},
+		{"8765", true, false},
+	}
+	for _, tt := range tests {
+		if err := checkServeAddress(tt.address, tt.useTLS); (err == nil) != tt.valid {
+			t.Errorf("checkServeAddress(%q, %v) = %v, expected valid: %v", tt.address, tt.useTLS, err, tt.valid)
+		}
+	}
+}
+
+func Test_serveTLSConfig(t *testing.T) {
+	if tlsConfig, err := serveTLSConfig("", ""); tlsConfig != nil || err != nil {
+		t.Errorf("Expected no TLS without a certificate, got %v, %v", tlsConfig, err)
+	}
+	if _, err := serveTLSConfig("cert.pem", ""); err == nil {
+		t.Error("Expected an error for a certificate without a key")
+	}
+	if _, err := serveTLSConfig("missing-cert.pem", "missing-key.pem"); err == nil {
+		t.Error("Expected an error for missing certificate files")
+	}
+}
+
+func Test_processServeExitCodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nrdiag-serve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	previousFlags := config.Flags
+	defer func() { config.Flags = previousFlags }()
+	config.Flags.OutputPath = dir
+	config.Flags.ServeToken = "secret"
+
+	if code := processServe("8765", tasks.Options{}); code != exitCodeBadInput {
+		t.Errorf("Expected exit code %d for an invalid address, got %d", exitCodeBadInput, code)
+	}
+	if code := processServe(":8765", tasks.Options{}); code != exitCodeBadInput {
+		t.Errorf("Expected exit code %d for an address reachable from other hosts without TLS, got %d", exitCodeBadInput, code)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	if code := processServe(listener.Addr().String(), tasks.Options{}); code != exitCodeServeError {
+		t.Errorf("Expected exit code %d for an address in use, got %d", exitCodeServeError, code)
+	}
+}
+
+func Test_writeServeToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nrdiag-serve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "nrdiag-serve", serveTokenFile)
+	if err := writeServeToken(tokenFile, "generated"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(tokenFile)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the token file to be readable by its owner only, got %v %v", info, err)
+	}
+}
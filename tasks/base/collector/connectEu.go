@@ -28,6 +28,11 @@ func (p BaseCollectorConnectEU) Explain() string {
 	return "Check network connection to New Relic EU region collector endpoint"
 }
 
+// RequiresNetwork - This task connects to the EU collector, so it is skipped with -offline
+func (p BaseCollectorConnectEU) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - This task depends on Base/Config/ProxyDetect
 func (p BaseCollectorConnectEU) Dependencies() []string {
 	return []string{
@@ -28,6 +28,11 @@ func (p BaseCollectorConnectUS) Explain() string {
 	return "Check network connection to New Relic US region collector endpoint"
 }
 
+// RequiresNetwork - This task connects to the US collector, so it is skipped with -offline
+func (p BaseCollectorConnectUS) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - This task depends on Base/Config/ProxyDetect
 func (p BaseCollectorConnectUS) Dependencies() []string {
 	return []string{
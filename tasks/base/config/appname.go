@@ -65,6 +65,7 @@ func (t BaseConfigAppName) Explain() string {
 func (t BaseConfigAppName) Dependencies() []string {
 	return []string{
 		"Base/Config/Validate",
+		"Python/Config/Environment",
 		"Base/Env/CollectEnvVars",
 		"Base/Env/CollectSysProps",
 	}
@@ -111,7 +112,7 @@ func (t BaseConfigAppName) Execute(options tasks.Options, upstream map[string]ta
 		}
 	}
 
-	appNameInfosFromConfig := getAppNamesFromConfig(configElements)
+	appNameInfosFromConfig := getAppNamesFromConfig(withPythonEnvironments(configElements, upstream))
 
 	if len(appNameInfosFromConfig) == 0 {
 		return tasks.Result{
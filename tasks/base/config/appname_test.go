@@ -31,6 +31,7 @@ var _ = Describe("Base/Config/AppName", func() {
 		It("Should return a slice with dependencies", func() {
 			expectedDependencies := []string{
 				"Base/Config/Validate",
+				"Python/Config/Environment",
 				"Base/Env/CollectEnvVars",
 				"Base/Env/CollectSysProps",
 			}
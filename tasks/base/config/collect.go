@@ -2,7 +2,6 @@ package config
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -62,6 +61,17 @@ var secureFilePatterns = []string{
 	"(?i)appSettings[.]json$",
 }
 
+// These are files to skip for the secure files prompt
+var skippedSecureConfigs = map[string]struct{}{
+	"NewRelic.ServerMonitor.Config.exe.config": struct{}{},
+	"NewRelic.ServerMonitor.exe.config":        struct{}{},
+	"NewRelicStatusMonitor.exe.config":         struct{}{},
+}
+
+// SecureFilesOption is the task option holding the newline separated list of secure files the user already approved.
+// When it is set Base/Config/Collect does not prompt for secure files and skips the ones not listed.
+const SecureFilesOption = "secureFiles"
+
 var warningSummaryFmt = "The " + tasks.ThisProgramFullName + " cannot collect New Relic config files from the provided path (%s):\n%s\nIf you are working with a support ticket, manually provide your New Relic config file for further troubleshooting\n"
 
 // BaseConfigCollect - Primary task to search for and find config file. Will optionally take command line input as source
@@ -128,40 +138,17 @@ func (p BaseConfigCollect) Execute(options tasks.Options, upstream map[string]ta
 	}
 
 	// Search for config file in standard/default expected locations
-
-	var paths []string
-
-	localPath, err := os.Getwd()
-
-	if err != nil {
-		log.Debug("Error reading local working directory")
-	}
-
-	paths = append(paths, localPath)
-
-	if runtime.GOOS == "windows" {
-		sysProgramFiles := envVars["ProgramFiles"]
-		sysProgramData := envVars["ProgramData"]
-		paths = append(paths, sysProgramFiles+`\New Relic`)
-		paths = append(paths, sysProgramData+`\New Relic\`)
-	} else {
-		paths = append(paths, "/etc/")
-		paths = append(paths, "/opt/newrelic/synthetics/.newrelic/synthetics/minion/")
-		paths = append(paths, "/usr/local/newrelic-netcore20-agent/")
-	}
+	paths := getConfigSearchPaths(envVars)
 
 	//Find insecure paths
 	foundConfigs := tasks.FindFiles(patterns, paths)
 
-	// These are files to skip for the secure files prompt
-	var skippedSecureConfigs = make(map[string]struct{})
-	skippedSecureConfigs["NewRelic.ServerMonitor.Config.exe.config"] = struct{}{}
-	skippedSecureConfigs["NewRelic.ServerMonitor.exe.config"] = struct{}{}
-	skippedSecureConfigs["NewRelicStatusMonitor.exe.config"] = struct{}{}
-
 	//Find insecure paths
 	foundSecureConfigs := tasks.FindFiles(secureFilePatterns, paths)
 
+	// When the secure files were already reviewed (e.g. in interactive mode) only the approved ones are included, without prompting again
+	approvedSecureFiles, secureFilesReviewed := options.Options[SecureFilesOption]
+
 	var invalidConfigFiles, cannotCollectConfigFiles []string//will represent the secure files that the user reject nrdiag to collect at the prompt
 	var warningSummaryOnInvalidFiles string
 
@@ -184,7 +171,13 @@ func (p BaseConfigCollect) Execute(options tasks.Options, upstream map[string]ta
 		if _, ok := skippedSecureConfigs[filename]; !ok {
 			question := fmt.Sprintf("We've found a file that may contain secure information: %s\n", secureConfig) +
 				"Include this file in nrdiag-output.zip?"
-			if tasks.PromptUser(question, options) {
+			if secureFilesReviewed {
+				if tasks.PosString(strings.Split(approvedSecureFiles, "\n"), secureConfig) != -1 {
+					foundConfigs = append(foundConfigs, secureConfig)
+				} else {
+					cannotCollectConfigFiles = append(cannotCollectConfigFiles, secureConfig)
+				}
+			} else if tasks.PromptUser(question, options) {
 				if !config.Flags.Quiet {
 					log.Info("Adding file to Diagnostics CLI zip file: ", secureConfig)
 				}
@@ -275,6 +268,37 @@ func (p BaseConfigCollect) Execute(options tasks.Options, upstream map[string]ta
 	}
 }
 
+// getConfigSearchPaths returns the default locations searched for config files
+func getConfigSearchPaths(envVars map[string]string) []string {
+	var paths []string
+
+	// the current directory, or the working directories of the processes selected with -pid
+	paths = append(paths, tasks.GetDiscoveryDirectories()...)
+
+	if runtime.GOOS == "windows" {
+		sysProgramFiles := envVars["ProgramFiles"]
+		sysProgramData := envVars["ProgramData"]
+		paths = append(paths, sysProgramFiles+`\New Relic`)
+		paths = append(paths, sysProgramData+`\New Relic\`)
+	} else {
+		paths = append(paths, "/etc/")
+		paths = append(paths, "/opt/newrelic/synthetics/.newrelic/synthetics/minion/")
+		paths = append(paths, "/usr/local/newrelic-netcore20-agent/")
+	}
+	return paths
+}
+
+// FindSecureFiles returns the files Base/Config/Collect would prompt about before including them in the zip file
+func FindSecureFiles(envVars map[string]string) []string {
+	var secureFiles []string
+	for _, secureConfig := range tasks.FindFiles(secureFilePatterns, getConfigSearchPaths(envVars)) {
+		if _, ok := skippedSecureConfigs[filepath.Base(secureConfig)]; !ok {
+			secureFiles = append(secureFiles, secureConfig)
+		}
+	}
+	return secureFiles
+}
+
 func isConfigFileinPathToIgnore(dir string) bool {
 	for _, path := range pathsToIgnore {
 		if strings.Contains(dir, path) {
@@ -286,7 +310,7 @@ func isConfigFileinPathToIgnore(dir string) bool {
 
 func appendToInvalidOrFoundConfigs(configPath string, warningSummaryOnInvalidFiles *string, invalidConfigFiles, foundConfigs []string) ([]string, []string) {
 
-	pathInfo, err := os.Stat(configPath)
+	pathInfo, err := tasks.FS.Stat(tasks.RootPath(configPath))
 	if err != nil {
 		invalidConfigFiles = append(invalidConfigFiles, configPath)
 		*warningSummaryOnInvalidFiles += fmt.Sprintf(warningSummaryFmt, configPath, err.Error())
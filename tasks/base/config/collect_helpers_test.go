@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
 	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
 
 func Test_getNETAgentConfigPathFromFile(t *testing.T) {
@@ -69,3 +74,23 @@ func Test_isFileWithNETAgentConfigPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_FindSecureFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("searches the linux config locations")
+	}
+	defer tasks.UseFileSystem(tasks.NewMemFS(map[string]string{
+		"/etc/newrelic/app.config":                             "<configuration />",
+		"/etc/newrelic/newrelic.yml":                           "common:\n  app_name: test",
+		"/etc/newrelic/NewRelicStatusMonitor.exe.config":       "<configuration />",
+		"/usr/local/newrelic-netcore20-agent/appsettings.json": "{}",
+		"/var/www/app.config":                                  "<configuration />",
+	}))()
+
+	got := FindSecureFiles(map[string]string{})
+	sort.Strings(got)
+	want := []string{"/etc/newrelic/app.config", "/usr/local/newrelic-netcore20-agent/appsettings.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindSecureFiles() = %v, want %v", got, want)
+	}
+}
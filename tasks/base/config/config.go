@@ -20,6 +20,7 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 	}, true)
 	registrationFunc(BaseConfigAppName{}, true)
 	registrationFunc(BaseConfigRegionDetect{}, true)
+	registrationFunc(BaseConfigEffective{}, true)
 	registrationFunc(BaseConfigValidateHSM{
 		hsmService: haberdasherHSMService,
 	}, true)
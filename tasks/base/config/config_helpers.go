@@ -2,10 +2,10 @@ package config
 
 import (
 	"errors"
-	"os"
 	"regexp"
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
 
 //app.config/web.config files can contain custom paths to New Relic .NET Agent config files, that we need to collect in this task.
@@ -31,12 +31,12 @@ func isFileWithNETAgentConfigPath(filename string) bool {
 // New Relic .NET Agent config file, if it is set.
 // Example: <add key = "NewRelic.ConfigFile" value="C:\Path-to-alternate-config-dir\newrelic.config" />
 func getNETAgentConfigPathFromFile(filepath string) (string, error) {
-	file, err := os.Open(filepath)
-	defer file.Close()
+	file, err := tasks.FS.Open(tasks.RootPath(filepath))
 	if err != nil {
 		log.Debugf("Unable to open '%s': %s\n", filepath, err.Error())
 		return "", err
 	}
+	defer file.Close()
 
 	parsedFile, err := parseXML(file)
 	if err != nil {
@@ -67,7 +67,7 @@ func getNETAgentConfigPathFromFile(filepath string) (string, error) {
 	}
 
 	// Check if file exists
-	pathInfo, err := os.Stat(agentConfigPath)
+	pathInfo, err := tasks.FS.Stat(tasks.RootPath(agentConfigPath))
 	if err != nil {
 		return "", err
 	}
@@ -80,3 +80,32 @@ func getNETAgentConfigPathFromFile(filepath string) (string, error) {
 
 	return agentConfigPath, nil
 }
+
+// withPythonEnvironments - the config files with each newrelic.ini of the Python agent replaced by the settings the
+// agent reads from it, as resolved by Python/Config/Environment. Base/Config/Validate flattens every section of an ini
+// file, so the last [newrelic:<environment>] section would win.
+func withPythonEnvironments(configElements []ValidateElement, upstream map[string]tasks.Result) []ValidateElement {
+	resolved, ok := upstream["Python/Config/Environment"].Payload.([]ValidateElement)
+	if !ok || len(resolved) == 0 {
+		return configElements
+	}
+	byFile := make(map[string][]ValidateElement)
+	for _, element := range resolved {
+		file := element.Config.FilePath + element.Config.FileName
+		byFile[file] = append(byFile[file], element)
+	}
+	var elements []ValidateElement
+	replaced := make(map[string]bool)
+	for _, element := range configElements {
+		file := element.Config.FilePath + element.Config.FileName
+		pythonElements, isPython := byFile[file]
+		if !isPython {
+			elements = append(elements, element)
+		} else if !replaced[file] {
+			// Base/Config/Validate can list a file more than once
+			elements = append(elements, pythonElements...)
+			replaced[file] = true
+		}
+	}
+	return elements
+}
@@ -0,0 +1,432 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// SourceKind - where the value of a setting was read from
+type SourceKind string
+
+// the sources a setting can be read from
+const (
+	SourceFile    SourceKind = "file"
+	SourceEnv     SourceKind = "env"
+	SourceSysProp SourceKind = "sysprop"
+	SourceDefault SourceKind = "default"
+)
+
+// SettingSource - the config file and line, environment variable, system property or default a value comes from
+type SettingSource struct {
+	Kind SourceKind
+	// Name is the config file path, environment variable or system property
+	Name string
+	Line int   `json:",omitempty"`
+	PID  int32 `json:",omitempty"`
+}
+
+func (s SettingSource) String() string {
+	switch s.Kind {
+	case SourceFile:
+		if s.Line > 0 {
+			return s.Name + ":" + strconv.Itoa(s.Line)
+		}
+		return s.Name
+	case SourceEnv:
+		return "environment variable " + s.Name
+	case SourceSysProp:
+		return fmt.Sprintf("system property %s (pid %d)", s.Name, s.PID)
+	}
+	return "default"
+}
+
+// SettingValue - a value of a setting and where it was read from
+type SettingValue struct {
+	Value  string
+	Source SettingSource
+}
+
+// EffectiveSetting - the value an agent uses for a setting, and the values found for it that it takes precedence over
+type EffectiveSetting struct {
+	Name     string
+	Value    string
+	Source   SettingSource
+	Shadowed []SettingValue `json:",omitempty"`
+}
+
+// EffectiveConfig - the resolved settings of the agent reading a config file
+type EffectiveConfig struct {
+	Agent      string
+	ConfigFile string
+	// Environment is the section of newrelic.yml the settings were read from, if the file has any
+	Environment string `json:",omitempty"`
+	Settings    []EffectiveSetting
+}
+
+// BaseConfigEffective - Struct for task definition
+type BaseConfigEffective struct {
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (t BaseConfigEffective) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("Base/Config/Effective")
+}
+
+// Explain - Returns the help text for each individual task
+func (t BaseConfigEffective) Explain() string {
+	return "Resolve the agent settings in effect and where each value comes from"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (t BaseConfigEffective) Dependencies() []string {
+	return []string{
+		"Base/Config/Validate",
+		"Python/Config/Environment",
+		"Base/Env/CollectEnvVars",
+		"Base/Env/CollectSysProps",
+	}
+}
+
+// Execute - The core work within each task
+func (t BaseConfigEffective) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	configElements, ok := upstream["Base/Config/Validate"].Payload.([]ValidateElement)
+	if !ok {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No config files were parsed, no settings to resolve.",
+		}
+	}
+	envVars, _ := upstream["Base/Env/CollectEnvVars"].Payload.(map[string]string)
+	var sysProps []tasks.ProcIDSysProps
+	if upstream["Base/Env/CollectSysProps"].Status == tasks.Info {
+		sysProps, _ = upstream["Base/Env/CollectSysProps"].Payload.([]tasks.ProcIDSysProps)
+	}
+
+	specs := registeredSettingsSpecs(options)
+	var effectiveConfigs []EffectiveConfig
+	seen := make(map[string]bool)
+	for _, element := range withPythonEnvironments(configElements, upstream) {
+		file := element.Config.FilePath + element.Config.FileName
+		// a newrelic.ini is listed once for each environment the Python processes run in
+		if element.Status != tasks.Success || seen[file+element.ParsedResult.String()] {
+			continue
+		}
+		seen[file+element.ParsedResult.String()] = true
+		settings := make(map[string]interface{})
+		flattenSettings(element.ParsedResult.Children, "", settings)
+		spec, ok := detectConfigAgent(element.Config, settings, sysProps, specs)
+		if !ok {
+			continue
+		}
+		effectiveConfigs = append(effectiveConfigs, resolveSettings(spec, file, settings, envVars, sysProps, yamlEnvironment(spec, options, envVars, sysProps)))
+	}
+
+	if len(effectiveConfigs) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No New Relic agent config files were found, no settings to resolve.",
+		}
+	}
+	return tasks.Result{
+		Status:  tasks.Info,
+		Summary: summarizeEffectiveConfigs(effectiveConfigs),
+		Payload: effectiveConfigs,
+	}
+}
+
+// detectConfigAgent - the spec of the agent reading a config file, among the registered specs listing its name. A
+// spec with a prefix, such as PHP's newrelic., only matches files holding settings with that prefix. When several
+// agents read files with this name, as the Java and Ruby agents do with newrelic.yml, the file belongs to the agent
+// its location points to, and otherwise to the agent whose spec knows the most of its settings.
+func detectConfigAgent(config ConfigElement, settings map[string]interface{}, sysProps []tasks.ProcIDSysProps, specs []SettingsSpec) (SettingsSpec, bool) {
+	var candidates []SettingsSpec
+	for _, spec := range specs {
+		if spec.listsFile(config.FileName) && (spec.Prefix == "" || hasPrefixedSetting(settings, spec.Prefix)) {
+			candidates = append(candidates, spec)
+		}
+	}
+	if len(candidates) == 0 {
+		return SettingsSpec{}, false
+	}
+	if agent := configLocationAgent(config, sysProps); agent != "" {
+		for _, spec := range candidates {
+			if spec.Agent == agent {
+				return spec, true
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].knownSettings(settings) > candidates[j].knownSettings(settings)
+	})
+	return candidates[0], true
+}
+
+// configLocationAgent - the agent a newrelic.yml belongs to from where it is: Java's when a JVM reads it through
+// -Dnewrelic.config.file or the agent jar is beside it, Ruby's when a Gemfile is beside it or one directory up, as
+// with the config/ directory of a Rails app. Returns "" when its location tells neither.
+func configLocationAgent(config ConfigElement, sysProps []tasks.ProcIDSysProps) string {
+	if !strings.EqualFold(config.FileName, "newrelic.yml") {
+		return ""
+	}
+	file := filepath.Clean(filepath.Join(config.FilePath, config.FileName))
+	for _, procSysProps := range sysProps {
+		if configFile := procSysProps.SysPropsKeyToVal["-Dnewrelic.config.file"]; configFile != "" && filepath.Clean(configFile) == file {
+			return "Java"
+		}
+	}
+	if tasks.FileExists(filepath.Join(config.FilePath, "newrelic.jar")) {
+		return "Java"
+	}
+	for _, dir := range []string{filepath.Clean(config.FilePath), filepath.Dir(filepath.Clean(config.FilePath))} {
+		if tasks.FileExists(filepath.Join(dir, "Gemfile")) {
+			return "Ruby"
+		}
+	}
+	return ""
+}
+
+func hasPrefixedSetting(settings map[string]interface{}, prefix string) bool {
+	for key := range settings {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RubyEnvironmentVars - the environment variables the Ruby agent reads its newrelic.yml section from, in order
+var RubyEnvironmentVars = []string{"NEW_RELIC_ENV", "RUBY_ENV", "RAILS_ENV", "APP_ENV", "RACK_ENV"}
+
+// RubyDefaultEnvironment - the section the Ruby agent reads when none of RubyEnvironmentVars is set
+const RubyDefaultEnvironment = "development"
+
+// RubyEnvironment - the newrelic.yml section the Ruby agent reads on top of common with these environment variables
+func RubyEnvironment(envVars map[string]string) string {
+	for _, envVar := range RubyEnvironmentVars {
+		if envVars[envVar] != "" {
+			return envVars[envVar]
+		}
+	}
+	return RubyDefaultEnvironment
+}
+
+// yamlEnvironment - the newrelic.yml section the agent reads on top of common, unless set with the environment option.
+// The Java agent reads the newrelic.environment system property and defaults to production.
+func yamlEnvironment(spec SettingsSpec, options tasks.Options, envVars map[string]string, sysProps []tasks.ProcIDSysProps) string {
+	if options.Options["environment"] != "" {
+		return options.Options["environment"]
+	}
+	if spec.Agent != "Java" {
+		return RubyEnvironment(envVars)
+	}
+	for _, procSysProps := range sysProps {
+		if environment := procSysProps.SysPropsKeyToVal["-Dnewrelic.environment"]; environment != "" {
+			return environment
+		}
+	}
+	return "production"
+}
+
+// resolveSettings - the value of each setting of the spec the agent runs with, in the order of the setting names.
+// Settings found nowhere and without a default are left out.
+func resolveSettings(spec SettingsSpec, file string, settings map[string]interface{}, envVars map[string]string, sysProps []tasks.ProcIDSysProps, environment string) EffectiveConfig {
+	effective := EffectiveConfig{Agent: spec.Agent, ConfigFile: file}
+	sectioned := len(spec.Sections) > 0 && hasSection(settings, spec.Sections[0])
+	if sectioned {
+		effective.Environment = environment
+	}
+	content, err := tasks.ReadFileBytes(file)
+	if err != nil {
+		content = nil
+	}
+	lines := strings.Split(string(content), "\n")
+
+	var names []string
+	for name := range spec.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		definition := spec.Settings[name]
+		key := spec.Prefix + name
+		var fromFile []SettingValue
+		if sectioned {
+			fromFile = sectionValues(key, spec.Sections[0], file, lines, settings, environment)
+		} else if value, ok := settingString(settings[key]); ok {
+			fromFile = []SettingValue{{Value: value, Source: fileSource(file, lines, key)}}
+		}
+
+		settingEnvVars := definition.Env
+		if envVar := spec.EnvVar(name); envVar != "" {
+			settingEnvVars = append([]string{envVar}, settingEnvVars...)
+		}
+		var fromEnv []SettingValue
+		for _, envVar := range settingEnvVars {
+			if value, ok := envVars[envVar]; ok {
+				fromEnv = append(fromEnv, SettingValue{Value: value, Source: SettingSource{Kind: SourceEnv, Name: envVar}})
+			}
+		}
+		if spec.SysPropPrefix != "" {
+			for _, procSysProps := range sysProps {
+				sysProp := spec.SysPropPrefix + name
+				if value, ok := procSysProps.SysPropsKeyToVal[sysProp]; ok {
+					fromEnv = append(fromEnv, SettingValue{Value: value, Source: SettingSource{Kind: SourceSysProp, Name: sysProp, PID: procSysProps.ProcID}})
+				}
+			}
+		}
+
+		var values []SettingValue
+		if spec.FileFirst {
+			values = append(append(values, fromFile...), fromEnv...)
+		} else {
+			values = append(append(values, fromEnv...), fromFile...)
+		}
+		if len(values) == 0 {
+			if definition.Default == "" {
+				continue
+			}
+			values = []SettingValue{{Value: definition.Default, Source: SettingSource{Kind: SourceDefault}}}
+		}
+		if definition.Secret {
+			for i := range values {
+				values[i].Value = maskSettingValue(values[i].Value)
+			}
+		}
+		resolved := EffectiveSetting{Name: key, Value: values[0].Value, Source: values[0].Source}
+		if len(values) > 1 {
+			resolved.Shadowed = values[1:]
+		}
+		effective.Settings = append(effective.Settings, resolved)
+	}
+	return effective
+}
+
+// sectionValues - the values of a newrelic.yml setting, the environment section first. A section that inherits the
+// value of the defaults section, common, through a merge key reports common's line.
+func sectionValues(name string, defaults string, file string, lines []string, settings map[string]interface{}, environment string) []SettingValue {
+	var values []SettingValue
+	common, inCommon := settingString(settings[defaults+"."+name])
+	if value, ok := settingString(settings[environment+"."+name]); ok && (!inCommon || value != common) {
+		values = append(values, SettingValue{Value: value, Source: SettingSource{Kind: SourceFile, Name: file, Line: settingLine(lines, strings.Split(environment+"."+name, "."))}})
+	}
+	if inCommon {
+		values = append(values, SettingValue{Value: common, Source: SettingSource{Kind: SourceFile, Name: file, Line: settingLine(lines, strings.Split(defaults+"."+name, "."))}})
+	}
+	return values
+}
+
+func fileSource(file string, lines []string, name string) SettingSource {
+	segments := strings.Split(name, ".")
+	if strings.EqualFold(filepath.Ext(file), ".ini") {
+		// ini keys hold their dots
+		segments = []string{name}
+	}
+	return SettingSource{Kind: SourceFile, Name: file, Line: settingLine(lines, segments)}
+}
+
+func hasSection(settings map[string]interface{}, section string) bool {
+	for key := range settings {
+		if strings.HasPrefix(key, section+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// settingLine - the 1-based line setting a key, found by looking for each of its segments nested below the previous
+// one. Returns 0 when the key can't be found, e.g. when it was set through a YAML merge key.
+func settingLine(lines []string, segments []string) int {
+	parentIndent := -1
+	segment := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || isCommentLine(trimmed) {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if segment > 0 && indent <= parentIndent {
+			// left the block of the previous segment
+			return 0
+		}
+		if !isKeyLine(trimmed, segments[segment]) {
+			continue
+		}
+		segment++
+		if segment == len(segments) {
+			return i + 1
+		}
+		parentIndent = indent
+	}
+	return 0
+}
+
+func isCommentLine(trimmed string) bool {
+	for _, prefix := range []string{"#", ";", "//", "/*", "*"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isKeyLine(trimmed string, key string) bool {
+	for _, quote := range []string{"", "'", "\""} {
+		if !strings.HasPrefix(trimmed, quote+key+quote) {
+			continue
+		}
+		rest := strings.TrimSpace(strings.TrimPrefix(trimmed, quote+key+quote))
+		if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "=") {
+			return true
+		}
+	}
+	return false
+}
+
+// settingString - the value of a flattened setting as the agent reads it, lists joined with commas
+func settingString(value interface{}) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	if list, ok := value.([]interface{}); ok {
+		var values []string
+		for _, item := range list {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return strings.Join(values, ", "), true
+	}
+	return scalarString(value)
+}
+
+func maskSettingValue(value string) string {
+	if value == "" {
+		return value
+	}
+	return "********"
+}
+
+func summarizeEffectiveConfigs(configs []EffectiveConfig) string {
+	var lines []string
+	for _, config := range configs {
+		header := fmt.Sprintf("%s agent settings from %s", config.Agent, config.ConfigFile)
+		if config.Environment != "" {
+			header += " (" + config.Environment + " environment)"
+		}
+		lines = append(lines, header+":")
+		settings := make([]EffectiveSetting, len(config.Settings))
+		copy(settings, config.Settings)
+		sort.SliceStable(settings, func(i, j int) bool { return settings[i].Name < settings[j].Name })
+		for _, setting := range settings {
+			lines = append(lines, fmt.Sprintf("\t%s = '%s' from %s", setting.Name, setting.Value, setting.Source))
+			for _, shadowed := range setting.Shadowed {
+				lines = append(lines, fmt.Sprintf("\t\toverrides '%s' from %s", shadowed.Value, shadowed.Source))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
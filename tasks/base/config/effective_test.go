@@ -0,0 +1,257 @@
+package config
+
+import (
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// effectiveSpecs stand in for the agent specs, which register themselves from the agent packages
+var effectiveSpecs = []string{`
+agent: Java
+files: [newrelic.yml]
+sections: [common, development, test, production, staging]
+sysPropPrefix: -Dnewrelic.config.
+settings:
+  license_key: {type: String, env: [NEW_RELIC_LICENSE_KEY]}
+  app_name: {type: String, env: [NEW_RELIC_APP_NAME]}
+  agent_enabled: {type: Boolean, default: 'true'}
+  enable_auto_app_naming: {type: Boolean}
+  enable_auto_transaction_naming: {type: Boolean}
+  proxy_scheme: {type: Enum, values: [http, https], default: http}
+`, `
+agent: Ruby
+files: [newrelic.yml]
+sections: [common, development, test, staging, production]
+settings:
+  license_key: {type: String, env: [NEW_RELIC_LICENSE_KEY]}
+  app_name: {type: String, env: [NEW_RELIC_APP_NAME]}
+  agent_enabled: {type: Boolean, default: 'true'}
+  monitor_mode: {type: Boolean, env: [NEW_RELIC_MONITOR_MODE], default: 'true'}
+`, `
+agent: Node
+files: [newrelic.js]
+settings:
+  logging.filepath: {type: String, env: [NEW_RELIC_LOG], default: newrelic_agent.log}
+`, `
+agent: Python
+files: [newrelic.ini]
+fileFirst: true
+settings:
+  license_key: {type: String, env: [NEW_RELIC_LICENSE_KEY]}
+`, `
+agent: PHP
+files: [newrelic.ini]
+prefix: newrelic.
+settings:
+  license: {type: String}
+`, `
+agent: Infrastructure
+files: [newrelic-infra.yml]
+envPrefix: NRIA_
+settings:
+  log.level: {type: String}
+`}
+
+var _ = Describe("Base/Config/Effective", func() {
+	var (
+		p       BaseConfigEffective
+		restore func()
+	)
+
+	BeforeEach(func() {
+		restore = useSettingsSpecs(effectiveSpecs...)
+	})
+	AfterEach(func() {
+		restore()
+	})
+
+	validated := func(fileName string, filePath string) tasks.Result {
+		return BaseConfigValidate{}.Execute(tasks.Options{}, map[string]tasks.Result{
+			"Base/Config/Collect": {
+				Status:  tasks.Success,
+				Payload: []ConfigElement{{FileName: fileName, FilePath: filePath}},
+			},
+		})
+	}
+	setting := func(result tasks.Result, name string) EffectiveSetting {
+		configs, ok := result.Payload.([]EffectiveConfig)
+		Expect(ok).To(BeTrue())
+		for _, setting := range configs[0].Settings {
+			if setting.Name == name {
+				return setting
+			}
+		}
+		Fail("no effective setting " + name)
+		return EffectiveSetting{}
+	}
+
+	Describe("Identifier()", func() {
+		It("Should return the identifier", func() {
+			Expect(p.Identifier()).To(Equal(tasks.IdentifierFromString("Base/Config/Effective")))
+		})
+	})
+
+	Describe("Execute()", func() {
+		It("should return None when no config file was parsed", func() {
+			result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+				"Base/Config/Validate": {Status: tasks.None},
+			})
+			Expect(result.Status).To(Equal(tasks.None))
+		})
+
+		Context("for the Java agent", func() {
+			var result tasks.Result
+			BeforeEach(func() {
+				result = p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate":    validated("newrelic.yml", "../../fixtures/java/newrelic/"),
+					"Base/Env/CollectEnvVars": {Status: tasks.Info, Payload: map[string]string{"NEW_RELIC_APP_NAME": "env-app"}},
+					"Base/Env/CollectSysProps": {Status: tasks.Info, Payload: []tasks.ProcIDSysProps{{
+						ProcID:           42,
+						SysPropsKeyToVal: map[string]string{"-Dnewrelic.config.app_name": "sysprop-app", "-Dnewrelic.config.license_key": "sysprop-key"},
+					}}},
+				})
+			})
+			It("should return Info with the file values and their lines", func() {
+				Expect(result.Status).To(Equal(tasks.Info))
+				Expect(result.Payload.([]EffectiveConfig)[0].Agent).To(Equal("Java"))
+				Expect(setting(result, "agent_enabled")).To(Equal(EffectiveSetting{
+					Name:   "agent_enabled",
+					Value:  "true",
+					Source: SettingSource{Kind: SourceFile, Name: "../../fixtures/java/newrelic/newrelic.yml", Line: 21},
+				}))
+			})
+			It("should prefer environment variables over system properties over the file", func() {
+				Expect(setting(result, "app_name")).To(Equal(EffectiveSetting{
+					Name:   "app_name",
+					Value:  "env-app",
+					Source: SettingSource{Kind: SourceEnv, Name: "NEW_RELIC_APP_NAME"},
+					Shadowed: []SettingValue{
+						{Value: "sysprop-app", Source: SettingSource{Kind: SourceSysProp, Name: "-Dnewrelic.config.app_name", PID: 42}},
+						{Value: "My Java App", Source: SettingSource{Kind: SourceFile, Name: "../../fixtures/java/newrelic/newrelic.yml", Line: 32}},
+					},
+				}))
+				Expect(setting(result, "license_key").Source.String()).To(Equal("system property -Dnewrelic.config.license_key (pid 42)"))
+			})
+			It("should fall back to the agent defaults", func() {
+				Expect(setting(result, "proxy_scheme").Source.Kind).To(Equal(SourceDefault))
+			})
+		})
+
+		Context("for the Ruby agent", func() {
+			It("should read the environment section on top of common", func() {
+				result := p.Execute(tasks.Options{Options: map[string]string{"environment": "development"}}, map[string]tasks.Result{
+					"Base/Config/Validate": validated("newrelic.yml", "../../fixtures/ruby/config/"),
+				})
+				configs := result.Payload.([]EffectiveConfig)
+				Expect(configs[0].Agent).To(Equal("Ruby"))
+				Expect(configs[0].Environment).To(Equal("development"))
+				monitorMode := setting(result, "monitor_mode")
+				Expect(monitorMode.Value).To(Equal("false"))
+				Expect(monitorMode.Shadowed).To(HaveLen(1))
+				Expect(monitorMode.Shadowed[0].Value).To(Equal("true"))
+			})
+			It("should read the environment from the variables the Ruby agent reads", func() {
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate":    validated("newrelic.yml", "../../fixtures/ruby/config/"),
+					"Base/Env/CollectEnvVars": {Status: tasks.Info, Payload: map[string]string{"RUBY_ENV": "test", "RACK_ENV": "staging"}},
+				})
+				Expect(result.Payload.([]EffectiveConfig)[0].Environment).To(Equal("test"))
+			})
+			It("should default to the development environment", func() {
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate": validated("newrelic.yml", "../../fixtures/ruby/config/"),
+				})
+				Expect(result.Payload.([]EffectiveConfig)[0].Environment).To(Equal(RubyDefaultEnvironment))
+			})
+		})
+
+		Context("for a newrelic.yml holding only the settings both agents know", func() {
+			var (
+				sysProps []tasks.ProcIDSysProps
+				files    map[string]string
+			)
+			agent := func() string {
+				defer tasks.UseFileSystem(tasks.NewMemFS(files))()
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate":     validated("newrelic.yml", "/app/config/"),
+					"Base/Env/CollectSysProps": {Status: tasks.Info, Payload: sysProps},
+				})
+				return result.Payload.([]EffectiveConfig)[0].Agent
+			}
+			BeforeEach(func() {
+				sysProps = []tasks.ProcIDSysProps{{ProcID: 42, SysPropsKeyToVal: map[string]string{"-Dnewrelic.environment": "production"}}}
+				files = map[string]string{"/app/config/newrelic.yml": "common:\n  app_name: shared\n  license_key: abc\n"}
+			})
+			It("should be Java's when a JVM reads it with -Dnewrelic.config.file", func() {
+				sysProps[0].SysPropsKeyToVal["-Dnewrelic.config.file"] = "/app/config/newrelic.yml"
+				files["/app/Gemfile"] = "gem 'newrelic_rpm'\n"
+				Expect(agent()).To(Equal("Java"))
+			})
+			It("should be Java's when the agent jar is beside it", func() {
+				files["/app/config/newrelic.jar"] = ""
+				Expect(agent()).To(Equal("Java"))
+			})
+			It("should be Ruby's when a Gemfile is one directory up, even with a JVM running", func() {
+				files["/app/Gemfile"] = "gem 'newrelic_rpm'\n"
+				Expect(agent()).To(Equal("Ruby"))
+			})
+			It("should go by the settings only one agent knows otherwise", func() {
+				files["/app/config/newrelic.yml"] += "  monitor_mode: true\n"
+				Expect(agent()).To(Equal("Ruby"))
+				files["/app/config/newrelic.yml"] += "  enable_auto_app_naming: false\n  enable_auto_transaction_naming: true\n"
+				Expect(agent()).To(Equal("Java"))
+			})
+		})
+
+		Context("for the Node agent", func() {
+			It("should find nested settings in newrelic.js", func() {
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate": validated("newrelic.js", "../../fixtures/node/"),
+				})
+				Expect(setting(result, "logging.filepath").Source.String()).To(Equal("../../fixtures/node/newrelic.js:26"))
+			})
+		})
+
+		Context("for the Python agent", func() {
+			It("should prefer the config file over environment variables", func() {
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate":    validated("newrelic.ini", "../../fixtures/python/"),
+					"Base/Env/CollectEnvVars": {Status: tasks.Info, Payload: map[string]string{"NEW_RELIC_LICENSE_KEY": "env-key"}},
+				})
+				licenseKey := setting(result, "license_key")
+				Expect(licenseKey.Value).To(Equal("license-key-val-python"))
+				Expect(licenseKey.Shadowed).To(Equal([]SettingValue{{Value: "env-key", Source: SettingSource{Kind: SourceEnv, Name: "NEW_RELIC_LICENSE_KEY"}}}))
+			})
+		})
+
+		Context("for the PHP agent", func() {
+			It("should tell newrelic.ini apart from the Python agent's", func() {
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate": validated("newrelic.ini", "../../fixtures/php/root/etc/php5/conf.d/"),
+				})
+				Expect(result.Payload.([]EffectiveConfig)[0].Agent).To(Equal("PHP"))
+				Expect(setting(result, "newrelic.license").Source.Kind).To(Equal(SourceFile))
+			})
+		})
+
+		Context("for the Infrastructure agent", func() {
+			It("should read the environment variables named after the settings", func() {
+				restore := tasks.UseFileSystem(tasks.NewMemFS(map[string]string{
+					"/etc/newrelic-infra.yml": "license_key: abc\nlog:\n  level: info\n",
+				}))
+				defer restore()
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Base/Config/Validate":    validated("newrelic-infra.yml", "/etc/"),
+					"Base/Env/CollectEnvVars": {Status: tasks.Info, Payload: map[string]string{"NRIA_LOG_LEVEL": "debug"}},
+				})
+				Expect(setting(result, "log.level")).To(Equal(EffectiveSetting{
+					Name:     "log.level",
+					Value:    "debug",
+					Source:   SettingSource{Kind: SourceEnv, Name: "NRIA_LOG_LEVEL"},
+					Shadowed: []SettingValue{{Value: "info", Source: SettingSource{Kind: SourceFile, Name: "/etc/newrelic-infra.yml", Line: 3}}},
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// settingsSpecs are the names of the settings specs of the agents, registered with RegisterSettingsSpec.
+// Base/Config/Validate suggests corrections against the settings of the specs listing a config file.
+var settingsSpecs []string
+
+// RegisterSettingsSpec - makes an agent's settings spec, named after its file in the specs directory, available to the
+// key suggestions of Base/Config/Validate and to Base/Config/Effective
+func RegisterSettingsSpec(name string) {
+	settingsSpecs = append(settingsSpecs, name)
+}
+
+// registeredSettingsSpecs - the registered specs, loaded from the specDir task option or the builtin ones. A spec
+// given with the specFile task option replaces the specs of its files.
+func registeredSettingsSpecs(options tasks.Options) []SettingsSpec {
+	var specs []SettingsSpec
+	for _, name := range settingsSpecs {
+		spec, err := loadNamedSettingsSpec(options, name)
+		if err != nil {
+			log.Debug("Skipping invalid settings spec:", err)
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	if options.Options["specFile"] == "" {
+		return specs
+	}
+
+	replacement, err := readSettingsSpec(options.Options["specFile"])
+	if err != nil {
+		log.Debug("Unable to load the settings spec file:", err)
+		return specs
+	}
+	kept := []SettingsSpec{replacement}
+	for _, spec := range specs {
+		if !sharesFile(spec, replacement) {
+			kept = append(kept, spec)
+		}
+	}
+	return kept
+}
+
+func sharesFile(spec SettingsSpec, other SettingsSpec) bool {
+	for _, file := range spec.Files {
+		if other.listsFile(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// listsFile - true when the settings of the spec are read from config files with this name
+func (s SettingsSpec) listsFile(fileName string) bool {
+	for _, file := range s.Files {
+		if strings.EqualFold(file, fileName) {
+			return true
+		}
+	}
+	return false
+}
+
+// knownConfigKeys - the settings of the registered specs by the config file names they list
+func knownConfigKeys(options tasks.Options) map[string]tasks.KnownKeys {
+	known := make(map[string]tasks.KnownKeys)
+	for _, spec := range registeredSettingsSpecs(options) {
+		addKnownKeys(known, spec)
+	}
+	return known
+}
+
+// addKnownKeys - adds the sections and settings of a spec to the known keys of each file it lists
+func addKnownKeys(known map[string]tasks.KnownKeys, spec SettingsSpec) {
+	for _, file := range spec.Files {
+		file = strings.ToLower(file)
+		keys := known[file]
+		for _, section := range spec.Sections {
+			if tasks.PosString(keys.Sections, section) == -1 {
+				keys.Sections = append(keys.Sections, section)
+			}
+		}
+		for key := range spec.Settings {
+			keys.Keys = append(keys.Keys, spec.Prefix+key)
+		}
+		known[file] = keys
+	}
+}
+
+// suggestConfigKeys - the key suggestions for a parsed config file, when its name has known keys
+func suggestConfigKeys(element ValidateElement, known map[string]tasks.KnownKeys) []tasks.KeySuggestion {
+	keys, ok := known[strings.ToLower(element.Config.FileName)]
+	if !ok || element.Status != tasks.Success {
+		return nil
+	}
+	return element.ParsedResult.SuggestKeys(keys)
+}
+
+// summarizeKeySuggestions - one "did you mean" line per suggestion, grouped by file
+func summarizeKeySuggestions(elements []ValidateElement) string {
+	var lines []string
+	for _, element := range elements {
+		if len(element.Suggestions) == 0 {
+			continue
+		}
+		lines = append(lines, element.Config.FilePath+element.Config.FileName+":")
+		for _, suggestion := range element.Suggestions {
+			lines = append(lines, fmt.Sprintf("\t'%s' %s, did you mean '%s'?", suggestion.Key, suggestion.Reason, suggestion.Suggestion))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func suggestionsSummary(suggestions string) string {
+	if suggestions == "" {
+		return ""
+	}
+	return "\nSettings the agent won't read:\n" + suggestions
+}
+
+// keySuggestionFixes - renames the misspelled settings of a config file. Only the suggestions that differ in the last
+// part of the key are fixed, moving a setting to another section or parent is left to the user.
+func keySuggestionFixes(element ValidateElement) []tasks.ConfigFix {
+	var fixes []tasks.ConfigFix
+	for _, suggestion := range element.Suggestions {
+		keyParent, keyName := splitLastKeyPart(suggestion.Key)
+		suggestionParent, suggestionName := splitLastKeyPart(suggestion.Suggestion)
+		if suggestion.Reason != "is not a known setting" || keyParent != suggestionParent || keyName == suggestionName {
+			continue
+		}
+		value := ""
+		if found, ok := findSetting(element.ParsedResult.Children, "", suggestion.Key); ok && found.IsLeaf() {
+			value = found.Value()
+		}
+		fixes = append(fixes, tasks.ConfigFix{
+			File:        element.Config.FilePath + element.Config.FileName,
+			Description: fmt.Sprintf("Rename %s to %s", suggestion.Key, suggestion.Suggestion),
+			Key:         suggestion.Key,
+			Value:       value,
+			NewKey:      suggestion.Suggestion,
+		})
+	}
+	return fixes
+}
+
+// findSetting - the setting with the dotted name, e.g. common.log_level. The parts of a name aren't split on its dots,
+// as keys can hold dots themselves, e.g. newrelic.loglevel in newrelic.ini.
+func findSetting(blobs []tasks.ValidateBlob, parent string, name string) (tasks.ValidateBlob, bool) {
+	for _, blob := range blobs {
+		key := blob.Key
+		if parent != "" {
+			key = parent + "." + blob.Key
+		}
+		if key == name {
+			return blob, true
+		}
+		if strings.HasPrefix(name, key+".") {
+			if found, ok := findSetting(blob.Children, key, name); ok {
+				return found, true
+			}
+		}
+	}
+	return tasks.ValidateBlob{}, false
+}
+
+func splitLastKeyPart(key string) (string, string) {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return "", key
+	}
+	return key[:i], key[i+1:]
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
@@ -27,6 +28,8 @@ var licenseKeySysProp = "-Dnewrelic.config.license_key"
 type LicenseKey struct {
 	Value  string
 	Source string
+	// Key is the config setting the license key was read from, with the keys it's nested in, empty for other sources
+	Key string `json:",omitempty"`
 }
 
 // BaseConfigLicenseKey - Struct for task definition
@@ -47,6 +50,7 @@ func (t BaseConfigLicenseKey) Explain() string {
 func (t BaseConfigLicenseKey) Dependencies() []string {
 	return []string{
 		"Base/Config/Validate",
+		"Python/Config/Environment",
 		"Base/Env/CollectEnvVars",
 		"Base/Env/CollectSysProps",
 	}
@@ -62,7 +66,7 @@ func (t BaseConfigLicenseKey) Execute(options tasks.Options, upstream map[string
 
 	configElements, ok := upstream["Base/Config/Validate"].Payload.([]ValidateElement)
 	if ok {
-		licenseKeysFromConfig = getLicenseKeysFromConfig(configElements, licenseKeyConfigNames)
+		licenseKeysFromConfig = getLicenseKeysFromConfig(withPythonEnvironments(configElements, upstream), licenseKeyConfigNames)
 		licenseKeys = append(licenseKeys, licenseKeysFromConfig...)
 	}
 
@@ -183,6 +187,7 @@ func getLicenseKeysFromConfig(configElements []ValidateElement, configKeys []str
 				licenseKey := LicenseKey{
 					Value:  key.Value(),
 					Source: configFile.Config.FilePath + configFile.Config.FileName,
+					Key:    strings.Replace(strings.TrimPrefix(key.PathAndKey(), "/"), "/", ".", -1),
 				}
 
 				if detectEnvLicenseKey(licenseKey.Value) {
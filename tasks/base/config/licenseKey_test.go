@@ -125,6 +125,7 @@ func Test_getLicenseKeysFromConfig(t *testing.T) {
 				{
 					Value:  "Schnauzer12",
 					Source: "/app/newrelic.ini",
+					Key:    "newrelic.license",
 				},
 			},
 		},
@@ -151,6 +152,7 @@ func Test_getLicenseKeysFromConfig(t *testing.T) {
 				{
 					Value:  "",
 					Source: "/app/newrelic.ini",
+					Key:    "newrelic.license",
 				},
 			},
 		},
@@ -290,6 +292,7 @@ var _ = Describe("BaseConfigLicenseKey", func() {
 		It("Should return a slice with dependencies", func() {
 			expectedDependencies := []string{
 				"Base/Config/Validate",
+				"Python/Config/Environment",
 				"Base/Env/CollectEnvVars",
 				"Base/Env/CollectSysProps",
 			}
@@ -357,10 +360,12 @@ var _ = Describe("BaseConfigLicenseKey", func() {
 					{
 						Value:  "Schnauzer12",
 						Source: "/etc/newrelic.yml",
+						Key:    "license_key",
 					},
 					{
 						Value:  "Schnauzer12",
 						Source: "/app/newrelic.ini",
+						Key:    "newrelic.license",
 					},
 					{
 						Value:  "Schnauzer12",
@@ -435,10 +440,12 @@ var _ = Describe("BaseConfigLicenseKey", func() {
 					{
 						Value:  "Schnauzer12",
 						Source: "/app/newrelic.ini",
+						Key:    "newrelic.license",
 					},
 					{
 						Value:  "Schnauzer12",
 						Source: "/etc/newrelic.yml",
+						Key:    "license_key",
 					},
 					{
 						Value:  "Schnauzer12",
@@ -519,10 +526,12 @@ var _ = Describe("BaseConfigLicenseKey", func() {
 					{
 						Value:  "Schnauzer12",
 						Source: "/app/newrelic.ini",
+						Key:    "newrelic.license",
 					},
 					{
 						Value:  "Schnauzer12",
 						Source: "/etc/newrelic.yml",
+						Key:    "license_key",
 					},
 					{
 						Value:  "mylicensekey",
@@ -588,6 +597,7 @@ var _ = Describe("BaseConfigLicenseKey", func() {
 					{
 						Value:  "<%license_key goes here%>",
 						Source: "/newrelic/newrelic.yml",
+						Key:    "license_key",
 					},
 					{
 						Value:  "mylicensekey",
@@ -667,14 +677,17 @@ var _ = Describe("BaseConfigLicenseKey", func() {
 					{
 						Value:  "Banana",
 						Source: "/app/newrelic.ini",
+						Key:    "newrelic.license",
 					},
 					{
 						Value:  "Schnauzer12",
 						Source: "/etc/newrelic.yml",
+						Key:    "license_key",
 					},
 					{
 						Value:  "Schnauzer13",
 						Source: "/etc/newrelic.yml",
+						Key:    "license_key",
 					},
 				},
 			}
@@ -696,6 +709,37 @@ var _ = Describe("BaseConfigLicenseKey", func() {
 
 		})
 
+		Context("When Python/Config/Environment resolved the sections of a newrelic.ini", func() {
+			BeforeEach(func() {
+				options = tasks.Options{}
+				pythonConfig := ConfigElement{FileName: "newrelic.ini", FilePath: "/app/"}
+				upstream = map[string]tasks.Result{
+					"Base/Config/Validate": tasks.Result{
+						Status: tasks.Success,
+						Payload: []ValidateElement{{
+							Config: pythonConfig,
+							ParsedResult: tasks.ValidateBlob{Children: []tasks.ValidateBlob{
+								{Key: "newrelic", Children: []tasks.ValidateBlob{{Key: "license_key", RawValue: "Schnauzer12"}}},
+								{Key: "newrelic:staging", Children: []tasks.ValidateBlob{{Key: "license_key", RawValue: "Staging12"}}},
+							}},
+						}},
+					},
+					"Python/Config/Environment": tasks.Result{
+						Status: tasks.Success,
+						Payload: []ValidateElement{{
+							Config:       pythonConfig,
+							ParsedResult: tasks.ValidateBlob{Children: []tasks.ValidateBlob{{Key: "license_key", RawValue: "Schnauzer12"}}},
+						}},
+					},
+				}
+			})
+
+			It("Should only report the license key of the environment the agent runs in", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+				Expect(result.Payload).To(ConsistOf([]LicenseKey{{Value: "Schnauzer12", Source: "/app/newrelic.ini", Key: "license_key"}}))
+			})
+		})
+
 		Context("When no license keys are found", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
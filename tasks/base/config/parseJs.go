@@ -0,0 +1,531 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// parseJs - reads the object literal newrelic.js exports as its config, through exports.config, module.exports.config,
+// module.exports = { config } or export const config. Values that aren't literals, e.g. process.env.NEW_RELIC_APP_NAME,
+// are kept as tasks.DynamicValue with their source, as are spreads and computed keys that can't be read from the file.
+// Nested objects are keyed by their dotted names, with a "{" value for the object itself. Syntax errors are reported
+// with their line, along with the settings read around them.
+func parseJs(reader io.Reader) (tasks.ValidateBlob, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return tasks.ValidateBlob{}, fmt.Errorf("%v : %v", errConfigFileNotRead, err)
+	}
+	tokens, tokenErr := tokenizeJs(string(data))
+	p := jsParser{src: string(data), tokens: tokens, vars: make(map[string]int), resolving: make(map[string]bool)}
+	if tokenErr != nil {
+		p.errs = append(p.errs, tokenErr.Error())
+	}
+
+	settings := make(map[string]interface{})
+	switch config := p.parseConfig().(type) {
+	case map[string]interface{}:
+		flattenJsObject("", config, settings)
+	case nil:
+	default:
+		log.Debug("newrelic.js exports a config that isn't an object literal:", config)
+	}
+
+	result := convertToValidateBlob(settings)
+	if len(p.errs) > 0 {
+		return result, errors.New(strings.Join(p.errs, "\n"))
+	}
+	return result, nil
+}
+
+func flattenJsObject(prefix string, object map[string]interface{}, settings map[string]interface{}) {
+	for key, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			settings[prefix+key] = "{"
+			flattenJsObject(prefix+key+".", nested, settings)
+			continue
+		}
+		settings[prefix+key] = value
+	}
+}
+
+type jsTokenKind int
+
+const (
+	jsEOF jsTokenKind = iota
+	jsIdent
+	jsString
+	jsTemplate
+	jsNumber
+	jsRegex
+	jsPunct
+)
+
+type jsToken struct {
+	kind  jsTokenKind
+	text  string
+	line  int
+	start int
+	end   int
+}
+
+// tokenizeJs - splits the source into tokens, dropping comments. On an unterminated string or comment it returns the
+// tokens read so far and an error naming the line.
+func tokenizeJs(src string) ([]jsToken, error) {
+	var tokens []jsToken
+	line := 1
+	eof := func() []jsToken {
+		return append(tokens, jsToken{kind: jsEOF, line: line, start: len(src), end: len(src)})
+	}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//") || (i == 0 && strings.HasPrefix(src, "#!")):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return eof(), fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '\'' || c == '"':
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' {
+					j++
+				} else if src[j] == '\n' {
+					break
+				}
+			}
+			if j >= len(src) || src[j] != c {
+				return eof(), fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, jsToken{kind: jsString, text: unescapeJs(src[i+1 : j]), line: line, start: i, end: j + 1})
+			line += strings.Count(src[i:j], "\n")
+			i = j + 1
+		case c == '`':
+			j, depth := i+1, 0
+			for ; j < len(src); j++ {
+				if src[j] == '\\' {
+					j++
+				} else if src[j] == '`' && depth == 0 {
+					break
+				} else if strings.HasPrefix(src[j:], "${") {
+					depth++
+				} else if src[j] == '}' && depth > 0 {
+					depth--
+				}
+			}
+			if j >= len(src) {
+				return eof(), fmt.Errorf("line %d: unterminated template string", line)
+			}
+			tokens = append(tokens, jsToken{kind: jsTemplate, text: src[i+1 : j], line: line, start: i, end: j + 1})
+			line += strings.Count(src[i:j], "\n")
+			i = j + 1
+		case isJsDigit(c) || (c == '.' && i+1 < len(src) && isJsDigit(src[i+1])):
+			j := i + 1
+			for j < len(src) && (isJsIdentChar(src[j]) || src[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, jsToken{kind: jsNumber, text: src[i:j], line: line, start: i, end: j})
+			i = j
+		case isJsIdentChar(c):
+			j := i + 1
+			for j < len(src) && isJsIdentChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, jsToken{kind: jsIdent, text: src[i:j], line: line, start: i, end: j})
+			i = j
+		case c == '/' && regexAllowed(tokens):
+			j, inClass := i+1, false
+			for ; j < len(src) && src[j] != '\n'; j++ {
+				if src[j] == '\\' {
+					j++
+				} else if src[j] == '[' {
+					inClass = true
+				} else if src[j] == ']' {
+					inClass = false
+				} else if src[j] == '/' && !inClass {
+					break
+				}
+			}
+			if j >= len(src) || src[j] != '/' {
+				return eof(), fmt.Errorf("line %d: unterminated regular expression", line)
+			}
+			j++
+			for j < len(src) && isJsIdentChar(src[j]) {
+				j++
+			}
+			tokens = append(tokens, jsToken{kind: jsRegex, text: src[i:j], line: line, start: i, end: j})
+			i = j
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, jsToken{kind: jsPunct, text: "...", line: line, start: i, end: i + 3})
+			i += 3
+		default:
+			tokens = append(tokens, jsToken{kind: jsPunct, text: string(c), line: line, start: i, end: i + 1})
+			i++
+		}
+	}
+	return eof(), nil
+}
+
+func isJsDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isJsIdentChar(c byte) bool {
+	return c == '_' || c == '$' || isJsDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+// regexAllowed - a slash starts a regular expression, rather than dividing, where a value is expected
+func regexAllowed(tokens []jsToken) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	previous := tokens[len(tokens)-1]
+	return previous.kind == jsPunct && previous.text != ")" && previous.text != "]" && previous.text != "}"
+}
+
+var jsEscapes = strings.NewReplacer(`\\`, `\`, `\'`, `'`, `\"`, `"`, `\n`, "\n", `\t`, "\t", `\r`, "\r", "\\\n", "")
+
+func unescapeJs(s string) string {
+	return jsEscapes.Replace(s)
+}
+
+type jsParser struct {
+	src    string
+	tokens []jsToken
+	pos    int
+	// vars are the token indexes of the values top-level variables are declared with
+	vars      map[string]int
+	resolving map[string]bool
+	errs      []string
+}
+
+func (p *jsParser) peek() jsToken {
+	return p.tokens[p.pos]
+}
+
+func (p *jsParser) next() jsToken {
+	t := p.tokens[p.pos]
+	if t.kind != jsEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *jsParser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == jsPunct || t.kind == jsIdent) && t.text == text
+}
+
+func (p *jsParser) matches(i int, texts ...string) bool {
+	for j, text := range texts {
+		if i+j >= len(p.tokens) || p.tokens[i+j].text != text || p.tokens[i+j].kind == jsString {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *jsParser) errorf(t jsToken, format string, args ...interface{}) {
+	p.errs = append(p.errs, fmt.Sprintf("line %d: ", t.line)+fmt.Sprintf(format, args...))
+}
+
+// parseConfig - finds the assignment of the config and parses its value. The last assignment wins, as it would when
+// the file is run.
+func (p *jsParser) parseConfig() interface{} {
+	config, moduleExports := -1, -1
+	for i := range p.tokens {
+		if i > 0 && p.tokens[i-1].text == "." {
+			continue
+		}
+		switch {
+		case p.matches(i, "exports", ".", "config", "="):
+			config = i + 4
+		case p.matches(i, "module", ".", "exports", ".", "config", "="):
+			config = i + 6
+		case p.matches(i, "export", "const", "config", "="):
+			config = i + 4
+		case p.matches(i, "module", ".", "exports", "="):
+			moduleExports = i + 4
+		}
+		if (p.matches(i, "const") || p.matches(i, "let") || p.matches(i, "var")) && i+2 < len(p.tokens) &&
+			p.tokens[i+1].kind == jsIdent && p.matches(i+2, "=") {
+			p.vars[p.tokens[i+1].text] = i + 3
+		}
+	}
+
+	if config >= 0 {
+		return p.parseValueAt(config)
+	}
+	if moduleExports >= 0 {
+		if exported, ok := p.parseValueAt(moduleExports).(map[string]interface{}); ok && exported["config"] != nil {
+			return exported["config"]
+		}
+	}
+	if len(p.errs) == 0 {
+		p.errorf(p.tokens[0], "no exports.config assignment found")
+	}
+	return nil
+}
+
+func (p *jsParser) parseValueAt(i int) interface{} {
+	saved := p.pos
+	p.pos = i
+	value := p.parseValue()
+	p.pos = saved
+	return value
+}
+
+// resolveVar - the value a variable is declared with, or its name as a dynamic value when it isn't declared here
+func (p *jsParser) resolveVar(name string) interface{} {
+	i, ok := p.vars[name]
+	if !ok || p.resolving[name] {
+		return tasks.DynamicValue(name)
+	}
+	p.resolving[name] = true
+	defer delete(p.resolving, name)
+	return p.parseValueAt(i)
+}
+
+func (p *jsParser) atValueEnd() bool {
+	t := p.peek()
+	return t.kind == jsEOF || (t.kind == jsPunct && strings.Contains(",}];)", t.text))
+}
+
+func (p *jsParser) parseValue() interface{} {
+	t := p.peek()
+	switch {
+	case t.kind == jsPunct && t.text == "{":
+		return p.parseObject()
+	case t.kind == jsPunct && t.text == "[":
+		return p.parseArray()
+	}
+
+	start := p.pos
+	p.next()
+	if p.atValueEnd() {
+		switch t.kind {
+		case jsString, jsNumber, jsRegex:
+			return t.text
+		case jsTemplate:
+			if !strings.Contains(t.text, "${") {
+				return t.text
+			}
+		case jsIdent:
+			switch t.text {
+			case "true", "false":
+				return t.text
+			case "null", "undefined":
+				return nil
+			}
+			return p.resolveVar(t.text)
+		}
+	}
+	p.pos = start
+	expression := p.skipExpression()
+	if expression == tasks.DynamicValue("") {
+		if t.kind != jsEOF {
+			p.errorf(t, "expected a value but found '%s'", t.text)
+		}
+		return nil
+	}
+	return expression
+}
+
+// skipExpression - consumes tokens up to the end of the value, keeping brackets balanced, and returns its source
+func (p *jsParser) skipExpression() interface{} {
+	start := p.peek()
+	end := start.start
+	var open []string
+	for {
+		t := p.peek()
+		if t.kind == jsEOF {
+			if len(open) > 0 {
+				p.errorf(t, "missing '%s'", open[len(open)-1])
+			}
+			break
+		}
+		if t.kind == jsPunct {
+			if len(open) == 0 && p.atValueEnd() {
+				break
+			}
+			switch t.text {
+			case "{":
+				open = append(open, "}")
+			case "[":
+				open = append(open, "]")
+			case "(":
+				open = append(open, ")")
+			case "}", "]", ")":
+				if len(open) == 0 || open[len(open)-1] != t.text {
+					p.errorf(t, "unexpected '%s'", t.text)
+					return tasks.DynamicValue(strings.TrimSpace(p.src[start.start:end]))
+				}
+				open = open[:len(open)-1]
+			}
+		}
+		end = p.next().end
+	}
+	return tasks.DynamicValue(strings.TrimSpace(p.src[start.start:end]))
+}
+
+func (p *jsParser) parseObject() interface{} {
+	open := p.next()
+	object := make(map[string]interface{})
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == jsEOF:
+			p.errorf(open, "object is missing its closing '}'")
+			return object
+		case p.is("}"):
+			p.next()
+			return object
+		case p.is("..."):
+			p.next()
+			p.parseSpread(object)
+		default:
+			p.parseProperty(object)
+		}
+		if !p.is(",") && !p.is("}") && p.peek().kind != jsEOF {
+			p.errorf(p.peek(), "expected ',' or '}' but found '%s'", p.peek().text)
+			if p.atKey() {
+				// only the comma is missing, carry on with the next property
+				continue
+			}
+			p.recover()
+		}
+		if p.is(",") {
+			p.next()
+		}
+	}
+}
+
+// atKey - whether the next tokens are the key of a property, e.g. license_key:
+func (p *jsParser) atKey() bool {
+	t := p.peek()
+	return (t.kind == jsIdent || t.kind == jsString) && p.pos+1 < len(p.tokens) && p.matches(p.pos+1, ":")
+}
+
+func (p *jsParser) parseProperty(object map[string]interface{}) {
+	t := p.next()
+	var key string
+	switch {
+	case t.kind == jsIdent || t.kind == jsString || t.kind == jsNumber:
+		key = t.text
+	case t.kind == jsPunct && t.text == "[":
+		// a computed key is read when it's a string, e.g. ['app_name'], and kept with its brackets otherwise
+		start := p.peek()
+		name, isString := p.parseValue().(string)
+		source := strings.TrimSpace(p.src[start.start:p.peek().start])
+		if !p.is("]") {
+			p.errorf(t, "computed key is missing its closing ']'")
+			p.recover()
+			return
+		}
+		p.next()
+		if isString {
+			key = name
+			break
+		}
+		key = "[" + source + "]"
+		if p.is(":") {
+			p.next()
+			object[key] = p.dynamicValue()
+			return
+		}
+	default:
+		p.errorf(t, "unexpected '%s' where a key was expected", t.text)
+		p.recover()
+		return
+	}
+
+	switch {
+	case p.is(":"):
+		p.next()
+		object[key] = p.parseValue()
+	case p.is(",") || p.is("}"):
+		// shorthand property, e.g. { config }
+		object[key] = p.resolveVar(key)
+	case p.is("("):
+		// a method, not a setting
+		p.skipExpression()
+	default:
+		p.errorf(p.peek(), "expected ':' after '%s'", key)
+		p.recover()
+	}
+}
+
+// parseSpread - copies the properties of a spread object into the object, e.g. ...defaults for a variable declared in
+// newrelic.js. Other spreads are kept as a dynamic value keyed by their source, e.g. ...require('./defaults').
+func (p *jsParser) parseSpread(object map[string]interface{}) {
+	start := p.peek()
+	if properties, ok := p.parseValue().(map[string]interface{}); ok {
+		for key, value := range properties {
+			object[key] = value
+		}
+		return
+	}
+	key := "..." + strings.TrimSpace(p.src[start.start:p.peek().start])
+	object[key] = tasks.DynamicValue(key)
+}
+
+// dynamicValue - the next value as a dynamic value holding its source, for a value whose key is only known when the
+// agent runs
+func (p *jsParser) dynamicValue() tasks.DynamicValue {
+	start := p.peek()
+	p.parseValue()
+	return tasks.DynamicValue(strings.TrimSpace(p.src[start.start:p.peek().start]))
+}
+
+// isDynamicJsKey - whether a key of newrelic.js is a spread or a computed key that isn't a string, whose settings are
+// only known when the agent runs
+func isDynamicJsKey(key string) bool {
+	return strings.Contains(key, "...") || strings.Contains(key, "[")
+}
+
+func (p *jsParser) parseArray() interface{} {
+	open := p.next()
+	array := []interface{}{}
+	for {
+		switch {
+		case p.peek().kind == jsEOF:
+			p.errorf(open, "array is missing its closing ']'")
+			return array
+		case p.is("]"):
+			p.next()
+			return array
+		case p.is(","):
+			p.next()
+			continue
+		}
+		array = append(array, p.parseValue())
+		if !p.is(",") && !p.is("]") && p.peek().kind != jsEOF {
+			p.errorf(p.peek(), "expected ',' or ']' but found '%s'", p.peek().text)
+			p.recover()
+		}
+	}
+}
+
+// recover - skips the rest of a property after a syntax error, so the ones after it are still read
+func (p *jsParser) recover() {
+	p.skipExpression()
+	if p.peek().kind == jsPunct && strings.Contains(";)", p.peek().text) {
+		p.next()
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("parseJs()", func() {
+	settings := func(source string) (map[string]interface{}, error) {
+		blob, err := parseJs(strings.NewReader(source))
+		flattened := make(map[string]interface{})
+		flattenSettings(blob.Children, "", flattened)
+		return flattened, err
+	}
+
+	DescribeTable("should read the exported config",
+		func(source string, expected map[string]interface{}) {
+			parsed, err := settings(source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(expected))
+		},
+		Entry("with comments, trailing commas and strings holding delimiters",
+			"'use strict'\n/* header */\nexports.config = {\n  app_name: ['a, b', \"c\"], // names\n  license_key: 'k}e,y',\n  logging: { level: 'info', },\n}\n",
+			map[string]interface{}{"app_name": []interface{}{"a, b", "c"}, "license_key": "k}e,y", "logging.level": "info"}),
+		Entry("with template strings",
+			"exports.config = { app_name: [`plain`], host: `${region}.newrelic.com` }",
+			map[string]interface{}{"app_name": []interface{}{"plain"}, "host": tasks.DynamicValue("`${region}.newrelic.com`")}),
+		Entry("with environment variable references and computed values",
+			"exports.config = {\n  license_key: process.env.LICENSE_KEY,\n  agent_enabled: process.env.NODE_ENV !== 'test',\n  port: 443,\n}",
+			map[string]interface{}{
+				"license_key":   tasks.DynamicValue("process.env.LICENSE_KEY"),
+				"agent_enabled": tasks.DynamicValue("process.env.NODE_ENV !== 'test'"),
+				"port":          "443",
+			}),
+		Entry("from module.exports with a shorthand property",
+			"const config = {\n  app_name: ['shorthand'],\n  rules: { ignore: [/^\\/health/] },\n}\nmodule.exports = { config }\n",
+			map[string]interface{}{"app_name": []interface{}{"shorthand"}, "rules.ignore": []interface{}{`/^\/health/`}}),
+		Entry("with a spread of a declared object",
+			"const defaults = { app_name: ['base'], logging: { level: 'info' } }\nexports.config = {\n  ...defaults,\n  app_name: ['override'],\n}\n",
+			map[string]interface{}{"app_name": []interface{}{"override"}, "logging.level": "info"}),
+		Entry("with spreads and computed keys only known when the agent runs",
+			"exports.config = {\n  ...require('./defaults'),\n  ['app_name']: ['literal'],\n  [process.env.KEY_NAME]: 'value',\n}\n",
+			map[string]interface{}{
+				"...require('./defaults')": tasks.DynamicValue("...require('./defaults')"),
+				"app_name":                 []interface{}{"literal"},
+				"[process.env.KEY_NAME]":   tasks.DynamicValue("'value'"),
+			}),
+		Entry("from module.exports.config",
+			"module.exports.config = { app_name: 'direct' };",
+			map[string]interface{}{"app_name": "direct"}),
+	)
+
+	It("should mark dynamic values", func() {
+		blob, err := parseJs(strings.NewReader("exports.config = { license_key: process.env.KEY, app_name: 'static' }"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blob.FindKey("license_key")[0].IsDynamic()).To(BeTrue())
+		Expect(blob.FindKey("license_key")[0].Value()).To(Equal("process.env.KEY"))
+		Expect(blob.FindKey("app_name")[0].IsDynamic()).To(BeFalse())
+	})
+
+	DescribeTable("should report syntax errors with their line",
+		func(source string, expectedError string, expected map[string]interface{}) {
+			parsed, err := settings(source)
+			Expect(err).To(MatchError(expectedError))
+			Expect(parsed).To(Equal(expected))
+		},
+		Entry("for a missing comma",
+			"exports.config = {\n  app_name: ['a']\n  license_key: 'key',\n}",
+			"line 3: expected ',' or '}' but found 'license_key'",
+			map[string]interface{}{"app_name": []interface{}{"a"}, "license_key": "key"}),
+		Entry("for an unterminated string",
+			"exports.config = {\n  app_name: ['a'],\n  license_key: 'key,\n}",
+			"line 3: unterminated string\nline 1: object is missing its closing '}'",
+			map[string]interface{}{"app_name": []interface{}{"a"}, "license_key": nil}),
+		Entry("for a file without a config",
+			"module.exports = require('./other')",
+			"line 1: no exports.config assignment found",
+			map[string]interface{}{}),
+	)
+})
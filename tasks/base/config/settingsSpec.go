@@ -0,0 +1,564 @@
+package config
+
+//go:generate go run ../../../scripts/specgen -dir specs -out builtinSpecs.go
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/output/color"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsSpec - describes the settings an agent accepts. Specs are the YAML files of the specs directory, one per
+// agent, shared by the <Agent>/Config/ValidateSettings tasks, the key suggestions of Base/Config/Validate and
+// Base/Config/Effective. They are built into nrdiag by go generate, and the -spec-dir flag (specDir task option) loads
+// the files of another directory instead, so a spec can be updated without a new build. The specFile task option
+// replaces the spec of a single task. A spec reads:
+//
+//	agent: Ruby                    # the agent name Base/Config/Effective reports
+//	docs: https://docs.newrelic.com/...
+//	files: [newrelic.yml]          # the config file names holding the settings
+//	prefix: newrelic.              # optional, only keys with this prefix belong to the agent
+//	sections: [common, production] # optional, top-level sections holding the settings, defaults first
+//	envPrefix: NRIA_               # optional, settings are overridden by <envPrefix><KEY> environment variables
+//	sysPropPrefix: -Dnewrelic.config. # optional, settings are overridden by JVM system properties
+//	fileFirst: true                # optional, the config file takes precedence over environment variables
+//	settings:
+//	  log_level:
+//	    type: Enum                 # String, Boolean, Integer, Float, Duration, Enum, List, Map or Object
+//	    values: [error, warn, info, debug]
+//	    env: [NEW_RELIC_LOG_LEVEL] # optional, the environment variables overriding the setting
+//	    default: info              # optional, the value the agent uses when the setting is not set
+//	  proxy_pass:
+//	    type: String
+//	    secret: true               # optional, the value is masked in the results
+//	  capture_params:
+//	    type: Boolean
+//	    deprecated: use attributes.include instead
+type SettingsSpec struct {
+	Agent         string                 `yaml:"agent"`
+	Docs          string                 `yaml:"docs"`
+	Files         []string               `yaml:"files"`
+	Prefix        string                 `yaml:"prefix"`
+	Sections      []string               `yaml:"sections"`
+	EnvPrefix     string                 `yaml:"envPrefix"`
+	SysPropPrefix string                 `yaml:"sysPropPrefix"`
+	FileFirst     bool                   `yaml:"fileFirst"`
+	Settings      map[string]SettingSpec `yaml:"settings"`
+}
+
+// SettingSpec - the expected type and lifecycle of a single setting, and where else the agent reads it from
+type SettingSpec struct {
+	Type       string   `yaml:"type"`
+	Values     []string `yaml:"values"`
+	Deprecated string   `yaml:"deprecated"`
+	Removed    string   `yaml:"removed"`
+	Env        []string `yaml:"env"`
+	Default    string   `yaml:"default"`
+	Secret     bool     `yaml:"secret"`
+}
+
+// SettingStatus - the kind of problem found with a setting
+type SettingStatus string
+
+// Statuses of a SettingProblem
+const (
+	SettingInvalid    SettingStatus = "Invalid"
+	SettingUnknown    SettingStatus = "Unknown"
+	SettingDeprecated SettingStatus = "Deprecated"
+	SettingRemoved    SettingStatus = "Removed"
+	SettingShadowed   SettingStatus = "Shadowed"
+	SettingConflict   SettingStatus = "Conflict"
+)
+
+// SettingProblem - a setting of a config file that doesn't match the spec
+type SettingProblem struct {
+	Key     string
+	Value   interface{}
+	Status  SettingStatus
+	Message string
+}
+
+// SettingsValidation - the problems found in one config file
+type SettingsValidation struct {
+	File     string
+	Problems []SettingProblem
+}
+
+// ParseSettingsSpec - reads a settings spec from its YAML document
+func ParseSettingsSpec(document []byte) (SettingsSpec, error) {
+	var spec SettingsSpec
+	if err := yaml.Unmarshal(document, &spec); err != nil {
+		return spec, err
+	}
+	for key, setting := range spec.Settings {
+		if _, known := settingValidators[setting.Type]; !known {
+			return spec, fmt.Errorf("setting %s has an unknown type %q", key, setting.Type)
+		}
+	}
+	return spec, nil
+}
+
+// LoadSettingsSpec - returns the spec file given with the specFile task option, or the named spec. The named spec is
+// read from <name>.yml in the directory given with the specDir task option when it holds one, and is the builtin one
+// otherwise
+func LoadSettingsSpec(options tasks.Options, name string) (SettingsSpec, error) {
+	if specFile := options.Options["specFile"]; specFile != "" {
+		return readSettingsSpec(specFile)
+	}
+	return loadNamedSettingsSpec(options, name)
+}
+
+// loadNamedSettingsSpec - the spec directory belongs to nrdiag rather than to the diagnosed host, so it is read
+// without going through -root
+func loadNamedSettingsSpec(options tasks.Options, name string) (SettingsSpec, error) {
+	if specDir := options.Options["specDir"]; specDir != "" {
+		specFile := filepath.Join(specDir, name+".yml")
+		document, err := ioutil.ReadFile(specFile)
+		if err == nil {
+			return parseSettingsSpecFile(specFile, document)
+		}
+		if !os.IsNotExist(err) {
+			return SettingsSpec{}, err
+		}
+		log.Debug("No", name, "settings spec in", specDir, "- using the builtin one")
+	}
+	builtin, ok := builtinSettingsSpecs[name]
+	if !ok {
+		return SettingsSpec{}, fmt.Errorf("there is no settings spec named %s", name)
+	}
+	return ParseSettingsSpec([]byte(builtin))
+}
+
+func readSettingsSpec(specFile string) (SettingsSpec, error) {
+	document, err := tasks.ReadFileBytes(specFile)
+	if err != nil {
+		return SettingsSpec{}, err
+	}
+	return parseSettingsSpecFile(specFile, document)
+}
+
+func parseSettingsSpecFile(specFile string, document []byte) (SettingsSpec, error) {
+	spec, err := ParseSettingsSpec(document)
+	if err != nil {
+		return spec, fmt.Errorf("%s: %s", specFile, err)
+	}
+	return spec, nil
+}
+
+// Flatten - returns the settings of a parsed config file keyed by their dotted names, e.g. "logging.level".
+// Lists are kept as a single []interface{} value instead of one setting per element.
+func (s SettingsSpec) Flatten(blob tasks.ValidateBlob) map[string]interface{} {
+	settings := make(map[string]interface{})
+	flattenSettings(blob.Children, "", settings)
+	return settings
+}
+
+func flattenSettings(blobs []tasks.ValidateBlob, parent string, settings map[string]interface{}) {
+	for _, blob := range blobs {
+		key := blob.Key
+		if parent != "" {
+			key = parent + "." + blob.Key
+		}
+		if blob.IsLeaf() {
+			// the newrelic.js parser keeps a "{" placeholder for objects next to their dotted children
+			if blob.RawValue == "{" {
+				continue
+			}
+			settings[key] = blob.RawValue
+			continue
+		}
+		if isList(blob.Children) {
+			var values []interface{}
+			for _, child := range blob.Children {
+				values = append(values, child.RawValue)
+			}
+			settings[key] = values
+			continue
+		}
+		flattenSettings(blob.Children, key, settings)
+	}
+}
+
+// isList - the parsers turn list elements into children keyed by their index
+func isList(blobs []tasks.ValidateBlob) bool {
+	for _, blob := range blobs {
+		if _, err := strconv.Atoi(blob.Key); err != nil || !blob.IsLeaf() {
+			return false
+		}
+	}
+	return true
+}
+
+// specKey - returns the key of a flattened setting as written in the spec, and false when it doesn't belong to the agent
+func (s SettingsSpec) specKey(key string) (string, bool) {
+	for _, section := range s.Sections {
+		if strings.HasPrefix(key, section+".") {
+			key = strings.TrimPrefix(key, section+".")
+			break
+		}
+	}
+	if s.Prefix != "" {
+		if !strings.HasPrefix(key, s.Prefix) {
+			return "", false
+		}
+		key = strings.TrimPrefix(key, s.Prefix)
+	}
+	return key, true
+}
+
+// lookup - finds the spec of a setting. Keys below a Map setting, e.g. labels.team, are accepted as single values,
+// keys below an Object setting can also hold lists.
+func (s SettingsSpec) lookup(key string) (SettingSpec, bool) {
+	name, ok := s.definedSetting(key)
+	if !ok {
+		return SettingSpec{}, false
+	}
+	setting := s.Settings[name]
+	switch {
+	case name == key:
+		return setting, true
+	case setting.Type == "Map":
+		return SettingSpec{Type: "String"}, true
+	default:
+		return SettingSpec{Type: "List"}, true
+	}
+}
+
+// knownSettings - the number of the flattened settings of a config file that belong to the spec
+func (s SettingsSpec) knownSettings(settings map[string]interface{}) int {
+	known := 0
+	for key := range settings {
+		if name, ok := s.specKey(key); ok {
+			if _, found := s.lookup(name); found {
+				known++
+			}
+		}
+	}
+	return known
+}
+
+// inherited - true when a setting of a section has the value it gets from the defaults section, as with
+// YAML merge keys, so that its problems are only reported once
+func (s SettingsSpec) inherited(key string, value interface{}, settings map[string]interface{}) bool {
+	if len(s.Sections) == 0 || strings.HasPrefix(key, s.Sections[0]+".") {
+		return false
+	}
+	for _, section := range s.Sections[1:] {
+		if strings.HasPrefix(key, section+".") {
+			defaultValue, ok := settings[s.Sections[0]+strings.TrimPrefix(key, section)]
+			return ok && reflect.DeepEqual(value, defaultValue)
+		}
+	}
+	return false
+}
+
+// Validate - checks the flattened settings against the spec, returning the problems sorted by key
+func (s SettingsSpec) Validate(settings map[string]interface{}) []SettingProblem {
+	problems := []SettingProblem{}
+	for key, value := range settings {
+		name, ok := s.specKey(key)
+		if !ok || s.inherited(key, value, settings) || isDynamicJsKey(key) {
+			continue
+		}
+		setting, known := s.lookup(name)
+		switch {
+		case !known:
+			problems = append(problems, SettingProblem{Key: key, Value: value, Status: SettingUnknown, Message: "is not a setting of this agent"})
+		case setting.Removed != "":
+			problems = append(problems, SettingProblem{Key: key, Value: value, Status: SettingRemoved, Message: "has been removed: " + setting.Removed})
+		default:
+			// the values of expressions are only known when the agent runs
+			_, dynamic := value.(tasks.DynamicValue)
+			if message := settingValidators[setting.Type](value, setting); message != "" && !dynamic {
+				problems = append(problems, SettingProblem{Key: key, Value: value, Status: SettingInvalid, Message: message})
+			} else if setting.Deprecated != "" {
+				problems = append(problems, SettingProblem{Key: key, Value: value, Status: SettingDeprecated, Message: "is deprecated: " + setting.Deprecated})
+			}
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Key < problems[j].Key
+	})
+	return problems
+}
+
+// EnvVar - the environment variable overriding a setting, e.g. NRIA_LOG_LEVEL for log.level, or "" when the
+// agent has no such variables
+func (s SettingsSpec) EnvVar(key string) string {
+	if s.EnvPrefix == "" {
+		return ""
+	}
+	return s.EnvPrefix + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+// Shadowed - reports the settings of a file that are overridden by an environment variable, so the value in
+// the file is not the one the agent runs with. The keys below a Map or Object setting are overridden together.
+func (s SettingsSpec) Shadowed(settings map[string]interface{}, envVars map[string]string) []SettingProblem {
+	shadowed := make(map[string]*SettingProblem)
+	for key, value := range settings {
+		name, ok := s.specKey(key)
+		if !ok {
+			continue
+		}
+		setting, known := s.definedSetting(name)
+		envVar := s.EnvVar(setting)
+		envValue, set := envVars[envVar]
+		if !known || !set || envVar == "" {
+			continue
+		}
+		if setting == name {
+			shadowed[key] = &SettingProblem{Key: key, Value: value, Status: SettingShadowed, Message: fmt.Sprintf("is overridden by %s=%s", envVar, envValue)}
+			continue
+		}
+		parentKey := strings.TrimSuffix(key, strings.TrimPrefix(name, setting))
+		if shadowed[parentKey] == nil {
+			shadowed[parentKey] = &SettingProblem{Key: parentKey, Value: map[string]interface{}{}, Status: SettingShadowed, Message: fmt.Sprintf("is overridden by %s=%s", envVar, envValue)}
+		}
+		shadowed[parentKey].Value.(map[string]interface{})[strings.TrimPrefix(name, setting+".")] = value
+	}
+	problems := []SettingProblem{}
+	for _, problem := range shadowed {
+		problems = append(problems, *problem)
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Key < problems[j].Key
+	})
+	return problems
+}
+
+// definedSetting - the key of the spec setting a key belongs to, which is its Map or Object parent for keys below one
+func (s SettingsSpec) definedSetting(key string) (string, bool) {
+	if _, ok := s.Settings[key]; ok {
+		return key, true
+	}
+	for parent := key; strings.Contains(parent, "."); {
+		parent = parent[:strings.LastIndex(parent, ".")]
+		if setting, ok := s.Settings[parent]; ok && (setting.Type == "Map" || setting.Type == "Object") {
+			return parent, true
+		}
+	}
+	return "", false
+}
+
+// SettingsCheck - an agent specific check of the flattened settings of a config file, run after the spec validation
+type SettingsCheck func(settings map[string]interface{}) []SettingProblem
+
+// ValidateAgentSettings - validates the settings of each config file found for an agent
+func ValidateAgentSettings(spec SettingsSpec, configs []ValidateElement, checks ...SettingsCheck) tasks.Result {
+	var validations []SettingsValidation
+	var summaries []string
+	var fixes []tasks.ConfigFix
+	status := tasks.Success
+	validated := make(map[string]bool)
+
+	for _, configFile := range configs {
+		file := configFile.Config.FilePath + configFile.Config.FileName
+		// files found without being parsed have nothing to validate, and the agent tasks can list a file more than once
+		if configFile.ParsedResult.IsLeaf() || validated[file] {
+			continue
+		}
+		validated[file] = true
+		settings := spec.Flatten(configFile.ParsedResult)
+		problems := spec.Validate(settings)
+		for _, check := range checks {
+			problems = append(problems, check(settings)...)
+		}
+		sort.SliceStable(problems, func(i, j int) bool {
+			return problems[i].Key < problems[j].Key
+		})
+		validations = append(validations, SettingsValidation{File: file, Problems: problems})
+		if len(problems) == 0 {
+			continue
+		}
+		summaries = append(summaries, file+":\n"+SummarizeSettingProblems(problems))
+		for _, problem := range problems {
+			if fix, ok := spec.enumFix(file, problem); ok {
+				fixes = append(fixes, fix)
+			}
+			if problem.Status == SettingInvalid || problem.Status == SettingRemoved || problem.Status == SettingConflict {
+				status = tasks.Warning
+			} else if status == tasks.Success {
+				status = tasks.Info
+			}
+		}
+	}
+
+	if len(validations) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No parsed config files to validate.",
+		}
+	}
+
+	result := tasks.Result{
+		Status:  status,
+		Payload: validations,
+		Fixes:   fixes,
+	}
+	switch status {
+	case tasks.Success:
+		result.Summary = "All settings match the agent's settings spec."
+	case tasks.Warning:
+		result.Summary = "Some settings have invalid values, conflict or are no longer supported by the agent:\n" + strings.Join(summaries, "\n")
+		result.URL = spec.Docs
+	default:
+		result.Summary = "Some settings are deprecated, overridden or not known to the agent:\n" + strings.Join(summaries, "\n")
+		result.URL = spec.Docs
+	}
+	return result
+}
+
+// enumFix - suggests the allowed value an invalid Enum value is a typo of, e.g. debug for debg
+func (s SettingsSpec) enumFix(file string, problem SettingProblem) (tasks.ConfigFix, bool) {
+	if problem.Status != SettingInvalid {
+		return tasks.ConfigFix{}, false
+	}
+	name, _ := s.specKey(problem.Key)
+	setting, _ := s.lookup(name)
+	value, ok := scalarString(problem.Value)
+	if setting.Type != "Enum" || !ok {
+		return tasks.ConfigFix{}, false
+	}
+	closest := tasks.ClosestMatch(value, setting.Values)
+	if closest == "" {
+		return tasks.ConfigFix{}, false
+	}
+	return tasks.ConfigFix{
+		File:        file,
+		Description: fmt.Sprintf("Change %s from %s to %s", problem.Key, value, closest),
+		Key:         problem.Key,
+		Value:       value,
+		NewValue:    closest,
+	}, true
+}
+
+// SummarizeSettingProblems - one line per problem, for the result summary
+func SummarizeSettingProblems(problems []SettingProblem) string {
+	var lines []string
+	for _, problem := range problems {
+		key := color.ColorString(color.White, problem.Key)
+		value := color.ColorString(color.LightRed, fmt.Sprintf("%v", problem.Value))
+		message := color.ColorString(color.Yellow, problem.Message)
+		lines = append(lines, fmt.Sprintf("    %s %s (%s) %s", problem.Status, key, value, message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+type settingValidator func(value interface{}, setting SettingSpec) string
+
+var settingValidators = map[string]settingValidator{
+	"String":   validateStringSetting,
+	"Boolean":  validateBooleanSetting,
+	"Integer":  validateIntegerSetting,
+	"Float":    validateFloatSetting,
+	"Duration": validateDurationSetting,
+	"Enum":     validateEnumSetting,
+	"List":     validateListSetting,
+	"Map":      validateMapSetting,
+	"Object":   validateMapSetting,
+}
+
+// scalarString - the value as written in the file, and false for lists
+func scalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case []interface{}, []string:
+		return "", false
+	case string:
+		return strings.TrimSpace(v), true
+	default:
+		return fmt.Sprintf("%v", v), true
+	}
+}
+
+func validateStringSetting(value interface{}, _ SettingSpec) string {
+	if _, ok := scalarString(value); !ok {
+		return "should be a single value, not a list"
+	}
+	return ""
+}
+
+func validateBooleanSetting(value interface{}, _ SettingSpec) string {
+	if value == nil {
+		return ""
+	}
+	str, _ := scalarString(value)
+	switch strings.ToLower(str) {
+	case "true", "false", "on", "off", "yes", "no", "1", "0":
+		return ""
+	}
+	return fmt.Sprintf("should be true or false (not %v)", value)
+}
+
+func validateIntegerSetting(value interface{}, _ SettingSpec) string {
+	if value == nil {
+		return ""
+	}
+	str, _ := scalarString(value)
+	if _, err := strconv.ParseInt(str, 10, 64); err != nil {
+		return fmt.Sprintf("should be a whole number (not %v)", value)
+	}
+	return ""
+}
+
+func validateFloatSetting(value interface{}, _ SettingSpec) string {
+	if value == nil {
+		return ""
+	}
+	str, _ := scalarString(value)
+	if _, err := strconv.ParseFloat(str, 64); err != nil {
+		return fmt.Sprintf("should be a number (not %v)", value)
+	}
+	return ""
+}
+
+// validateDurationSetting - a number of seconds, or a Go duration such as 30s or 1m
+func validateDurationSetting(value interface{}, _ SettingSpec) string {
+	if value == nil {
+		return ""
+	}
+	str, _ := scalarString(value)
+	if _, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return ""
+	}
+	if _, err := time.ParseDuration(str); err != nil {
+		return fmt.Sprintf("should be a number of seconds or a duration such as 30s (not %v)", value)
+	}
+	return ""
+}
+
+func validateEnumSetting(value interface{}, setting SettingSpec) string {
+	if value == nil {
+		return ""
+	}
+	str, _ := scalarString(value)
+	for _, allowed := range setting.Values {
+		if strings.EqualFold(str, allowed) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("should be one of %s", strings.Join(setting.Values, ", "))
+}
+
+// validateListSetting - lists can be written inline as comma or space separated strings in ini files
+func validateListSetting(_ interface{}, _ SettingSpec) string {
+	return ""
+}
+
+// validateMapSetting - a Map written as a single value, e.g. Ruby labels: "team:api;env:prod"
+func validateMapSetting(value interface{}, _ SettingSpec) string {
+	if _, ok := scalarString(value); !ok {
+		return "should be a set of key/value pairs, not a list"
+	}
+	return ""
+}
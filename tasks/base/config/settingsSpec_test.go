@@ -0,0 +1,273 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var testSettingsSpec = `
+docs: https://docs.newrelic.com/docs/agents/manage-apm-agents/configuration
+sections: [common, production]
+settings:
+  app_name: {type: List}
+  log_level: {type: Enum, values: [error, warn, info, debug]}
+  port: {type: Integer}
+  apdex_t: {type: Float}
+  harvest_interval: {type: Duration}
+  labels: {type: Map}
+  include_matching_metrics: {type: Object}
+  transaction_tracer.enabled: {type: Boolean}
+  transaction_tracer.record_sql: {type: Enum, values: ['off', obfuscated, raw]}
+  capture_params:
+    type: Boolean
+    deprecated: use attributes.include instead
+  ssl:
+    type: Boolean
+    removed: the agent always connects over HTTPS
+`
+
+// useSettingsSpecs replaces the builtin and registered specs with the given documents, named after their position,
+// and returns a function restoring them
+func useSettingsSpecs(documents ...string) func() {
+	builtin, registered := builtinSettingsSpecs, settingsSpecs
+	builtinSettingsSpecs, settingsSpecs = map[string]string{}, nil
+	for i, document := range documents {
+		name := "spec" + strconv.Itoa(i)
+		builtinSettingsSpecs[name] = document
+		settingsSpecs = append(settingsSpecs, name)
+	}
+	return func() {
+		builtinSettingsSpecs, settingsSpecs = builtin, registered
+	}
+}
+
+var _ = Describe("SettingsSpec", func() {
+	var spec SettingsSpec
+
+	BeforeEach(func() {
+		var err error
+		spec, err = ParseSettingsSpec([]byte(testSettingsSpec))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("ParseSettingsSpec()", func() {
+		It("should reject settings of an unknown type", func() {
+			_, err := ParseSettingsSpec([]byte("settings:\n  port: {type: Number}\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("builtinSettingsSpecs", func() {
+		It("should match the files of the specs directory, run go generate after editing them", func() {
+			paths, err := filepath.Glob(filepath.Join("specs", "*.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builtinSettingsSpecs).To(HaveLen(len(paths)))
+			for _, path := range paths {
+				content, err := ioutil.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builtinSettingsSpecs[strings.TrimSuffix(filepath.Base(path), ".yml")]).To(Equal(string(content)), path)
+			}
+		})
+		It("should only hold valid specs", func() {
+			for name, document := range builtinSettingsSpecs {
+				_, err := ParseSettingsSpec([]byte(document))
+				Expect(err).NotTo(HaveOccurred(), name)
+			}
+		})
+	})
+
+	Describe("LoadSettingsSpec()", func() {
+		var specDir string
+
+		BeforeEach(func() {
+			var err error
+			specDir, err = ioutil.TempDir("", "nrdiag-specs")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(specDir, "ruby.yml"), []byte("agent: Ruby from specDir\n"), 0644)).To(Succeed())
+		})
+		AfterEach(func() {
+			os.RemoveAll(specDir)
+		})
+
+		It("should return the builtin spec of the name", func() {
+			loaded, err := LoadSettingsSpec(tasks.Options{}, "ruby")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded.Agent).To(Equal("Ruby"))
+		})
+		It("should prefer the spec of the specDir option", func() {
+			loaded, err := LoadSettingsSpec(tasks.Options{Options: map[string]string{"specDir": specDir}}, "ruby")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded.Agent).To(Equal("Ruby from specDir"))
+		})
+		It("should use the builtin spec when the specDir option has none of the name", func() {
+			loaded, err := LoadSettingsSpec(tasks.Options{Options: map[string]string{"specDir": specDir}}, "php")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded.Agent).To(Equal("PHP"))
+		})
+		It("should return an error for an unknown name", func() {
+			_, err := LoadSettingsSpec(tasks.Options{}, "cobol")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Flatten()", func() {
+		It("should join nested keys and keep lists as one setting", func() {
+			blob, err := ParseYaml(strings.NewReader("common:\n  app_name: [one, two]\n  transaction_tracer:\n    enabled: true\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Flatten(blob)).To(Equal(map[string]interface{}{
+				"common.app_name":                   []interface{}{"one", "two"},
+				"common.transaction_tracer.enabled": true,
+			}))
+		})
+		It("should skip the object placeholders of newrelic.js", func() {
+			file, err := os.Open("../../fixtures/node/newrelic.js")
+			Expect(err).NotTo(HaveOccurred())
+			defer file.Close()
+			blob, err := parseJs(file)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Flatten(blob)).To(Equal(map[string]interface{}{
+				"app_name":         []interface{}{"My Node App"},
+				"license_key":      "license-key-val-node",
+				"logging.level":    "info",
+				"logging.filepath": "temp.log",
+			}))
+		})
+	})
+
+	Describe("Validate()", func() {
+		DescribeTable("should report the expected problem",
+			func(key string, value interface{}, expected SettingStatus) {
+				problems := spec.Validate(map[string]interface{}{key: value})
+				if expected == "" {
+					Expect(problems).To(BeEmpty())
+					return
+				}
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].Key).To(Equal(key))
+				Expect(problems[0].Status).To(Equal(expected))
+			},
+			Entry("for a valid enum value in any case", "log_level", "INFO", SettingStatus("")),
+			Entry("for an invalid enum value", "transaction_tracer.record_sql", "obfuscate", SettingInvalid),
+			Entry("for an integer read as a string", "port", "8080", SettingStatus("")),
+			Entry("for an invalid integer", "port", "80a", SettingInvalid),
+			Entry("for an invalid float", "apdex_t", "fast", SettingInvalid),
+			Entry("for a duration in seconds", "harvest_interval", 60, SettingStatus("")),
+			Entry("for a duration with a unit", "harvest_interval", "1m30s", SettingStatus("")),
+			Entry("for an invalid duration", "harvest_interval", "1 minute", SettingInvalid),
+			Entry("for an ini style boolean", "transaction_tracer.enabled", "on", SettingStatus("")),
+			Entry("for an invalid boolean", "transaction_tracer.enabled", "enabled", SettingInvalid),
+			Entry("for a setting in an environment section", "production.log_level", "verbose", SettingInvalid),
+			Entry("for a key below a map", "labels.team", "api", SettingStatus("")),
+			Entry("for a list below a map", "labels.team", []interface{}{"api"}, SettingInvalid),
+			Entry("for a list below an object", "include_matching_metrics.process.name", []interface{}{"regex \"^java\""}, SettingStatus("")),
+			Entry("for an unknown key", "log_levl", "info", SettingUnknown),
+			Entry("for a spread of newrelic.js", "...require('./defaults')", tasks.DynamicValue("...require('./defaults')"), SettingStatus("")),
+			Entry("for a computed key of newrelic.js", "logging.[process.env.KEY]", tasks.DynamicValue("'info'"), SettingStatus("")),
+			Entry("for a deprecated key", "capture_params", true, SettingDeprecated),
+			Entry("for a removed key", "ssl", true, SettingRemoved),
+		)
+
+		It("should report the settings a section inherits from the defaults once", func() {
+			problems := spec.Validate(map[string]interface{}{
+				"common.ssl":     true,
+				"production.ssl": true,
+			})
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].Key).To(Equal("common.ssl"))
+		})
+
+		It("should only check the keys with the spec prefix", func() {
+			spec.Prefix = "newrelic."
+			problems := spec.Validate(map[string]interface{}{
+				"extension":          "newrelic.so",
+				"newrelic.log_level": "verbose",
+			})
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].Key).To(Equal("newrelic.log_level"))
+		})
+	})
+
+	Describe("Shadowed()", func() {
+		It("should report the settings overridden by an environment variable", func() {
+			spec.EnvPrefix = "NRIA_"
+			problems := spec.Shadowed(map[string]interface{}{
+				"common.log_level":                  "info",
+				"common.transaction_tracer.enabled": true,
+			}, map[string]string{"NRIA_TRANSACTION_TRACER_ENABLED": "false", "LOG_LEVEL": "debug"})
+			Expect(problems).To(Equal([]SettingProblem{
+				{Key: "common.transaction_tracer.enabled", Value: true, Status: SettingShadowed, Message: "is overridden by NRIA_TRANSACTION_TRACER_ENABLED=false"},
+			}))
+		})
+		It("should report the keys below a map once, under the map", func() {
+			spec.EnvPrefix = "NRIA_"
+			problems := spec.Shadowed(map[string]interface{}{
+				"labels.team": "api",
+				"labels.env":  "prod",
+			}, map[string]string{"NRIA_LABELS": "team:web", "NRIA_LABELS_TEAM": "web"})
+			Expect(problems).To(Equal([]SettingProblem{
+				{Key: "labels", Value: map[string]interface{}{"team": "api", "env": "prod"}, Status: SettingShadowed, Message: "is overridden by NRIA_LABELS=team:web"},
+			}))
+		})
+		It("should report nothing for agents without environment variables", func() {
+			Expect(spec.Shadowed(map[string]interface{}{"log_level": "info"}, map[string]string{"LOG_LEVEL": "debug"})).To(BeEmpty())
+		})
+	})
+
+	Describe("ValidateAgentSettings()", func() {
+		parsed := func(name string, content string) ValidateElement {
+			blob, err := ParseYaml(strings.NewReader(content))
+			Expect(err).NotTo(HaveOccurred())
+			return ValidateElement{Config: ConfigElement{FileName: name, FilePath: "/app/config/"}, ParsedResult: blob}
+		}
+
+		It("should return Success when every setting matches the spec", func() {
+			result := ValidateAgentSettings(spec, []ValidateElement{parsed("newrelic.yml", "common:\n  log_level: info\n")})
+			Expect(result.Status).To(Equal(tasks.Success))
+		})
+		It("should return Info for deprecated or unknown settings", func() {
+			result := ValidateAgentSettings(spec, []ValidateElement{parsed("newrelic.yml", "common:\n  capture_params: true\n")})
+			Expect(result.Status).To(Equal(tasks.Info))
+			Expect(result.URL).To(Equal(spec.Docs))
+		})
+		It("should return Warning for invalid values, once per file", func() {
+			element := parsed("newrelic.yml", "common:\n  log_level: verbose\n")
+			result := ValidateAgentSettings(spec, []ValidateElement{element, element})
+			Expect(result.Status).To(Equal(tasks.Warning))
+			Expect(result.Payload).To(Equal([]SettingsValidation{{
+				File:     "/app/config/newrelic.yml",
+				Problems: []SettingProblem{{Key: "common.log_level", Value: "verbose", Status: SettingInvalid, Message: "should be one of error, warn, info, debug"}},
+			}}))
+			Expect(result.Fixes).To(BeEmpty())
+		})
+		It("should suggest a fix for a misspelled enum value", func() {
+			result := ValidateAgentSettings(spec, []ValidateElement{parsed("newrelic.yml", "common:\n  log_level: debg\n")})
+			Expect(result.Status).To(Equal(tasks.Warning))
+			Expect(result.Fixes).To(Equal([]tasks.ConfigFix{{
+				File:        "/app/config/newrelic.yml",
+				Description: "Change common.log_level from debg to debug",
+				Key:         "common.log_level",
+				Value:       "debg",
+				NewValue:    "debug",
+			}}))
+		})
+		It("should return Warning for conflicts found by the agent checks", func() {
+			conflict := func(settings map[string]interface{}) []SettingProblem {
+				return []SettingProblem{{Key: "common.app_name", Status: SettingConflict, Message: "conflicts"}}
+			}
+			result := ValidateAgentSettings(spec, []ValidateElement{parsed("newrelic.yml", "common:\n  log_level: info\n")}, conflict)
+			Expect(result.Status).To(Equal(tasks.Warning))
+		})
+		It("should return None when no config file was parsed", func() {
+			result := ValidateAgentSettings(spec, []ValidateElement{{Config: ConfigElement{FileName: "newrelic.yml"}}})
+			Expect(result.Status).To(Equal(tasks.None))
+		})
+	})
+})
@@ -7,12 +7,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
 	"strconv"
-	"strings"
 
 	"github.com/clbanning/mxj"
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
@@ -32,6 +30,7 @@ type ValidateElement struct {
 	Status       tasks.Status
 	ParsedResult tasks.ValidateBlob
 	Error        string
+	Suggestions  []tasks.KeySuggestion
 }
 
 var (
@@ -45,12 +44,14 @@ var (
 func (el ValidateElement) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		ConfigElement
-		Status tasks.Status
-		Error  string
+		Status      tasks.Status
+		Error       string
+		Suggestions []tasks.KeySuggestion `json:",omitempty"`
 	}{
 		ConfigElement: el.Config,
 		Status:        el.Status,
 		Error:         el.Error,
+		Suggestions:   el.Suggestions,
 	})
 }
 
@@ -99,12 +100,16 @@ func (p BaseConfigValidate) Execute(options tasks.Options, results map[string]ta
 	}
 
 	validatedResults := []ValidateElement{}
+	var fixes []tasks.ConfigFix
+	known := knownConfigKeys(options)
 	for _, config := range configs {
 		processedConfig, err := processConfig(config)
 		if err != nil {
 			log.Debugf("%s - %s", config.FileName, err.Error())
 			continue
 		}
+		processedConfig.Suggestions = suggestConfigKeys(processedConfig, known)
+		fixes = append(fixes, keySuggestionFixes(processedConfig)...)
 		validatedResults = append(validatedResults, processedConfig)
 	}
 
@@ -124,6 +129,18 @@ func (p BaseConfigValidate) Execute(options tasks.Options, results map[string]ta
 		}
 	}
 
+	suggestions := summarizeKeySuggestions(validatedResults)
+
+	if successCounter > 0 && failureCounter == 0 && suggestions != "" {
+		return tasks.Result{
+			Status:  tasks.Warning,
+			Summary: "Successfully parsed config file(s), but found settings the agent won't read:\n" + suggestions,
+			URL:     "https://docs.newrelic.com/docs/agents/manage-apm-agents/configuration/configure-agent",
+			Payload: validatedResults,
+			Fixes:   fixes,
+		}
+	}
+
 	if successCounter > 0 && failureCounter == 0 {
 		return tasks.Result{
 			Summary: "Successfully parsed config file(s) - See json for full detail",
@@ -143,9 +160,10 @@ func (p BaseConfigValidate) Execute(options tasks.Options, results map[string]ta
 	log.Debug("Recorded ", successCounter, "Successful files parsed and ", failureCounter, "failures to parse config files")
 	return tasks.Result{
 		Status:  tasks.Warning,
-		Summary: fmt.Sprintf("We were able to parse %d of %d configuration file(s).\nErrors parsing the following configuration file(s):%s", successCounter, (successCounter + failureCounter), parsingErrors),
+		Summary: fmt.Sprintf("We were able to parse %d of %d configuration file(s).\nErrors parsing the following configuration file(s):%s", successCounter, (successCounter + failureCounter), parsingErrors) + suggestionsSummary(suggestions),
 		URL:     "https://docs.newrelic.com/docs/new-relic-diagnostics#run-diagnostics",
 		Payload: validatedResults,
+		Fixes:   fixes,
 	}
 }
 
@@ -154,8 +172,7 @@ func processConfig(config ConfigElement) (ValidateElement, error) {
 	log.Debug("Validating " + file)
 
 	//Read file
-	content, err := os.Open(file)
-	defer content.Close()
+	content, err := tasks.FS.Open(tasks.RootPath(file))
 	if err != nil {
 		log.Debug("error reading file", err)
 		return ValidateElement{
@@ -164,6 +181,7 @@ func processConfig(config ConfigElement) (ValidateElement, error) {
 			Error:  err.Error(),
 		}, nil
 	}
+	defer content.Close()
 	// initialize variables for data
 	var parsedConfig tasks.ValidateBlob
 
@@ -185,7 +203,7 @@ func processConfig(config ConfigElement) (ValidateElement, error) {
 
 	case ".js":
 		log.Debug(".js file found, validating")
-		parsedConfig, _ = parseJs(content)
+		parsedConfig, err = parseJs(content)
 
 	case ".ini", ".properties", ".cfg":
 		log.Debug(".ini file found, validating")
@@ -284,142 +302,6 @@ func ParseJSONarray(reader io.Reader) (result []tasks.ValidateBlob, err error) {
 	return validateBlobs, nil
 }
 
-func parseJs(reader io.Reader) (result tasks.ValidateBlob, err error) {
-	tempMap := make(map[string]interface{})
-	//So this function basically reads the newrelic.js and re-constructs the exported json config from the `exports.config=` beyond
-
-	var jsonString []string
-	var isComment bool
-
-	scanner := bufio.NewScanner(reader)
-	scanner.Split(bufio.ScanLines)
-	startComment, _ := regexp.Compile("^[/][*].*$")         // looks for /* at the beginning of lines in the config file to remove lines with comments
-	endComment, _ := regexp.Compile("^.*[*][/]$")           // looks for */ at the end of lines to assist in removal of comments
-	oneLineComment, _ := regexp.Compile("^[/][*].*[*][/]$") // looks for single line comments /* like this */
-	slashComment, _ := regexp.Compile("^[/][/].*$")         // this looks for comments // like this
-
-	for scanner.Scan() {
-		scannerText := strings.TrimSpace(scanner.Text()) // trim leading and trailing whitespace
-		if oneLineComment.MatchString(scannerText) || slashComment.MatchString(scannerText) {
-			// check for one liners first since startComment and endComment both match one liners as well and will mess up the logic below
-			continue
-		}
-		if startComment.MatchString(scannerText) {
-			isComment = true // starts a comment block this and the following lines are ignored until we see */
-			continue
-		}
-		if endComment.MatchString(scannerText) {
-			isComment = false // ends the comment block
-			continue
-		}
-
-		if !isComment {
-			if strings.Contains(scannerText, "use strict") {
-				continue
-			}
-
-			if strings.Contains(scannerText, "=") {
-				t := strings.Split(scannerText, "=")
-				jsonString = append(jsonString, strings.TrimSpace(t[1]))
-				continue
-
-			}
-
-			jsonString = append(jsonString, scannerText)
-
-		}
-	}
-
-	location := ""
-	arrayLocation := ""
-	var buildarray []string
-
-	for lineNum, keyValue := range jsonString {
-		log.Debug("keyvalue", keyValue)
-		switch keyValue {
-		case "{": //do nothing here
-		case "}", "},":
-			log.Debug("location is", location)
-			locationSplit := strings.Split(location, ".")
-			location = locationSplit[len(locationSplit)-1]
-
-		case "]", "],":
-			log.Debug("done with array, adding ", buildarray)
-
-			tempMap[arrayLocation] = buildarray
-			arrayLocation = ""
-			buildarray = nil
-
-		case "};":
-			break // end of config, just break the switch
-
-		case "":
-
-		default:
-			if arrayLocation != "" {
-				log.Debug("creating multi-line array", strings.Replace(keyValue, ",", "", 1))
-				buildarray = append(buildarray, strings.Replace(keyValue, ",", "", 1))
-				continue // we are in an array, go to next line
-			}
-
-			keyMap := strings.SplitN(keyValue, ":", 2)
-			if len(keyMap) != 2 {
-				log.Debug("Couldn't parse line", lineNum)
-				break
-			}
-
-			log.Debug("adding: ", location+keyMap[0], " : ", strings.Replace(keyMap[1], ",", "", 1))
-			// check for inline array value
-			if string(strings.TrimSpace(keyMap[1])[0]) == "[" {
-				log.Debug("array detected")
-				//strip [] from string
-				removeBrackets := regexp.MustCompile(`\[(.*)\]`)
-				tempString := removeBrackets.FindStringSubmatch(keyMap[1])
-				if len(tempString) != 2 {
-					log.Debug("multi-line array detected", location+keyMap[0])
-					if strings.TrimSpace(keyMap[1]) == "[" {
-						arrayLocation = location + keyMap[0]
-					}
-					continue //move to the next line
-				}
-				// split on commas
-				tempSlice := strings.Split(tempString[1], ",")
-
-				//now loop through the slice to clean up whitespace and single quotes
-				var finalSlice []string
-				for _, value := range tempSlice {
-					finalSlice = append(finalSlice, sanitizeValue(value))
-				}
-				tempMap[location+keyMap[0]] = finalSlice
-
-			} else {
-				tempMap[location+keyMap[0]] = sanitizeValue(keyMap[1])
-
-			}
-
-			if strings.TrimSpace(keyMap[1]) == "{" {
-				log.Debug("creating adding location", keyMap[0])
-				location = keyMap[0] + "." + location
-			}
-
-		}
-	}
-	//log.Dump(tempMap)
-	return convertToValidateBlob(tempMap), nil
-}
-
-// "  'license-key-val-node',"  ->  "license-key-val-node"
-func sanitizeValue(src string) string {
-	//Remove whitespace
-	whitespaceTrimmed := strings.TrimSpace(src)
-
-	//Remove comma at end of line
-	commaTrimmed := strings.Replace(whitespaceTrimmed, ",", "", 1)
-
-	//Remove single/double quotes
-	return trimQuotes(commaTrimmed)
-}
-
 func trimQuotes(src string) string {
 	if quoted.Match([]byte(src)) {
 		return quoted.ReplaceAllString(src, "$1")
@@ -57,6 +57,11 @@ func (t BaseConfigValidateHSM) Explain() string {
 	return "Validate High Security Mode agent configuration against account configuration"
 }
 
+// RequiresNetwork - This task gets the High Security Mode setting of the account from New Relic, so it is skipped with -offline
+func (t BaseConfigValidateHSM) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - Returns the dependencies for each task.
 func (t BaseConfigValidateHSM) Dependencies() []string {
 	return []string{
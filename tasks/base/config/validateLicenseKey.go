@@ -54,6 +54,7 @@ func (p BaseConfigValidateLicenseKey) Execute(options tasks.Options, upstream ma
 	//we'll start by validating license key format and collecting the proper result summary based on our findings
 	validatedLKCounter := 0
 	validFormatLKToSources := make(map[string][]string)
+	var fixes []tasks.ConfigFix
 	var successSummary, warningSummary, failureSummary string
 
 	//For all cases, except Python, env vars will override config settings. If we find env vars let's skip validating config files because they will contain either empty strings or unmodified sample license keys that will fail validation.
@@ -75,6 +76,10 @@ func (p BaseConfigValidateLicenseKey) Execute(options tasks.Options, upstream ma
 			}
 		}
 	} else {
+		fixes = quotedLicenseKeyFixes(licenseKeys)
+		for _, fix := range fixes {
+			warningSummary += fmt.Sprintf("The license key found in %s is wrapped in quotes or contains spaces, which some agents keep as part of the key. A fix removing them was written to the nrdiag-fixes directory, run with -apply-fixes to apply it.\n\n", fix.File)
+		}
 		uniqueLKToSources := dedupeLicenseKeys(licenseKeys) //we should expect some repeats because when customers are using multiple NR products, most probably they will use the same license key for each product's config file.
 		for lk, sources := range uniqueLKToSources {
 			isConfigFormatValid, errMsg := checkConfigFormat(lk, sources)
@@ -89,7 +94,13 @@ func (p BaseConfigValidateLicenseKey) Execute(options tasks.Options, upstream ma
 
 	//Only if we have collected a license key with a valid format, then we can move into checking that the customer's account agrees that this is a valid key
 	var resultsPayload map[string][]string
-	if len(validFormatLKToSources) > 0 {
+	if len(validFormatLKToSources) > 0 && options.Options["Offline"] == "true" {
+		// the format checks and fixes don't need the network, only the account lookup is skipped
+		for lk, sources := range validFormatLKToSources {
+			successSummary += fmt.Sprintf("The license key found in %s has a valid New Relic format: %s\nIt was not validated against your account since -offline was used.\n", strings.Join(sources, ",\n "), lk)
+		}
+		resultsPayload = validFormatLKToSources
+	} else if len(validFormatLKToSources) > 0 {
 		validAccountLKToSources, invalidAccountLKToSources, err := p.validateAgainstAccount(validFormatLKToSources)
 
 		if err != nil {
@@ -124,7 +135,27 @@ func (p BaseConfigValidateLicenseKey) Execute(options tasks.Options, upstream ma
 		Status:  resultStatus,
 		Summary: fmt.Sprintf("We validated %s license key(s):\n"+successSummary+failureSummary+warningSummary, strconv.Itoa(validatedLKCounter)),
 		Payload: resultsPayload,
+		Fixes:   fixes,
+	}
+}
+
+// quotedLicenseKeyFixes - suggests removing the quotes and spaces around license keys read from config files, when
+// the key is valid without them
+func quotedLicenseKeyFixes(licenseKeys []LicenseKey) []tasks.ConfigFix {
+	var fixes []tasks.ConfigFix
+	for _, lk := range licenseKeys {
+		if lk.Key == "" || !licenseKeyUsingQuotes(lk.Value) || !isFormatValid(sanitizeLicenseKey(lk.Value)) {
+			continue
+		}
+		fixes = append(fixes, tasks.ConfigFix{
+			File:        lk.Source,
+			Description: "Remove the quotes and spaces inside the " + lk.Key + " value",
+			Key:         lk.Key,
+			Value:       lk.Value,
+			NewValue:    sanitizeLicenseKey(lk.Value),
+		})
 	}
+	return fixes
 }
 
 func findLKFromEnvVarSources(licenseKeys []LicenseKey) []LicenseKey {
@@ -93,6 +93,39 @@ var _ = Describe("BaseConfigValidateLicenseKey", func() {
 			})
 		})
 
+		Context("when 1 license key is found wrapped in quotes inside the value of a config setting", func() {
+			BeforeEach(func() {
+				options = tasks.Options{}
+				upstream = map[string]tasks.Result{
+					"Base/Config/LicenseKey": tasks.Result{
+						Status: tasks.Success,
+						Payload: []LicenseKey{
+							LicenseKey{
+								Value:  `'08a2ad66c637a29c3982469a3fe8d1982d00NRAL'`,
+								Source: "/app/config/newrelic.yml",
+								Key:    "license_key",
+							},
+						},
+					},
+				}
+				p.validateAgainstAccount = func(lkToSources map[string][]string) (map[string][]string, map[string][]string, error) {
+					return lkToSources, map[string][]string{}, nil
+				}
+			})
+
+			It("Should return a Warning status with a fix removing the quotes", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Summary).To(ContainSubstring("The license key found in /app/config/newrelic.yml is wrapped in quotes or contains spaces"))
+				Expect(result.Fixes).To(Equal([]tasks.ConfigFix{{
+					File:        "/app/config/newrelic.yml",
+					Description: "Remove the quotes and spaces inside the license_key value",
+					Key:         "license_key",
+					Value:       `'08a2ad66c637a29c3982469a3fe8d1982d00NRAL'`,
+					NewValue:    "08a2ad66c637a29c3982469a3fe8d1982d00NRAL",
+				}}))
+			})
+		})
+
 		Context("when 1 license key is found and is set in the python config file and is valid but we ran into an error when checking against account", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
@@ -120,6 +153,33 @@ var _ = Describe("BaseConfigValidateLicenseKey", func() {
 			})
 		})
 
+		Context("when 1 license key with a valid format is found and -offline was used", func() {
+			BeforeEach(func() {
+				options = tasks.Options{Options: map[string]string{"Offline": "true"}}
+				upstream = map[string]tasks.Result{
+					"Base/Config/LicenseKey": tasks.Result{
+						Status: tasks.Success,
+						Payload: []LicenseKey{
+							LicenseKey{
+								Value:  "08a2ad66c637a29c3982469a3fe8d1982d00NRAL",
+								Source: "/app/myappname/newrelic.ini",
+							},
+						},
+					},
+				}
+				p.validateAgainstAccount = func(map[string][]string) (map[string][]string, map[string][]string, error) {
+					Fail("the account lookup needs the network")
+					return nil, nil, nil
+				}
+			})
+
+			It("Should return a Success status without checking the account", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+				Expect(result.Summary).To(Equal("We validated 1 license key(s):\nThe license key found in /app/myappname/newrelic.ini has a valid New Relic format: 08a2ad66c637a29c3982469a3fe8d1982d00NRAL\nIt was not validated against your account since -offline was used.\n"))
+				Expect(result.Payload).To(Equal(map[string][]string{"08a2ad66c637a29c3982469a3fe8d1982d00NRAL": {"/app/myappname/newrelic.ini"}}))
+			})
+		})
+
 		Context("when 2 license keys are found and is set in the dotnet/infra config file, it has valid format, but is invalid when checking against account(the account's owner rotated keys and the one being used is an old one)", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
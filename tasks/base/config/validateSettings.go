@@ -0,0 +1,61 @@
+package config
+
+import (
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// ValidateSettings - checks an agent's config settings against a spec of their types and allowed values. The agent
+// packages register one each with NewValidateSettingsTask
+type ValidateSettings struct {
+	identifier tasks.Identifier
+	configTask string
+	specName   string
+}
+
+// NewValidateSettingsTask - creates the <Agent>/Config/ValidateSettings task validating the config files returned as
+// []ValidateElement by configTask against the spec named specName, see LoadSettingsSpec
+func NewValidateSettingsTask(identifier string, configTask string, specName string) ValidateSettings {
+	return ValidateSettings{
+		identifier: tasks.IdentifierFromString(identifier),
+		configTask: configTask,
+		specName:   specName,
+	}
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p ValidateSettings) Identifier() tasks.Identifier {
+	return p.identifier
+}
+
+// Explain - Returns the help text for each individual task
+func (p ValidateSettings) Explain() string {
+	return "Validate the types and values of " + p.identifier.Category + " agent config settings, and flag unknown or deprecated ones"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p ValidateSettings) Dependencies() []string {
+	return []string{
+		p.configTask,
+	}
+}
+
+// Execute - The core work within each task
+func (p ValidateSettings) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	agent := p.identifier.Category
+	configs, ok := upstream[p.configTask].Payload.([]ValidateElement)
+	if !ok || len(configs) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No " + agent + " agent config files found",
+		}
+	}
+
+	spec, err := LoadSettingsSpec(options, p.specName)
+	if err != nil {
+		return tasks.Result{
+			Status:  tasks.Error,
+			Summary: "Unable to load the " + agent + " agent settings spec: " + err.Error(),
+		}
+	}
+	return ValidateAgentSettings(spec, configs)
+}
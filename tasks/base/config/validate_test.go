@@ -39,6 +39,16 @@ func init() {
 	flag.BoolVar(&updateGoldenFiles, "updateGoldenFiles", false, "updateGoldenFiles is used trigger a package level update of golden files")
 }
 
+var suggestionsSpec = `
+files: [newrelic.yml]
+sections: [common, development, test, staging, production]
+settings:
+  license_key: {type: String}
+  app_name: {type: String}
+  log_level: {type: String}
+  transaction_tracer.record_sql: {type: String}
+`
+
 var _ = Describe("Base/Config/Validate", func() {
 	var p BaseConfigValidate
 	Describe("Execute()", func() {
@@ -128,6 +138,73 @@ var _ = Describe("Base/Config/Validate", func() {
 				Expect(fmt.Sprintf("#%v", result.Payload)).To(Equal(expectedPayload))
 			})
 		})
+		Context("When a parsed file has misspelled or misplaced keys", func() {
+			// the suggestions come from the specs the agent packages register
+			defer useSettingsSpecs(suggestionsSpec)()
+			upstream := map[string]tasks.Result{
+				"Base/Config/Collect": tasks.Result{
+					Status: tasks.Success,
+					Payload: []ConfigElement{
+						ConfigElement{
+							FileName: "newrelic.yml",
+							FilePath: "fixtures/suggestions/",
+						},
+					},
+				},
+			}
+			options := tasks.Options{}
+			result := p.Execute(options, upstream)
+			It("Should return a warning suggesting the intended keys", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Summary).To(Equal("Successfully parsed config file(s), but found settings the agent won't read:\n" +
+					"fixtures/suggestions/newrelic.yml:\n" +
+					"\t'common.licence_key' is not a known setting, did you mean 'common.license_key'?\n" +
+					"\t'common.transaction_tracer.record_sq' is not a known setting, did you mean 'common.transaction_tracer.record_sql'?\n" +
+					"\t'log_level' is outside of the common section, so the agent doesn't read it, did you mean 'common.log_level'?"))
+				Expect(result.Payload).To(HaveLen(1))
+			})
+			It("Should suggest fixes renaming the misspelled keys only", func() {
+				Expect(result.Fixes).To(Equal([]tasks.ConfigFix{
+					{
+						File:        "fixtures/suggestions/newrelic.yml",
+						Description: "Rename common.licence_key to common.license_key",
+						Key:         "common.licence_key",
+						Value:       "abc123",
+						NewKey:      "common.license_key",
+					},
+					{
+						File:        "fixtures/suggestions/newrelic.yml",
+						Description: "Rename common.transaction_tracer.record_sq to common.transaction_tracer.record_sql",
+						Key:         "common.transaction_tracer.record_sq",
+						Value:       "obfuscated",
+						NewKey:      "common.transaction_tracer.record_sql",
+					},
+				}))
+			})
+		})
+		Context("When a spec file is given for the key suggestions", func() {
+			defer useSettingsSpecs(suggestionsSpec)()
+			upstream := map[string]tasks.Result{
+				"Base/Config/Collect": tasks.Result{
+					Status: tasks.Success,
+					Payload: []ConfigElement{
+						ConfigElement{
+							FileName: "newrelic.yml",
+							FilePath: "fixtures/suggestions/",
+						},
+					},
+				},
+			}
+			options := tasks.Options{Options: map[string]string{"specFile": "fixtures/suggestions/spec.yml"}}
+			result := p.Execute(options, upstream)
+			It("Should suggest the keys of the spec file instead of the builtin spec", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Summary).To(Equal("Successfully parsed config file(s), but found settings the agent won't read:\n" +
+					"fixtures/suggestions/newrelic.yml:\n" +
+					"\t'common.licence_key' is not a known setting, did you mean 'common.license_key'?\n" +
+					"\t'log_level' is outside of the common section, so the agent doesn't read it, did you mean 'common.log_level'?"))
+			})
+		})
 		Context("When parsing two files, one with errors", func() {
 			upstream := map[string]tasks.Result{
 				"Base/Config/Collect": tasks.Result{
@@ -335,8 +412,11 @@ var _ = Describe("Base/Config/Validate", func() {
 		Context("When parsing invalid js config", func() {
 			file, _ := os.Open("fixtures/validate_testdata_js_comment.js")
 			defer file.Close()
-			result, _ := parseJs(file)
-			It("Should return special character key in ValidateBlob", func() {
+			result, err := parseJs(file)
+			It("Should report the line of the invalid key", func() {
+				Expect(err).To(MatchError("line 24: unexpected '*' where a key was expected"))
+			})
+			It("Should return the settings around the invalid key", func() {
 				goldenFile := goldenFileName(CurrentGinkgoTestDescription().TestText)
 
 				if updateGoldenFiles {
@@ -37,10 +37,18 @@ func (t BaseEnvCollectEnvVars) Execute(options tasks.Options, upstream map[strin
 	}
 
 	filteredEnvVars := envVars.WithDefaultFilter()
+	result.Summary = "Gathered Environment variables of current shell."
+
+	// the processes selected with -pid were started with their own environment, which takes precedence
+	if len(tasks.TargetPids()) > 0 {
+		for key, value := range tasks.GetTargetProcessEnvVars().WithDefaultFilter() {
+			filteredEnvVars[key] = value
+		}
+		result.Summary = "Gathered Environment variables of current shell and of the processes selected with -pid."
+	}
 
 	result.Payload = filteredEnvVars
 	result.Status = tasks.Info
-	result.Summary = "Gathered Environment variables of current shell."
 
 	return result
 }
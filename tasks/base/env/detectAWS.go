@@ -20,6 +20,11 @@ func (p BaseEnvDetectAWS) Explain() string {
 	return "Detect if running in AWS environment"
 }
 
+// RequiresNetwork - This task queries the AWS metadata endpoint, so it is skipped with -offline
+func (p BaseEnvDetectAWS) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - Returns the dependencies for each task.
 func (p BaseEnvDetectAWS) Dependencies() []string {
 	return []string{}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/config"
@@ -117,7 +116,7 @@ func (p BaseLogCollect) Execute(options tasks.Options, upstream map[string]tasks
 }
 
 func prunedReader(path string) (c chan string, err error) {
-	file, err := os.Open(path)
+	file, err := tasks.FS.Open(tasks.RootPath(path))
 
 	if err != nil {
 		return nil, err
@@ -129,7 +128,7 @@ func prunedReader(path string) (c chan string, err error) {
 	return logChannel, nil
 }
 
-func pruneLog(file *os.File, logChannel chan string) {
+func pruneLog(file tasks.File, logChannel chan string) {
 	defer file.Close()
 
 	// Start reading from the file with a reader.
@@ -141,10 +140,13 @@ func pruneLog(file *os.File, logChannel chan string) {
 	for {
 		line, err = reader.ReadString('\n')
 
+		// the last line of a log that is still being written has no newline yet, keep it so the copy isn't cut short
+		if line != "" {
+			logChannel <- line
+		}
 		if err != nil {
 			break
 		}
-		logChannel <- line
 	}
 
 	if err != io.EOF {
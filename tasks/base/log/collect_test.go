@@ -0,0 +1,27 @@
+package log
+
+import (
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Base/Log/Collect", func() {
+	Describe("pruneLog()", func() {
+		It("should keep a last line without a newline", func() {
+			fs := tasks.NewMemFS(map[string]string{"/app/newrelic_agent.log": "first\nsecond"})
+			file, err := fs.Open("/app/newrelic_agent.log")
+			Expect(err).NotTo(HaveOccurred())
+
+			logChannel := make(chan string)
+			go pruneLog(file, logChannel)
+			var lines []string
+			for line := range logChannel {
+				lines = append(lines, line)
+			}
+			Expect(strings.Join(lines, "")).To(Equal("first\nsecond"))
+		})
+	})
+})
@@ -3,7 +3,6 @@ package log
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -266,7 +265,7 @@ func getLastModifiedDate(options tasks.Options) time.Time {
 }
 
 func isLogFileRecent(inputFilePath string, minimumModTime time.Time) bool {
-	fileInfo, err := os.Stat(inputFilePath)
+	fileInfo, err := tasks.FS.Stat(tasks.RootPath(inputFilePath))
 	if err != nil {
 		log.Debug("Error reading file", inputFilePath)
 		return true
@@ -279,7 +278,7 @@ func selectMostRecentOldLogs(logFilePaths []string) string {
 	var logFilePathSelected string
 
 	for index, logFilePath := range logFilePaths {
-		fileInfo, err := os.Stat(logFilePath)
+		fileInfo, err := tasks.FS.Stat(tasks.RootPath(logFilePath))
 		whenFileWasModified := fileInfo.ModTime()
 		if err != nil {
 			log.Debug("Error reading file", logFilePath)
@@ -2,7 +2,6 @@ package log
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -94,11 +93,9 @@ var (
 
 func collectFilePaths(envVars map[string]string, configElements []baseConfig.ValidateElement, foundSysProps map[string]string, options tasks.Options) []LogElement {
 	var paths []string
-	currentPath, err := os.Getwd()
-	if err != nil {
-		log.Info("Error reading local working directory")
-	}
-	paths = append(paths, currentPath)
+	// the current directory, or the working directories of the processes selected with -pid
+	discoveryDirs := tasks.GetDiscoveryDirectories()
+	paths = append(paths, discoveryDirs...)
 
 	if runtime.GOOS == "windows" {
 		sysProgramFiles := envVars["ProgramFiles"]
@@ -195,9 +192,11 @@ func collectFilePaths(envVars map[string]string, configElements []baseConfig.Val
 
 	//collect a full log path by putting together a filename and directory path that come from different sources, such as a dir path that comes from a system prop (Dnewrelic.config.log_file_path:path/todir) and filename that comes from a config file setting (log_file_name:somecustomlogname)
 	if len(unmatchedDirKeyToVal) > 0 || len(unmatchedFilenameKeyToVal) > 0 {
-		logElements := getLogPathFromUnmatchedKeys(unmatchedDirKeyToVal, unmatchedFilenameKeyToVal, currentPath, options)
-		if len(logElements) > 0 {
-			logFilesFound = append(logFilesFound, logElements...)
+		for _, currentPath := range discoveryDirs {
+			logElements := getLogPathFromUnmatchedKeys(unmatchedDirKeyToVal, unmatchedFilenameKeyToVal, currentPath, options)
+			if len(logElements) > 0 {
+				logFilesFound = append(logFilesFound, logElements...)
+			}
 		}
 	}
 	//collect paths to New Relic log Files by looking into standard locations
@@ -389,7 +388,7 @@ func getLogPathFromEnvVar(logPath string, logEnvVar string, unmatchedDirKeyToVal
 		return setLogElement(logPath, logPath, logSourceData, false, false, reasonToNotCollect), false
 	}
 	//check if path is a directory path
-	pathInfo, err := os.Stat(logPath)
+	pathInfo, err := tasks.FS.Stat(tasks.RootPath(logPath))
 	if err != nil {
 		//if we got an error it means this is not a path but a filename
 		unmatchedFilenameKeyToVal[logEnvVar] = logPath
@@ -524,12 +523,7 @@ func findLogFiles(patterns []string, dir string) []string {
 
 func getFilesFromDir(dir string) []string {
 	var potentialLogFiles []string
-	f, err := os.Open(dir)
-	if err != nil {
-		log.Debug(err)
-	}
-	files, err := f.Readdir(-1)
-	f.Close()
+	files, err := tasks.FS.ReadDir(tasks.RootPath(dir))
 	if err != nil {
 		log.Debug(err)
 	}
@@ -44,6 +44,11 @@ func (t BrowserAgentGetSource) Explain() string {
 	// ./nrdiag -browser-url http://thecustomers-website-url --suites browser
 }
 
+// RequiresNetwork - This task downloads the page from the browser URL, so it is skipped with -offline
+func (t BrowserAgentGetSource) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - Returns the dependencies for ech task.
 func (t BrowserAgentGetSource) Dependencies() []string {
 	return []string{}
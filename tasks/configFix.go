@@ -0,0 +1,251 @@
+package tasks
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ConfigFix - a mechanical change to one setting of a config file, suggested by a task for an issue that has a single
+// right answer, e.g. the quotes around a license key. Each fix changes the matching lines in place, so comments and
+// the rest of the file are kept as they are.
+type ConfigFix struct {
+	File        string
+	Description string
+	// Key is the setting, nested keys joined with dots, e.g. common.log_level. Lines are matched on the key with
+	// the keys it's nested in, so a setting of the same name elsewhere in the file is left alone
+	Key string
+	// Value is the current value of the setting. Only the lines holding the key with this value are changed
+	Value string
+	// NewValue replaces the value, keeping its quotes
+	NewValue string `json:",omitempty"`
+	// NewKey renames the setting, whatever its value
+	NewKey string `json:",omitempty"`
+}
+
+// configKey matches a key and its separator at the start of a line, or after the { or , of an inline object
+var configKey = regexp.MustCompile(`(?:^\s*|[{,]\s*)(["']?)([A-Za-z0-9_.\-]+)(["']?)(\s*[:=]\s*)`)
+
+// ApplyConfigFixes - returns the content of a config file with the fixes applied, or an error naming a fix whose
+// setting wasn't found
+func ApplyConfigFixes(file string, content []byte, fixes []ConfigFix) ([]byte, error) {
+	newline := "\n"
+	if strings.Contains(string(content), "\r\n") {
+		newline = "\r\n"
+	}
+	lines := strings.Split(string(content), newline)
+	for _, fix := range fixes {
+		changed := false
+		// the keys move when a fix changes the length of a line, so they are found again for each fix
+		for i, keys := range configLineKeys(file, lines) {
+			if fixed, ok := fix.applyToLine(file, lines[i], keys); ok {
+				lines[i] = fixed
+				changed = true
+			}
+		}
+		if !changed {
+			return nil, fmt.Errorf("%s: %s = %s was not found, the file may have changed", file, fix.Key, fix.Value)
+		}
+	}
+	return []byte(strings.Join(lines, newline)), nil
+}
+
+// applyToLine - the line with the fix applied, and false when the line doesn't hold the setting
+func (f ConfigFix) applyToLine(file string, line string, keys []configLineKey) (string, bool) {
+	for _, key := range keys {
+		match := key.match
+		if !f.matchesKey(key) {
+			continue
+		}
+		if f.NewKey != "" {
+			// only the part of the key written on the line is renamed
+			written := line[match[4]:match[5]]
+			parts := strings.Split(f.NewKey, ".")
+			newKey := f.NewKey
+			if n := strings.Count(written, ".") + 1; n < len(parts) {
+				newKey = strings.Join(parts[len(parts)-n:], ".")
+			}
+			return line[:match[4]] + newKey + line[match[5]:], true
+		}
+		inObject := line[match[0]] == '{' || line[match[0]] == ','
+		value := configValue(file, line[match[1]:], inObject)
+		if unquoteConfigValue(value) != f.Value {
+			continue
+		}
+		newValue := f.NewValue
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'' || value[0] == '`') {
+			newValue = value[:1] + newValue + value[:1]
+		}
+		return line[:match[1]] + newValue + line[match[1]+len(value):], true
+	}
+	return "", false
+}
+
+// matchesKey - true when the key on a line is the setting. The ini parser reads the keys without their sections, so
+// an ini key also matches as it's written.
+func (f ConfigFix) matchesKey(key configLineKey) bool {
+	return f.Key == key.path || key.section != "" && f.Key == key.section+"."+key.path
+}
+
+// configLineKey - a key on a line of a config file, and the setting it holds
+type configLineKey struct {
+	// match holds the submatch indexes of configKey
+	match []int
+	// path is the key with its parents, joined with dots, e.g. common.transaction_tracer.record_sql
+	path string
+	// section is the ini section holding the key, e.g. newrelic:staging
+	section string
+}
+
+// configParent - a key holding nested settings, and the indentation of its line in YAML
+type configParent struct {
+	key    string
+	indent int
+}
+
+// configLineKeys - the keys on each line of a config file with their parents: the indentation and inline objects of
+// YAML, the objects of newrelic.js and the sections of ini files
+func configLineKeys(file string, lines []string) [][]configLineKey {
+	ext := strings.ToLower(filepath.Ext(file))
+	isJs := ext == ".js" || ext == ".ts" || ext == ".mjs" || ext == ".cjs"
+	isIni := ext == ".ini" || ext == ".cfg" || ext == ".properties"
+
+	keys := make([][]configLineKey, len(lines))
+	var (
+		parents []configParent
+		section string
+	)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if isIni {
+			if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+				section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+				continue
+			}
+			match := configKey.FindStringSubmatchIndex(line)
+			if match != nil && match[0] == 0 && line[match[2]:match[3]] == line[match[6]:match[7]] {
+				keys[i] = []configLineKey{{match: match, path: line[match[4]:match[5]], section: section}}
+			}
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if !isJs {
+			// a YAML key holds the keys indented below it
+			for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+				parents = parents[:len(parents)-1]
+			}
+		}
+		keys[i], parents = scanConfigLine(file, line, indent, isJs, parents)
+	}
+	return keys
+}
+
+// scanConfigLine - the keys on a YAML or newrelic.js line, and the parents open at its end. Braces open an object
+// holding the keys up to the matching brace, and quoted values and comments are skipped.
+func scanConfigLine(file string, line string, indent int, isJs bool, parents []configParent) ([]configLineKey, []configParent) {
+	var keys []configLineKey
+	matches := configKey.FindAllStringSubmatchIndex(line, -1)
+	pendingKey, pendingAt := "", -1
+	for pos, m := 0, 0; pos < len(line); pos++ {
+		for m < len(matches) && matches[m][2] < pos {
+			m++
+		}
+		if m < len(matches) && matches[m][2] == pos {
+			match := matches[m]
+			m++
+			if line[match[2]:match[3]] == line[match[6]:match[7]] && !(isJs && strings.Contains(line[match[8]:match[9]], "=")) {
+				key := line[match[4]:match[5]]
+				keys = append(keys, configLineKey{match: match, path: configPath(parents, key)})
+				pendingKey, pendingAt = key, match[1]
+				if value := configValue(file, line[match[1]:], false); !isJs && match[0] == 0 && (value == "" || value[0] == '&') {
+					parents = append(parents, configParent{key: key, indent: indent})
+				}
+			}
+			pos = match[1] - 1
+			continue
+		}
+		switch c := line[pos]; {
+		case c == '{':
+			key := ""
+			if pos == pendingAt {
+				key = pendingKey
+			}
+			parents = append(parents, configParent{key: key, indent: indent})
+		case c == '}':
+			if len(parents) > 0 {
+				parents = parents[:len(parents)-1]
+			}
+		case c == '"' || c == '\'' || c == '`':
+			for pos++; pos < len(line) && line[pos] != c; pos++ {
+				if line[pos] == '\\' {
+					pos++
+				}
+			}
+		case isJs && strings.HasPrefix(line[pos:], "//"), !isJs && c == '#' && (pos == 0 || line[pos-1] == ' '):
+			return keys, parents
+		}
+	}
+	return keys, parents
+}
+
+// configPath - the key with the keys of its parents in front of it
+func configPath(parents []configParent, key string) string {
+	var path []string
+	for _, parent := range parents {
+		if parent.key != "" {
+			path = append(path, parent.key)
+		}
+	}
+	return strings.Join(append(path, key), ".")
+}
+
+// configValue - the value at the start of what follows a key, without what comes after it, e.g. a trailing comment
+// or, in newrelic.js and inline YAML objects, the comma before the next property
+func configValue(file string, rest string, inObject bool) string {
+	if rest != "" && (rest[0] == '"' || rest[0] == '\'' || rest[0] == '`') {
+		for i := 1; i < len(rest); i++ {
+			if rest[i] == '\\' {
+				i++
+			} else if rest[i] == rest[0] {
+				return rest[:i+1]
+			}
+		}
+		return rest
+	}
+	var terminators []string
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".js", ".ts", ".mjs", ".cjs":
+		terminators = []string{",", "//", "}"}
+	case ".ini", ".cfg", ".properties":
+		terminators = []string{" ;", " #"}
+	default:
+		terminators = []string{" #"}
+		if inObject {
+			terminators = append(terminators, ",", "}")
+		}
+	}
+	end := len(rest)
+	for _, terminator := range terminators {
+		if i := strings.Index(rest, terminator); i >= 0 && i < end {
+			end = i
+		}
+	}
+	return strings.TrimRight(rest[:end], " \t")
+}
+
+// unquoteConfigValue - the value with one pair of surrounding quotes removed, as the parsers read it
+func unquoteConfigValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'' || value[0] == '`') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// ClosestMatch - returns the candidate within a couple of typos of the value, e.g. a log level, or an empty string
+func ClosestMatch(value string, candidates []string) string {
+	return closestKey(value, candidates)
+}
@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"testing"
+)
+
+func TestApplyConfigFixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		fixes   []ConfigFix
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "value of the setting only, keeping quotes and comments",
+			file:    "/app/config/newrelic.yml",
+			content: "common: &default_settings\n  license_key: \"'abc'\" # the key\n  log_level: info\nproduction:\n  <<: *default_settings\n  license_key: \"'abc'\"\n",
+			fixes:   []ConfigFix{{Key: "common.license_key", Value: "'abc'", NewValue: "abc"}},
+			want:    "common: &default_settings\n  license_key: \"abc\" # the key\n  log_level: info\nproduction:\n  <<: *default_settings\n  license_key: \"'abc'\"\n",
+		},
+		{
+			name:    "only the line with the current value",
+			file:    "newrelic.yml",
+			content: "common:\n  log_level: debg\ndevelopment:\n  log_level: info\n",
+			fixes:   []ConfigFix{{Key: "development.log_level", Value: "debg", NewValue: "debug"}},
+			wantErr: true,
+		},
+		{
+			name:    "nested YAML key, not a key of the same name elsewhere",
+			file:    "newrelic-infra.yml",
+			content: "level: debg\nlogging:\n  # the agent log\n  level: debg\n  file: a.log\nother:\n  level: debg\n",
+			fixes:   []ConfigFix{{Key: "logging.level", Value: "debg", NewValue: "debug"}},
+			want:    "level: debg\nlogging:\n  # the agent log\n  level: debug\n  file: a.log\nother:\n  level: debg\n",
+		},
+		{
+			name:    "YAML key inside an inline object",
+			file:    "newrelic.yml",
+			content: "common:\n  level: debg\n  logging: {level: debg, file: 'a{b}.log'}\n",
+			fixes:   []ConfigFix{{Key: "common.logging.level", Value: "debg", NewValue: "debug"}},
+			want:    "common:\n  level: debg\n  logging: {level: debug, file: 'a{b}.log'}\n",
+		},
+		{
+			name:    "ini value with a trailing comment",
+			file:    "/etc/php/7.4/mods-available/newrelic.ini",
+			content: "[newrelic]\r\nnewrelic.loglevel = infos ; verbose\r\n",
+			fixes:   []ConfigFix{{Key: "newrelic.loglevel", Value: "infos", NewValue: "info"}},
+			want:    "[newrelic]\r\nnewrelic.loglevel = info ; verbose\r\n",
+		},
+		{
+			name:    "ini key within a section",
+			file:    "newrelic.ini",
+			content: "[newrelic]\nlog_level = infos\n\n[newrelic:staging]\nlog_level = infos\n",
+			fixes:   []ConfigFix{{Key: "newrelic:staging.log_level", Value: "infos", NewValue: "info"}},
+			want:    "[newrelic]\nlog_level = infos\n\n[newrelic:staging]\nlog_level = info\n",
+		},
+		{
+			name:    "nested newrelic.js key",
+			file:    "newrelic.js",
+			content: "exports.config = {\n  logging: { level: 'trce', filepath: 'a.log' },\n}\n",
+			fixes:   []ConfigFix{{Key: "logging.level", Value: "trce", NewValue: "trace"}},
+			want:    "exports.config = {\n  logging: { level: 'trace', filepath: 'a.log' },\n}\n",
+		},
+		{
+			name:    "newrelic.js key over several lines, not a key of the same name elsewhere",
+			file:    "newrelic.js",
+			content: "exports.config = {\n  level: 'trce',\n  logging: {\n    // '{'\n    filepath: 'a}.log',\n    level: 'trce'\n  },\n  audit: { level: 'trce' }\n}\n",
+			fixes:   []ConfigFix{{Key: "logging.level", Value: "trce", NewValue: "trace"}},
+			want:    "exports.config = {\n  level: 'trce',\n  logging: {\n    // '{'\n    filepath: 'a}.log',\n    level: 'trace'\n  },\n  audit: { level: 'trce' }\n}\n",
+		},
+		{
+			name:    "renamed key, in its own section only",
+			file:    "newrelic.yml",
+			content: "common:\n  log_levl: info\nproduction:\n  log_levl: debug\n",
+			fixes:   []ConfigFix{{Key: "common.log_levl", Value: "info", NewKey: "common.log_level"}},
+			want:    "common:\n  log_level: info\nproduction:\n  log_levl: debug\n",
+		},
+		{
+			name:    "setting no longer in the file",
+			file:    "newrelic.yml",
+			content: "common:\n  log_level: info\n",
+			fixes:   []ConfigFix{{Key: "common.log_level", Value: "debg", NewValue: "debug"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyConfigFixes(tt.file, []byte(tt.content), tt.fixes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyConfigFixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("ApplyConfigFixes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
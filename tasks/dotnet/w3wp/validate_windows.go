@@ -6,7 +6,6 @@ import (
 	"syscall"
 	"unsafe"
 
-	"github.com/shirou/gopsutil/process"
 	"github.com/shirou/w32"
 	"github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
@@ -55,7 +54,7 @@ func (p DotNetW3wpValidate) Execute(options tasks.Options, upstream map[string]t
 		return result
 	}
 	// get pids from DotNet/W3wp/Collect and make sure they are type []Process
-	w3wpProcesses, ok := upstream["DotNet/W3wp/Collect"].Payload.([]process.Process)
+	w3wpProcesses, ok := upstream["DotNet/W3wp/Collect"].Payload.([]tasks.RunningProcess)
 	if !ok {
 		logger.Debug("The payload from the w3wp collection is not the correct type! This usually means there were no w3wp processes running.")
 		result.Status = tasks.None
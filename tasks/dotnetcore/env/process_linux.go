@@ -1,7 +1,6 @@
 package env
 
 import (
-	"github.com/shirou/gopsutil/process"
 	"github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
@@ -42,7 +41,7 @@ func gatherProcessInfo() (result tasks.Result) {
 	return
 }
 
-func buildProcInfo(p process.Process) (procInfo ProcessArgs) {
+func buildProcInfo(p tasks.RunningProcess) (procInfo ProcessArgs) {
 	// add process info
 	procInfo.Pid = p.Pid
 	logger.Debug("DotNetCoreEnvProcess pid - ", p.Pid)
@@ -2,15 +2,11 @@ package tasks
 
 import (
 	"bufio"
-	"os"
 	"regexp"
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 )
 
-//global var for mocking to ensure test coverage
-var osOpen = os.Open
-
 //FindStringInFileFunc - function signature for FindStringInFile
 type FindStringInFileFunc func(string, string) bool
 
@@ -20,12 +16,13 @@ func FindStringInFile(search string, filepath string) bool {
 	regexKey, _ := regexp.Compile(search)
 	log.Debug("Opening " + filepath + "searching for " + search)
 	//Read file
-	file, err := osOpen(filepath)
+	file, err := FS.Open(RootPath(filepath))
 	if err != nil {
 		log.Debug("error reading file", filepath, err)
 		//result.Status = tasks.Error
 		return false
 	}
+	defer file.Close()
 	scanner := bufio.NewScanner(file)
 	scanner.Split(bufio.ScanLines)
 	for scanner.Scan() {
@@ -52,11 +49,12 @@ func ReturnStringSubmatchInFileAllMatches(search string, filepath string) ([][]s
 	}
 	log.Debug("Opening " + filepath + " searching for " + search)
 	//Read file
-	file, err := osOpen(filepath)
+	file, err := FS.Open(RootPath(filepath))
 	if err != nil {
 		log.Debug("error reading file", filepath, err)
 		return nil, err
 	}
+	defer file.Close()
 	var results [][]string
 	scanner := bufio.NewScanner(file)
 	scanner.Split(bufio.ScanLines)
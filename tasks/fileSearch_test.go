@@ -2,13 +2,25 @@ package tasks
 
 import (
 	"errors"
-	"os"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
+// openErrorFS fails to open any file
+type openErrorFS struct {
+	FileSystem
+	err error
+}
+
+func (f openErrorFS) Open(string) (File, error) {
+	return nil, f.err
+}
+
 var _ = Describe("file search Task helpers", func() {
+	AfterEach(func() {
+		FS = OSFileSystem{}
+	})
 
 	Describe("FindStringInFile", func() {
 		var (
@@ -23,7 +35,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				search = "blarh[tryfh"
 				filepath = ""
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 			It("Should return false", func() {
 				Expect(exists).To(BeFalse())
@@ -33,9 +45,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				search = ""
 				filepath = ""
-				osOpen = func(string) (*os.File, error) {
-					return nil, errors.New("error opening file")
-				}
+				FS = openErrorFS{FileSystem: OSFileSystem{}, err: errors.New("error opening file")}
 			})
 			It("Should return false", func() {
 				Expect(exists).To(BeFalse())
@@ -45,7 +55,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				search = "not in the file!!!"
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 			It("Should return false", func() {
 				Expect(exists).To(BeFalse())
@@ -55,12 +65,24 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				search = "us-west-2.compute.internal"
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 			It("Should return false", func() {
 				Expect(exists).To(BeTrue())
 			})
 		})
+		Context("When regex is in a file of an in-memory filesystem", func() {
+			BeforeEach(func() {
+				search = "license_key: [a-z0-9]+"
+				filepath = "/etc/newrelic/newrelic.yml"
+				FS = NewMemFS(map[string]string{
+					"/etc/newrelic/newrelic.yml": "common:\n  license_key: abc123\n",
+				})
+			})
+			It("Should return true", func() {
+				Expect(exists).To(BeTrue())
+			})
+		})
 
 	})
 
@@ -78,7 +100,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
 				search = "us-west-2.compute.internal"
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 
 			It("should return expected string matching search string ", func() {
@@ -97,7 +119,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
 				search = `Relic (\d+\.\d\.\d\.\d{3})`
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 
 			It("should return expected string matching search regex ", func() {
@@ -113,7 +135,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
 				search = "xia"
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 
 			It("should return an empty slice of slices, because no match is found ", func() {
@@ -125,7 +147,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				search = ""
 				filepath = "no way this works"
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 			It("should err that is not nil", func() {
 				Expect(err).ToNot(BeNil())
@@ -135,7 +157,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				search = "bla[k"
 				filepath = ""
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 			It("should err that is not nil", func() {
 				Expect(err.Error()).To(Equal("error parsing regexp: missing closing ]: `[k`"))
@@ -145,9 +167,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				search = ""
 				filepath = "brokenfile"
-				osOpen = func(string) (*os.File, error) {
-					return nil, errors.New("Error opening file")
-				}
+				FS = openErrorFS{FileSystem: OSFileSystem{}, err: errors.New("Error opening file")}
 			})
 			It("should err that is not nil", func() {
 				Expect(err.Error()).To(Equal("Error opening file"))
@@ -172,7 +192,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
 				search = `Relic (\d+\.\d\.\d\.\d{3})`
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 
 			It("should return only first result", func() {
@@ -236,7 +256,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
 				search = `Relic (\d+\.\d\.\d\.\d{3})`
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 
 			It("should return only last result", func() {
@@ -257,7 +277,7 @@ var _ = Describe("file search Task helpers", func() {
 			BeforeEach(func() {
 				filepath = "fixtures/fileSearch/newrelic_agent.log"
 				search = `info: New Relic (?P<version>(?P<major>\d)(\.)?(?P<minor>\d+)(\.)?(?P<patch>\d+)(\.)?(?P<build>\d+)) \("(?P<codename>[^"]+)"`
-				osOpen = os.Open
+				FS = OSFileSystem{}
 			})
 
 			It("should return only last result", func() {
@@ -0,0 +1,132 @@
+package tasks
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// File is a file opened from a FileSystem
+type File interface {
+	io.Reader
+	io.Closer
+	Stat() (os.FileInfo, error)
+}
+
+// FileSystem is the read only view of the host's files used by the task helpers. Tasks go through FS instead of the os package
+// so their tests can declare a virtual host layout with MemFS
+type FileSystem interface {
+	Open(name string) (File, error)
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	// ReadDir returns the entries of the directory sorted by name
+	ReadDir(name string) ([]os.FileInfo, error)
+	Readlink(name string) (string, error)
+	EvalSymlinks(name string) (string, error)
+}
+
+// FS is the filesystem the task helpers read from, the local one by default
+var FS FileSystem = OSFileSystem{}
+
+// UseFileSystem replaces FS and returns a function restoring the previous one, e.g. `defer tasks.UseFileSystem(memFS)()` in a test
+func UseFileSystem(fsys FileSystem) func() {
+	previous := FS
+	FS = fsys
+	return func() {
+		FS = previous
+	}
+}
+
+// OSFileSystem is the FileSystem of the machine nrdiag runs on
+type OSFileSystem struct{}
+
+// Open - os.Open
+func (OSFileSystem) Open(name string) (File, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		// avoid returning a nil *os.File in a non nil File
+		return nil, err
+	}
+	return file, nil
+}
+
+// Stat - os.Stat
+func (OSFileSystem) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// Lstat - os.Lstat
+func (OSFileSystem) Lstat(name string) (os.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+// ReadDir - ioutil.ReadDir
+func (OSFileSystem) ReadDir(name string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(name)
+}
+
+// Readlink - os.Readlink
+func (OSFileSystem) Readlink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+// EvalSymlinks - filepath.EvalSymlinks
+func (OSFileSystem) EvalSymlinks(name string) (string, error) {
+	return filepath.EvalSymlinks(name)
+}
+
+// ReadFileBytes reads a whole file from FS, rebasing the path onto the -root directory if one is set
+func ReadFileBytes(name string) ([]byte, error) {
+	file, err := FS.Open(RootPath(name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ioutil.ReadAll(file)
+}
+
+// Walk is filepath.Walk for FS: it calls walkFn for root and every file and directory below it in lexical order, without following symlinks
+func Walk(root string, walkFn filepath.WalkFunc) error {
+	info, err := FS.Lstat(root)
+	if err != nil {
+		err = walkFn(root, nil, err)
+	} else {
+		err = walk(root, info, walkFn)
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walk(path string, info os.FileInfo, walkFn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return walkFn(path, info, nil)
+	}
+
+	entries, err := FS.ReadDir(path)
+	err1 := walkFn(path, info, err)
+	// walkFn gets the error reading the directory, if any, and decides whether the walk carries on
+	if err != nil || err1 != nil {
+		return err1
+	}
+
+	for _, entry := range entries {
+		filename := filepath.Join(path, entry.Name())
+		fileInfo, err := FS.Lstat(filename)
+		if err != nil {
+			if err := walkFn(filename, fileInfo, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		err = walk(filename, fileInfo, walkFn)
+		if err != nil {
+			if !fileInfo.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
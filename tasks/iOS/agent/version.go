@@ -3,7 +3,6 @@ package agent
 import (
 	"bufio"
 	"errors"
-	"os"
 	"strings"
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
@@ -90,7 +89,7 @@ func confirmHeaderFile(configs []config.ConfigElement) (string, error) {
 func parseHeaderFile(headerFilePath string) (string, error) {
 	var lineRef = "// Using New Relic Agent Version: " // This is the first line of the header file, the next chars after this string should be the version.
 
-	fileHandle, err := os.Open(headerFilePath)
+	fileHandle, err := tasks.FS.Open(tasks.RootPath(headerFilePath))
 	if err != nil {
 		return "", err
 	}
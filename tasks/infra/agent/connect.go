@@ -38,6 +38,11 @@ func (p InfraAgentConnect) Explain() string {
 	return "Check network connection to New Relic Infrastructure collector endpoint"
 }
 
+// RequiresNetwork - This task connects to the Infrastructure endpoints, so it is skipped with -offline
+func (p InfraAgentConnect) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - Returns the dependencies for each task.
 func (p InfraAgentConnect) Dependencies() []string {
 	return []string{
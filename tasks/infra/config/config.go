@@ -6,6 +6,7 @@ import (
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
 )
 
 // RegisterWith - will register any plugins in this package
@@ -20,6 +21,8 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 		runtimeOS: runtime.GOOS,
 	}, true)
 	registrationFunc(InfraConfigIntegrationsValidateJson{}, true)
+	registrationFunc(InfraConfigValidateSettings{}, true)
+	config.RegisterSettingsSpec("infra")
 	registrationFunc(InfraConfigValidateJMX{
 		mCmdExecutor:             tasks.MultiCmdExecutor,
 		getJMXProcessCmdlineArgs: getJMXProcessCmdlineArgs,
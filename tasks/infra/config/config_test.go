@@ -26,7 +26,7 @@ func TestRegisterWithCount(t *testing.T) {
 		registrationFunc func(tasks.Task, bool)
 	}
 
-	expectedRegisteredTaskCount := 7
+	expectedRegisteredTaskCount := 8
 
 	tests := []struct {
 		name      string
@@ -65,6 +65,7 @@ func TestRegisterWith(t *testing.T) {
 		InfraConfigIntegrationsValidate{fileReader: os.Open},
 		InfraConfigIntegrationsMatch{runtimeOS: runtime.GOOS},
 		InfraConfigIntegrationsValidateJson{},
+		InfraConfigValidateSettings{},
 		InfraConfigValidateJMX{mCmdExecutor: tasks.MultiCmdExecutor, getJMXProcessCmdlineArgs: getJMXProcessCmdlineArgs},
 	}
 
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
 
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
@@ -238,11 +239,11 @@ func (p InfraConfigIntegrationsMatch) isValidIntegrationFilePath(integrationFile
 	}
 
 	if fileType == CONFIG {
-		isValidPath = (filePath == configFilepath)
+		isValidPath = isSamePath(filePath, configFilepath)
 	} else if fileType == DEFINITION {
 
 		for _, path := range definitionFilepaths {
-			isValidPath = (filePath == path)
+			isValidPath = isSamePath(filePath, path)
 			if isValidPath == true {
 				break
 			}
@@ -258,6 +259,11 @@ func (p InfraConfigIntegrationsMatch) isValidIntegrationFilePath(integrationFile
 	return isValidPath, matchError
 }
 
+// isSamePath compares two paths once cleaned, so a trailing separator doesn't matter
+func isSamePath(path, expectedPath string) bool {
+	return filepath.Clean(path) == filepath.Clean(expectedPath)
+}
+
 //Valdates that a map of IntegrationFilePair actually have defined Configuration and Definition files.
 //If IntegrationFilePair only has one file, it is removed from the map and captured as an IntegrationMatchError
 //If IntegrationFilePair has two files, this will validate that their integration names as parsed from the yaml match
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+)
+
+// replacedLogSettings are the settings the log block replaced, and their replacement
+var replacedLogSettings = map[string]string{
+	"verbose":                        "log.level",
+	"log_file":                       "log.file",
+	"log_format":                     "log.format",
+	"log_to_stdout":                  "log.stdout",
+	"smart_verbose_mode_entry_limit": "log.smart_level_entry_limit",
+}
+
+// InfraConfigValidateSettings - checks the newrelic-infra.yml settings against a spec of their types and allowed
+// values, and for settings overridden by the NRIA_ environment variables of the running agent
+type InfraConfigValidateSettings struct {
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p InfraConfigValidateSettings) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("Infra/Config/ValidateSettings")
+}
+
+// Explain - Returns the help text for each individual task
+func (p InfraConfigValidateSettings) Explain() string {
+	return "Validate the types and values of Infrastructure agent config settings, and flag deprecated, conflicting or overridden ones"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p InfraConfigValidateSettings) Dependencies() []string {
+	return []string{
+		"Infra/Config/Agent",
+		"Base/Env/CollectEnvVars",
+	}
+}
+
+// Execute - The core work within each task
+func (p InfraConfigValidateSettings) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	configs, ok := upstream["Infra/Config/Agent"].Payload.([]config.ValidateElement)
+	if !ok || len(configs) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No Infrastructure agent config files found",
+		}
+	}
+
+	spec, err := config.LoadSettingsSpec(options, "infra")
+	if err != nil {
+		return tasks.Result{
+			Status:  tasks.Error,
+			Summary: "Unable to load the Infrastructure agent settings spec: " + err.Error(),
+		}
+	}
+	envVars := agentEnvVars(upstream)
+
+	shadowed := func(settings map[string]interface{}) []config.SettingProblem {
+		return spec.Shadowed(settings, envVars)
+	}
+	proxy := func(settings map[string]interface{}) []config.SettingProblem {
+		return checkProxySettings(settings, envVars)
+	}
+	return config.ValidateAgentSettings(spec, configs, checkReplacedLogSettings, proxy, shadowed)
+}
+
+// agentEnvVars - the environment of the running newrelic-infra process, which the agent reads its NRIA_ and proxy
+// variables from. The shell's variables are only used when the agent isn't running.
+func agentEnvVars(upstream map[string]tasks.Result) map[string]string {
+	processes, err := tasks.FindProcessByName("newrelic-infra")
+	if err != nil {
+		log.Debug("Unable to look up the newrelic-infra process:", err)
+	}
+	for _, process := range processes {
+		envVars, err := tasks.GetProcessEnvVars(process.Pid)
+		if err == nil {
+			return envVars.All
+		}
+	}
+	envVars, _ := upstream["Base/Env/CollectEnvVars"].Payload.(map[string]string)
+	return envVars
+}
+
+// checkReplacedLogSettings - reports deprecated log settings set next to the log block setting replacing them
+func checkReplacedLogSettings(settings map[string]interface{}) []config.SettingProblem {
+	var problems []config.SettingProblem
+	for old, replacement := range replacedLogSettings {
+		value, oldSet := settings[old]
+		if _, set := settings[replacement]; oldSet && set {
+			problems = append(problems, config.SettingProblem{
+				Key:     old,
+				Value:   value,
+				Status:  config.SettingConflict,
+				Message: fmt.Sprintf("is also set as %s (%v), remove the deprecated setting", replacement, settings[replacement]),
+			})
+		}
+	}
+	return problems
+}
+
+// checkProxySettings - reports proxy settings that have no effect, or that make the agent skip the system proxy.
+// The agent uses NRIA_PROXY, then proxy, then HTTPS_PROXY and HTTP_PROXY unless ignore_system_proxy is set.
+func checkProxySettings(settings map[string]interface{}, envVars map[string]string) []config.SettingProblem {
+	var problems []config.SettingProblem
+	proxy := settingString(settings, "proxy")
+	if envVars["NRIA_PROXY"] != "" {
+		proxy = envVars["NRIA_PROXY"]
+	}
+
+	if proxy != "" && !strings.HasPrefix(proxy, "http://") && !strings.HasPrefix(proxy, "https://") {
+		problems = append(problems, config.SettingProblem{Key: "proxy", Value: proxy, Status: config.SettingInvalid, Message: "should start with http:// or https://"})
+	}
+
+	systemProxy := envVars["HTTPS_PROXY"]
+	if systemProxy == "" {
+		systemProxy = envVars["HTTP_PROXY"]
+	}
+	if ignoresSystemProxy(settings) && proxy == "" && systemProxy != "" {
+		problems = append(problems, config.SettingProblem{
+			Key:     "ignore_system_proxy",
+			Value:   settings["ignore_system_proxy"],
+			Status:  config.SettingConflict,
+			Message: "is set without a proxy, the agent connects directly instead of through " + systemProxy,
+		})
+	}
+	if proxy == "" && (ignoresSystemProxy(settings) || systemProxy == "") {
+		for _, key := range []string{"ca_bundle_file", "ca_bundle_dir", "proxy_validate_certificates"} {
+			if value, set := settings[key]; set {
+				problems = append(problems, config.SettingProblem{Key: key, Value: value, Status: config.SettingConflict, Message: "has no effect without a proxy"})
+			}
+		}
+	} else if strings.HasPrefix(proxy, "http://") && isTrue(settingString(settings, "proxy_validate_certificates")) {
+		problems = append(problems, config.SettingProblem{
+			Key:     "proxy_validate_certificates",
+			Value:   settings["proxy_validate_certificates"],
+			Status:  config.SettingConflict,
+			Message: "only applies to https:// proxies, but the proxy is " + proxy,
+		})
+	}
+	return problems
+}
+
+func ignoresSystemProxy(settings map[string]interface{}) bool {
+	return isTrue(settingString(settings, "ignore_system_proxy"))
+}
+
+func settingString(settings map[string]interface{}, key string) string {
+	if value, ok := settings[key]; ok && value != nil {
+		return strings.TrimSpace(fmt.Sprintf("%v", value))
+	}
+	return ""
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Infra/Config/ValidateSettings", func() {
+	var p InfraConfigValidateSettings
+
+	Describe("Execute()", func() {
+		var (
+			result    tasks.Result
+			content   string
+			envVars   map[string]string
+			processes string
+		)
+
+		problems := func() []config.SettingProblem {
+			validations := result.Payload.([]config.SettingsValidation)
+			Expect(validations).To(HaveLen(1))
+			return validations[0].Problems
+		}
+
+		BeforeEach(func() {
+			content = "license_key: abc\ncustom_attributes:\n  team: alpha\nlog:\n  level: info\nmetrics_process_sample_rate: 20s\nmetrics_system_sample_rate: 5\n"
+			envVars = map[string]string{}
+			processes = "processes: []"
+		})
+
+		JustBeforeEach(func() {
+			source, err := tasks.NewFakeProcessSource([]byte(processes))
+			Expect(err).NotTo(HaveOccurred())
+			defer tasks.UseProcessSource(source)()
+			blob, err := config.ParseYaml(strings.NewReader(content))
+			Expect(err).NotTo(HaveOccurred())
+			result = p.Execute(tasks.Options{}, map[string]tasks.Result{
+				"Infra/Config/Agent": {Status: tasks.Success, Payload: []config.ValidateElement{{
+					Config:       config.ConfigElement{FileName: "newrelic-infra.yml", FilePath: "/etc/"},
+					ParsedResult: blob,
+				}}},
+				"Base/Env/CollectEnvVars": {Status: tasks.Info, Payload: envVars},
+			})
+		})
+
+		Context("when every setting matches the spec", func() {
+			It("should return Success", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+			})
+		})
+
+		Context("when the agent was not detected", func() {
+			It("should return None", func() {
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{"Infra/Config/Agent": {Status: tasks.None}})
+				Expect(result.Status).To(Equal(tasks.None))
+			})
+		})
+
+		Context("when log.level and a sample rate are invalid", func() {
+			BeforeEach(func() {
+				content = "license_key: abc\nlog:\n  level: verbose\nmetrics_network_sample_rate: 10 seconds\n"
+			})
+			It("should return Warning with both settings", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(problems()).To(Equal([]config.SettingProblem{
+					{Key: "log.level", Value: "verbose", Status: config.SettingInvalid, Message: "should be one of error, warn, info, debug, trace"},
+					{Key: "metrics_network_sample_rate", Value: "10 seconds", Status: config.SettingInvalid, Message: "should be a number of seconds or a duration such as 30s (not 10 seconds)"},
+				}))
+			})
+		})
+
+		Context("when verbose is set next to the log block", func() {
+			BeforeEach(func() {
+				content = "license_key: abc\nverbose: 1\nlog:\n  level: info\n"
+			})
+			It("should report verbose as deprecated and conflicting", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(problems()).To(Equal([]config.SettingProblem{
+					{Key: "verbose", Value: 1, Status: config.SettingDeprecated, Message: "is deprecated: use log.level instead"},
+					{Key: "verbose", Value: 1, Status: config.SettingConflict, Message: "is also set as log.level (info), remove the deprecated setting"},
+				}))
+			})
+		})
+
+		Context("when only the deprecated verbose is set", func() {
+			BeforeEach(func() {
+				content = "license_key: abc\nverbose: 0\n"
+			})
+			It("should return Info", func() {
+				Expect(result.Status).To(Equal(tasks.Info))
+			})
+		})
+
+		Context("when NRIA_ environment variables override the file", func() {
+			BeforeEach(func() {
+				envVars = map[string]string{"NRIA_LICENSE_KEY": "def", "NRIA_CUSTOM_ATTRIBUTES": `{"team":"beta"}`}
+			})
+			It("should report the shadowed settings", func() {
+				Expect(result.Status).To(Equal(tasks.Info))
+				Expect(problems()).To(Equal([]config.SettingProblem{
+					{Key: "custom_attributes", Value: map[string]interface{}{"team": "alpha"}, Status: config.SettingShadowed, Message: `is overridden by NRIA_CUSTOM_ATTRIBUTES={"team":"beta"}`},
+					{Key: "license_key", Value: "abc", Status: config.SettingShadowed, Message: "is overridden by NRIA_LICENSE_KEY=def"},
+				}))
+			})
+		})
+
+		Context("when the running agent has NRIA_ environment variables the shell doesn't", func() {
+			BeforeEach(func() {
+				envVars = map[string]string{"NRIA_LOG_LEVEL": "debug"}
+				processes = `
+processes:
+  - pid: 42
+    name: newrelic-infra
+    env: {NRIA_LICENSE_KEY: def}
+`
+			})
+			It("should report the settings the agent process overrides", func() {
+				Expect(result.Status).To(Equal(tasks.Info))
+				Expect(problems()).To(Equal([]config.SettingProblem{
+					{Key: "license_key", Value: "abc", Status: config.SettingShadowed, Message: "is overridden by NRIA_LICENSE_KEY=def"},
+				}))
+			})
+		})
+
+		Context("when the proxy settings conflict", func() {
+			BeforeEach(func() {
+				content = "license_key: abc\nignore_system_proxy: true\nca_bundle_file: /etc/ssl/proxy.pem\n"
+				envVars = map[string]string{"HTTPS_PROXY": "https://proxy:3128"}
+			})
+			It("should report the system proxy being skipped and the unused CA bundle", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(problems()).To(Equal([]config.SettingProblem{
+					{Key: "ca_bundle_file", Value: "/etc/ssl/proxy.pem", Status: config.SettingConflict, Message: "has no effect without a proxy"},
+					{Key: "ignore_system_proxy", Value: true, Status: config.SettingConflict, Message: "is set without a proxy, the agent connects directly instead of through https://proxy:3128"},
+				}))
+			})
+		})
+
+		Context("when certificates are validated for an http proxy", func() {
+			BeforeEach(func() {
+				content = "license_key: abc\nproxy: http://proxy:3128\nproxy_validate_certificates: true\n"
+			})
+			It("should report proxy_validate_certificates", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(problems()).To(Equal([]config.SettingProblem{
+					{Key: "proxy_validate_certificates", Value: true, Status: config.SettingConflict, Message: "only applies to https:// proxies, but the proxy is http://proxy:3128"},
+				}))
+			})
+		})
+	})
+})
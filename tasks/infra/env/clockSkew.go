@@ -41,6 +41,11 @@ func (p InfraEnvClockSkew) Explain() string {
 	return "Detect if host has clock skew from New Relic collector"
 }
 
+// RequiresNetwork - This task compares the clock with the time of a New Relic endpoint, so it is skipped with -offline
+func (p InfraEnvClockSkew) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - Returns the dependencies for each task.
 func (p InfraEnvClockSkew) Dependencies() []string {
 	return []string{"Infra/Agent/Connect", "Base/Config/ProxyDetect"}
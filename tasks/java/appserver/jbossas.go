@@ -7,7 +7,6 @@ import (
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
-	"github.com/shirou/gopsutil/process"
 )
 
 // JavaAppserverJBossAsCheck - This struct defines the JBoss AS version check
@@ -17,7 +16,7 @@ type JavaAppserverJBossAsCheck struct {
 	findProcessByName     tasks.FindProcessByNameFunc
 	returnSubstringInFile tasks.ReturnStringInFileFunc
 }
-type getCmdlineFromProcessFunc func(process.Process) string
+type getCmdlineFromProcessFunc func(tasks.RunningProcess) string
 type getAndParseJBossAsReadMeFunc func(string, func([]string, []string) []string, tasks.ReturnStringInFileFunc) ([]string, error)
 type checkJBossAsVersionFunc func([]string) (string, tasks.Status)
 type getHomeDirFromCmdlineFunc func(string) string
@@ -101,7 +100,7 @@ func (p JavaAppserverJBossAsCheck) Execute(options tasks.Options, upstream map[s
 	return result
 }
 
-func getCmdlineFromProcess(proc process.Process) string {
+func getCmdlineFromProcess(proc tasks.RunningProcess) string {
 	cmdline, _ := proc.Cmdline()
 	return cmdline
 }
@@ -7,7 +7,6 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/shirou/gopsutil/process"
 
 	baseConfig "github.com/newrelic/newrelic-diagnostics-cli/config"
 	tasks "github.com/newrelic/newrelic-diagnostics-cli/tasks"
@@ -55,8 +54,8 @@ var _ = Describe("JavaAppserverJBossAsCheck", func() {
 
 		Context("When running on linux with JBOSS_HOME not set and no running Java processes", func() {
 			BeforeEach(func() {
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{}, nil
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{}, nil
 				}
 
 				p.findFiles = func([]string, []string) []string {
@@ -82,11 +81,11 @@ var _ = Describe("JavaAppserverJBossAsCheck", func() {
 
 		Context("When running on linux and JBOSS_HOME ENV var not set and java process found", func() {
 			BeforeEach(func() {
-				p.getCmdline = func(process.Process) string {
+				p.getCmdline = func(tasks.RunningProcess) string {
 					return "jboss.home.dir=/jboss-5.4.0/server"
 				}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{process.Process{Pid: 1}}, nil
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{tasks.RunningProcess{Pid: 1}}, nil
 				}
 				upstream = map[string]tasks.Result{
 					"Base/Env/CollectEnvVars": tasks.Result{},
@@ -107,11 +106,11 @@ var _ = Describe("JavaAppserverJBossAsCheck", func() {
 
 		Context("When running on windows and JBOSS_HOME ENV var not set and java process found", func() {
 			BeforeEach(func() {
-				p.getCmdline = func(process.Process) string {
+				p.getCmdline = func(tasks.RunningProcess) string {
 					return `jboss.home.dir=C:\appserver\jboss`
 				}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{process.Process{Pid: 1}}, nil
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{tasks.RunningProcess{Pid: 1}}, nil
 				}
 				p.findFiles = func([]string, []string) []string {
 					return []string{"README.txt"}
@@ -130,11 +129,11 @@ var _ = Describe("JavaAppserverJBossAsCheck", func() {
 
 		Context("When error reading processes", func() {
 			BeforeEach(func() {
-				p.getCmdline = func(process.Process) string {
+				p.getCmdline = func(tasks.RunningProcess) string {
 					return ""
 				}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{}, errors.New("Could not read processes")
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{}, errors.New("Could not read processes")
 				}
 			})
 
@@ -145,8 +144,8 @@ var _ = Describe("JavaAppserverJBossAsCheck", func() {
 		})
 		Context("When error retrieving list of processes", func() {
 			BeforeEach(func() {
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{}, errors.New("I like sandwiches")
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{}, errors.New("I like sandwiches")
 				}
 				upstream = map[string]tasks.Result{
 					"Base/Env/CollectEnvVars": tasks.Result{},
@@ -162,8 +161,8 @@ var _ = Describe("JavaAppserverJBossAsCheck", func() {
 		})
 		Context("When jboss not detected as installed", func() {
 			BeforeEach(func() {
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{}, nil
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{}, nil
 				}
 				upstream = map[string]tasks.Result{
 					"Base/Env/CollectEnvVars": tasks.Result{},
@@ -3,6 +3,7 @@ package config
 import (
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
 )
 
 // RegisterWith - will register any plugins in this package
@@ -12,4 +13,5 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 	registrationFunc(JavaConfigAgent{}, true)
 	registrationFunc(JavaConfigValidate{}, true)
 	registrationFunc(JavaConfigValidateSettings{}, true)
+	config.RegisterSettingsSpec("java")
 }
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -12,7 +11,6 @@ import (
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks/java/env"
-	"github.com/shirou/gopsutil/process"
 )
 
 // JavaConfigValidate - This struct defined the sample plugin which can be used as a starting point
@@ -20,7 +18,7 @@ type JavaConfigValidate struct {
 }
 
 type JavaValidatedConfig struct {
-	Proc              process.Process
+	Proc              tasks.RunningProcess
 	ParsedResult      tasks.ValidateBlob
 	ConfigPath        string
 	CurrentWorkingDir string
@@ -29,7 +27,7 @@ type JavaValidatedConfig struct {
 //MarshalJSON - custom JSON marshaling for this task, in this case we ignore the ParsedResult
 func (el JavaValidatedConfig) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		Proc              process.Process
+		Proc              tasks.RunningProcess
 		ConfigPath        string
 		CurrentWorkingDir string
 	}{
@@ -193,10 +191,12 @@ func matchConfigFile(processWorkingDir string, validations []config.ValidateElem
 	if configPath != "" && parsedResult.IsLeaf() == true {
 		//Now we need to create a new validated blob
 		log.Debug("Configpath is", configPath)
-		file, err := os.Open(configPath)
+		file, err := tasks.FS.Open(tasks.RootPath(configPath))
 		if err != nil {
 			log.Debug("error reading file", err)
+			return
 		}
+		defer file.Close()
 		parsedResult, err = config.ParseYaml(file)
 		if err != nil {
 			log.Debug("error reading file", err)
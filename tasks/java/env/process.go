@@ -8,12 +8,11 @@ import (
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
-	"github.com/shirou/gopsutil/process"
 )
 
 /* structure to contain a process and its corresponding command line args */
 type ProcIdAndArgs struct {
-	Proc        process.Process
+	Proc        tasks.RunningProcess
 	CmdLineArgs []string
 	Cwd         string
 	JarPath     string
@@ -23,8 +22,8 @@ type ProcIdAndArgs struct {
 type JavaEnvProcess struct {
 	name           string
 	findProcByName tasks.FindProcessByNameFunc
-	getCmdLineArgs func(process.Process) (string, error)
-	getCwd         func(process.Process) (string, error)
+	getCmdLineArgs func(tasks.RunningProcess) (string, error)
+	getCwd         func(tasks.RunningProcess) (string, error)
 }
 
 // Identifier - returns the Category (Agent), Subcategory (Java) and Name (SysPropCollect)
@@ -123,7 +122,7 @@ func (p JavaEnvProcess) Execute(options tasks.Options, upstream map[string]tasks
 }
 
 //getCmdLineArgs is a wrapper for dependency injecting proc.Cmdline in testing
-func getCmdLineArgs(proc process.Process) (string, error) {
+func getCmdLineArgs(proc tasks.RunningProcess) (string, error) {
 	return proc.Cmdline()
 }
 
@@ -164,6 +163,6 @@ func getJarInfoFromCmdLineArgs(cmdLineArgsString string) (string, string, error)
 	return path, fileName, err
 }
 
-func getCwd(proc process.Process) (string, error) {
+func getCwd(proc tasks.RunningProcess) (string, error) {
 	return proc.Cwd()
 }
@@ -11,7 +11,6 @@ import (
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/shirou/gopsutil/process"
 )
 
 func TestJavaEnvProcess(t *testing.T) {
@@ -67,8 +66,8 @@ var _ = Describe("JavaEnvProcess", func() {
 						Payload: map[string]string{},
 					},
 				}
-				p.findProcByName = func(string) ([]process.Process, error) {
-					return []process.Process{}, errors.New("an error message")
+				p.findProcByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{}, errors.New("an error message")
 				}
 			})
 
@@ -104,20 +103,20 @@ var _ = Describe("JavaEnvProcess", func() {
 							Payload: envVarsPayload,
 						},
 					}
-					javaProcesses := []process.Process{
-						process.Process{
+					javaProcesses := []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
 					}
-					p.findProcByName = func(string) ([]process.Process, error) {
+					p.findProcByName = func(string) ([]tasks.RunningProcess, error) {
 						return javaProcesses, nil
 					}
 					cmdLineArgs := "-javaagent:/root/go/src/github.com/newrelic/newrelic-diagnostics-cli/newrelic.jar"
-					p.getCmdLineArgs = func(process.Process) (string, error) {
+					p.getCmdLineArgs = func(tasks.RunningProcess) (string, error) {
 						return cmdLineArgs, nil
 					}
 					cmdLineArgsList := strings.Split(cmdLineArgs, " ")
-					p.getCwd = func(process.Process) (string, error) {
+					p.getCwd = func(tasks.RunningProcess) (string, error) {
 						return "/root/go/src/github.com/newrelic/newrelic-diagnostics-cli", nil
 					}
 					expectedPayload = append(expectedPayload, ProcIdAndArgs{Proc: javaProcesses[0], CmdLineArgs: cmdLineArgsList, Cwd: "/root/go/src/github.com/newrelic/newrelic-diagnostics-cli", JarPath: "/root/go/src/github.com/newrelic/newrelic-diagnostics-cli/newrelic.jar", EnvVars: envVarsPayload})
@@ -157,20 +156,20 @@ var _ = Describe("JavaEnvProcess", func() {
 							Payload: envVarsPayload,
 						},
 					}
-					javaProcesses := []process.Process{
-						process.Process{
+					javaProcesses := []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
 					}
-					p.findProcByName = func(string) ([]process.Process, error) {
+					p.findProcByName = func(string) ([]tasks.RunningProcess, error) {
 						return javaProcesses, nil
 					}
 					cmdLineArgs := "-javaagent:/root/go/src/github.com/newrelic/newrelic-diagnostics-cli/newrelic-1.8.0.jar"
-					p.getCmdLineArgs = func(process.Process) (string, error) {
+					p.getCmdLineArgs = func(tasks.RunningProcess) (string, error) {
 						return cmdLineArgs, nil
 					}
 					cmdLineArgsList := strings.Split(cmdLineArgs, " ")
-					p.getCwd = func(process.Process) (string, error) {
+					p.getCwd = func(tasks.RunningProcess) (string, error) {
 						return "/root/go/src/github.com/newrelic/newrelic-diagnostics-cli", nil
 					}
 					expectedPayload = append(expectedPayload, ProcIdAndArgs{Proc: javaProcesses[0], CmdLineArgs: cmdLineArgsList, Cwd: "/root/go/src/github.com/newrelic/newrelic-diagnostics-cli", JarPath: "/root/go/src/github.com/newrelic/newrelic-diagnostics-cli/newrelic-1.8.0.jar", EnvVars: envVarsPayload})
@@ -210,16 +209,16 @@ var _ = Describe("JavaEnvProcess", func() {
 							Payload: envVarsPayload,
 						},
 					}
-					javaProcesses := []process.Process{
+					javaProcesses := []tasks.RunningProcess{
 						{
 							Pid: 1,
 						},
 					}
-					p.findProcByName = func(string) ([]process.Process, error) {
+					p.findProcByName = func(string) ([]tasks.RunningProcess, error) {
 						return javaProcesses, nil
 					}
 					cmdLineArgs := "-javaagent:/root/go/src/github.com/newrelic/newrelic-diagnostics-cli/bluerelic-1.8.0.jar"
-					p.getCmdLineArgs = func(process.Process) (string, error) {
+					p.getCmdLineArgs = func(tasks.RunningProcess) (string, error) {
 						return cmdLineArgs, nil
 					}
 				})
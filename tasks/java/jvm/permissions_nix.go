@@ -17,7 +17,6 @@ import (
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 	baseLog "github.com/newrelic/newrelic-diagnostics-cli/tasks/base/log"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks/java/env"
-	"github.com/shirou/gopsutil/process"
 )
 
 var (
@@ -188,7 +187,7 @@ func buildErrMsg(j *JavaAgentPermissions) string {
 
 }
 
-func determineTmpDirPermissions(proc process.Process, upstream map[string]tasks.Result, j *JavaAgentPermissions) {
+func determineTmpDirPermissions(proc tasks.RunningProcess, upstream map[string]tasks.Result, j *JavaAgentPermissions) {
 	var tempDir, tempDirSource string
 	//Find location of tempDir in System Properties. New Relic sys prop should take precedence over standard java tmp files directory sys prop
 	if upstream["Base/Env/CollectSysProps"].Status == tasks.Info {
@@ -255,7 +254,7 @@ func determineTmpDirPermissions(proc process.Process, upstream map[string]tasks.
 }
 
 /* need write/execute access to temp dir */
-func canCreateFilesInTempDir(proc process.Process, tempDir string) (err error) {
+func canCreateFilesInTempDir(proc tasks.RunningProcess, tempDir string) (err error) {
 	procOwnerUID, procOwnerGID, fileOwnerUID, fileOwnerGID, err := getUIDsGIDs(proc, tempDir)
 
 	if err != nil {
@@ -292,7 +291,7 @@ func canCreateFilesInTempDir(proc process.Process, tempDir string) (err error) {
 	return nil
 }
 
-func determineLogPermissions(proc process.Process, jarPath string, upstream map[string]tasks.Result, j *JavaAgentPermissions) {
+func determineLogPermissions(proc tasks.RunningProcess, jarPath string, upstream map[string]tasks.Result, j *JavaAgentPermissions) {
 	//attempt to get the log file path by looking into the logElements provided by the Base/Log/Copy task
 	logElements, ok := upstream["Base/Log/Copy"].Payload.([]baseLog.LogElement)
 	if !ok {
@@ -341,7 +340,7 @@ func determineLogPermissions(proc process.Process, jarPath string, upstream map[
 }
 
 /* need write/execute access to log directory */
-func canCreateAgentLog(proc process.Process, logFilePath, jarPath string) (err error) {
+func canCreateAgentLog(proc tasks.RunningProcess, logFilePath, jarPath string) (err error) {
 	logDir := filepath.Dir(logFilePath)
 	if _, err := os.Stat(logDir); os.IsNotExist(err) {
 		log.Debug("logs directory does not exist: ", logDir)
@@ -384,7 +383,7 @@ func canCreateAgentLog(proc process.Process, logFilePath, jarPath string) (err e
 }
 
 /* need read permissions only; "java" is being executed and it needs to read the JAR */
-func canReadAgentJar(proc process.Process, jarLoc string) (err error) {
+func canReadAgentJar(proc tasks.RunningProcess, jarLoc string) (err error) {
 	/* check if the Java Agent JAR exists */
 	if _, errJarNotExist := os.Stat(jarLoc); os.IsNotExist(errJarNotExist) {
 		return fmt.Errorf(`Agent JAR does not exist for PID %d. This location is at %s: %w`, proc.Pid, jarLoc, errJarNotExist)
@@ -423,7 +422,7 @@ func canReadAgentJar(proc process.Process, jarLoc string) (err error) {
 	return nil
 }
 
-func determineJarPermissions(proc process.Process, jarLoc string, j *JavaAgentPermissions) {
+func determineJarPermissions(proc tasks.RunningProcess, jarLoc string, j *JavaAgentPermissions) {
 	err := canReadAgentJar(proc, jarLoc)
 
 	//assign javaAgentPermissions values to Jar
@@ -444,7 +443,7 @@ func determineJarPermissions(proc process.Process, jarLoc string, j *JavaAgentPe
 	j.AgentJarCanRead.Value = jarLoc
 }
 
-func getUIDsGIDs(proc process.Process, fileOrDirPath string) (string, string, string, string, error) {
+func getUIDsGIDs(proc tasks.RunningProcess, fileOrDirPath string) (string, string, string, string, error) {
 	procOwner, _ := proc.Username()
 	procOwnerUser, err := user.Lookup(procOwner)
 	if err != nil {
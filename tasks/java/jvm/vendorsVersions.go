@@ -8,7 +8,6 @@ import (
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks/compatibilityVars"
-	"github.com/shirou/gopsutil/process"
 )
 
 type supportabilityStatus int
@@ -31,10 +30,10 @@ type PIDInfo struct {
 }
 
 type JavaJVMVendorsVersions struct {
-	findProcessByName func(string) ([]process.Process, error)
+	findProcessByName func(string) ([]tasks.RunningProcess, error)
 	cmdExec           func(name string, arg ...string) ([]byte, error)
 	runtimeGOOS       string
-	getCmdLineArgs    func(process.Process) (string, error)
+	getCmdLineArgs    func(tasks.RunningProcess) (string, error)
 }
 
 // Identifier - This returns the Category, Subcategory and Name of each task
@@ -252,7 +251,7 @@ func parseJavaExecutable(cmdLineArgs string) string {
 }
 
 //getCmdLineArgs is a wrapper for dependency injecting proc.Cmdline in testing
-func getCmdLineArgs(proc process.Process) (string, error) {
+func getCmdLineArgs(proc tasks.RunningProcess) (string, error) {
 	return proc.Cmdline()
 }
 
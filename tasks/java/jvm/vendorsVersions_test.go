@@ -8,7 +8,6 @@ import (
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/shirou/gopsutil/process"
 )
 
 func TestJavaJVMVendorsVersion(t *testing.T) {
@@ -1003,8 +1002,8 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 					Status:  tasks.Success,
 					Payload: "4.3",
 				}}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{}, errors.New("an error message")
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{}, errors.New("an error message")
 				}
 			})
 
@@ -1023,8 +1022,8 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 					Status:  tasks.Success,
 					Payload: "4.3",
 				}}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{}, nil
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{}, nil
 				}
 			})
 
@@ -1040,17 +1039,17 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
 				p.runtimeGOOS = "linux" // IBM only compatible on Linux
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{
-						process.Process{
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
-						process.Process{
+						tasks.RunningProcess{
 							Pid: 2,
 						},
 					}, nil
 				}
-				p.getCmdLineArgs = func(proc process.Process) (string, error) {
+				p.getCmdLineArgs = func(proc tasks.RunningProcess) (string, error) {
 					var cmdLineArgs string
 					if proc.Pid == 1 {
 						cmdLineArgs = "/usr/local/bin/hotspot/java"
@@ -1098,12 +1097,12 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 		Context("When one of the attempts to retrieve the cmdLineArgs returns an error, but the other succeeds", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{
-						process.Process{
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
-						process.Process{
+						tasks.RunningProcess{
 							Pid: 2,
 						},
 					}, nil
@@ -1114,7 +1113,7 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 				Java HotSpot(TM) 64-Bit Server VM (build 9+181, mixed mode)`), nil
 				}
 
-				p.getCmdLineArgs = func(proc process.Process) (string, error) {
+				p.getCmdLineArgs = func(proc tasks.RunningProcess) (string, error) {
 					// Generate an error for one process
 					if proc.Pid == 1 {
 						return "", errors.New("Couldn't do that! Error")
@@ -1144,15 +1143,15 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 		Context("when unable to determine Java executable from cmdLineArgs", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{
-						process.Process{
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
 					}, nil
 				}
 
-				p.getCmdLineArgs = func(process.Process) (string, error) {
+				p.getCmdLineArgs = func(tasks.RunningProcess) (string, error) {
 					// unparseable java executable, but valid cmdlineArgs for HotSpot and Java 1.8
 					return `/home/duke/jeva -Xmx700m -Djava.vm.name=Java HotSpot™ 64-Bit Server VM -Djava.version=1.8 -Djava.awt.headless=true -Djava.endorsed.dirs="" -Djdt.compiler.useSingleThread=true -Dpreload.project.path=/Users/jmcgrath/code/samsa -Dpreload.config.path=/Users/jmcgrath/Library/Preferences/IdeaIC2018.3/options -Dexternal.project.config=/Users/jmcgrath/Library/Caches/IdeaIC2018.3/external_build_system/samsa.48129cba -Dcompile.parallel=false -Drebuild.on.dependency.change=true -Djava.net.preferIPv4Stack=true -Dio.netty.initialSeedUniquifier=-768099212347918098 -Dfile.encoding=UTF-8 -Duser.language=en -Duser.country=US -Didea.paths.selector=IdeaIC2018.3 -Didea.home.path=/Applications/IntelliJ IDEA CE.app/Contents -Didea.config.path=/Users/jmcgrath/Library/Preferences/IdeaIC2018.3 -Didea.plugins.path=/Users/jmcgrath/Library/Application Support/IdeaIC2018.3 -Djps.log.dir=/Users/jmcgrath/Library/Logs/IdeaIC2018.3/build-log -Djps.fallback.jdk.home=/Applications/IntelliJ IDEA CE.app/Contents/jdk/Contents/Home/jre -Djps.fallback.jdk.version=1.8.0_152-release -Dio.netty.noUnsafe=true -Djava.io.tmpdir=/Users/jmcgrath/Library/Caches/IdeaIC2018.3/compile-server/samsa_80e1e690/_temp_ -Djps.backward.ref.index.builder=true -Dkotlin.incremental.compilation=true -Dkotlin.daemon.enabled -Dkotlin.daemon.client.alive.path="/var/folders/8t/zvmxntvd4w7_j6flmkcj4jmr0000gn/T/kotlin-idea-3860326101882290058-is-running" -classpath /Applications/IntelliJ IDEA CE.app/Contents/lib/jps-launcher.jar:/Library/Java/JavaVirtualMachines/jdk1.8.0_152.jdk/Contents/Home/lib/tools.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/optimizedFileManager.jar org.jetbrains.jps.cmdline.Launcher /Applications/IntelliJ IDEA CE.app/Contents/lib/util.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jna-platform.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-aether-provider-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-builder-support-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-util-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/log4j.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/lz4-1.3.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-model-builder-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/asm-all-7.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/commons-codec-1.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/nanoxml-2.2.3.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-repository-metadata-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-transport-file-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/trove4j.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/plexus-utils-3.0.22.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/httpcore-4.4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-codec-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jps-builders.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jna.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-buffer-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/plexus-component-annotations-1.6.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-dependency-resolver.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/guava-25.1-jre.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-artifact-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-api-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/commons-lang3-3.4.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-model-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-impl-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/httpclient-4.5.6.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/idea_rt.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/resources_en.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-resolver-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/plexus-interpolation-1.21.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/oro-2.0.8.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/protobuf-java-3.4.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jps-model.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-transport-http-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-connector-basic-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/platform-api.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/forms-1.1-preview.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/slf4j-api-1.7.25.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jps-builders-6.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jdom.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-transport-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-spi-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/annotations.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/commons-logging-1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/javac2.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-common-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-impldep-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/ant/lib/ant.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/groovy-all-2.4.15.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/gson-2.8.5.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/slf4j-api-1.7.25.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/slf4j-log4j12-1.7.25.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/gson-2.8.5.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jarutils.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/guava-25.1-jre.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/manifest-merger-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/sdk-common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-model-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-test-api-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/ddmlib-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/repository-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/gson-2.8.5.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jarutils.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/guava-25.1-jre.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/manifest-merger-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/sdk-common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-model-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-test-api-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/ddmlib-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/repository-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/ant/lib/ant-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/uiDesigner/lib/jps/ui-designer-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/IntelliLang/lib/intellilang-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Groovy/lib/groovy-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Groovy/lib/groovy-rt-constants.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/eclipse/lib/eclipse-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/eclipse/lib/common-eclipse-util.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/maven/lib/maven-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/devkit/lib/devkit-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jps/android-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/android-common.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/build-common.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/android-rt.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/sdklib.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jarutils.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/layoutlib-api.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/jps/kotlin-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/kotlin-stdlib.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/kotlin-reflect.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/kotlin-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/android-extensions-ide.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/android-extensions-compiler.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/javaFX/lib/javaFX-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/javaFX/lib/common-javaFX-plugin.jar org.jetbrains.jps.cmdline.BuildMain 127.0.0.1 58192 cb15d722-c706-4bbc-87e2-80e6e2700ba8 /Users/jmcgrath/Library/Caches/IdeaIC2018.3/compile-server
 23429 ttys003    0:00.00 grep --color=auto --exclude-dir=.bzr --exclude-dir=CVS --exclude-dir=.git --exclude-dir=.hg --exclude-dir=.svn`, nil
@@ -1176,15 +1175,15 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 		Context("when unable to determine Java executable from cmdLineArgs and insufficient details present for fallback behavior", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{
-						process.Process{
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
 					}, nil
 				}
 
-				p.getCmdLineArgs = func(process.Process) (string, error) {
+				p.getCmdLineArgs = func(tasks.RunningProcess) (string, error) {
 					// unparseable java executable, insufficient details in cmdLineArgs
 					return `/home/duke/jeva HelloWorld -Djava.is.cool`, nil
 				}
@@ -1208,9 +1207,9 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 		Context("when cmdLine execution returns an error, but sufficient details present to determine Vendor and Version", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{
-						process.Process{
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
 					}, nil
@@ -1219,7 +1218,7 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 					return []byte(""), errors.New("Duke wuz here")
 				}
 
-				p.getCmdLineArgs = func(process.Process) (string, error) {
+				p.getCmdLineArgs = func(tasks.RunningProcess) (string, error) {
 					// parseable java executable (but we're going to get an error running it from the cmdExec mock)
 					// fallback available with valid cmdlineArgs for HotSpot and Java 1.8
 					return `/home/duke/java -Xmx700m -Djava.vm.name=Java HotSpot™ 64-Bit Server VM -Djava.version=1.8 -Djava.awt.headless=true -Djava.endorsed.dirs="" -Djdt.compiler.useSingleThread=true -Dpreload.project.path=/Users/jmcgrath/code/samsa -Dpreload.config.path=/Users/jmcgrath/Library/Preferences/IdeaIC2018.3/options -Dexternal.project.config=/Users/jmcgrath/Library/Caches/IdeaIC2018.3/external_build_system/samsa.48129cba -Dcompile.parallel=false -Drebuild.on.dependency.change=true -Djava.net.preferIPv4Stack=true -Dio.netty.initialSeedUniquifier=-768099212347918098 -Dfile.encoding=UTF-8 -Duser.language=en -Duser.country=US -Didea.paths.selector=IdeaIC2018.3 -Didea.home.path=/Applications/IntelliJ IDEA CE.app/Contents -Didea.config.path=/Users/jmcgrath/Library/Preferences/IdeaIC2018.3 -Didea.plugins.path=/Users/jmcgrath/Library/Application Support/IdeaIC2018.3 -Djps.log.dir=/Users/jmcgrath/Library/Logs/IdeaIC2018.3/build-log -Djps.fallback.jdk.home=/Applications/IntelliJ IDEA CE.app/Contents/jdk/Contents/Home/jre -Djps.fallback.jdk.version=1.8.0_152-release -Dio.netty.noUnsafe=true -Djava.io.tmpdir=/Users/jmcgrath/Library/Caches/IdeaIC2018.3/compile-server/samsa_80e1e690/_temp_ -Djps.backward.ref.index.builder=true -Dkotlin.incremental.compilation=true -Dkotlin.daemon.enabled -Dkotlin.daemon.client.alive.path="/var/folders/8t/zvmxntvd4w7_j6flmkcj4jmr0000gn/T/kotlin-idea-3860326101882290058-is-running" -classpath /Applications/IntelliJ IDEA CE.app/Contents/lib/jps-launcher.jar:/Library/Java/JavaVirtualMachines/jdk1.8.0_152.jdk/Contents/Home/lib/tools.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/optimizedFileManager.jar org.jetbrains.jps.cmdline.Launcher /Applications/IntelliJ IDEA CE.app/Contents/lib/util.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jna-platform.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-aether-provider-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-builder-support-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-util-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/log4j.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/lz4-1.3.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-model-builder-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/asm-all-7.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/commons-codec-1.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/nanoxml-2.2.3.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-repository-metadata-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-transport-file-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/trove4j.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/plexus-utils-3.0.22.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/httpcore-4.4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-codec-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jps-builders.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jna.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-buffer-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/plexus-component-annotations-1.6.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-dependency-resolver.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/guava-25.1-jre.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-artifact-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-api-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/commons-lang3-3.4.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/maven-model-3.3.9.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-impl-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/httpclient-4.5.6.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/idea_rt.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/resources_en.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-resolver-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/plexus-interpolation-1.21.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/oro-2.0.8.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/protobuf-java-3.4.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jps-model.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-transport-http-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-connector-basic-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/platform-api.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/forms-1.1-preview.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/slf4j-api-1.7.25.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jps-builders-6.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/jdom.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-transport-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/aether-spi-1.1.0.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/annotations.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/commons-logging-1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/javac2.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/netty-common-4.1.30.Final.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-impldep-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/ant/lib/ant.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/groovy-all-2.4.15.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/gson-2.8.5.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/slf4j-api-1.7.25.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/slf4j-log4j12-1.7.25.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/gson-2.8.5.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jarutils.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/guava-25.1-jre.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/manifest-merger-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/sdk-common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-model-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-test-api-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/ddmlib-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/repository-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/gson-2.8.5.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jarutils.jar:/Applications/IntelliJ IDEA CE.app/Contents/lib/guava-25.1-jre.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/manifest-merger-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/sdk-common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-model-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/builder-test-api-3.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/ddmlib-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/repository-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-api-4.10.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/ant/lib/ant-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/uiDesigner/lib/jps/ui-designer-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/IntelliLang/lib/intellilang-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Groovy/lib/groovy-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Groovy/lib/groovy-rt-constants.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/eclipse/lib/eclipse-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/eclipse/lib/common-eclipse-util.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/maven/lib/maven-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/gradle/lib/gradle-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/devkit/lib/devkit-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jps/android-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/android-common.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/build-common.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/android-rt.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/sdklib.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/common-26.1.2.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/jarutils.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/android/lib/layoutlib-api.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/jps/kotlin-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/kotlin-stdlib.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/kotlin-reflect.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/kotlin-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/android-extensions-ide.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/Kotlin/lib/android-extensions-compiler.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/javaFX/lib/javaFX-jps-plugin.jar:/Applications/IntelliJ IDEA CE.app/Contents/plugins/javaFX/lib/common-javaFX-plugin.jar org.jetbrains.jps.cmdline.BuildMain 127.0.0.1 58192 cb15d722-c706-4bbc-87e2-80e6e2700ba8 /Users/jmcgrath/Library/Caches/IdeaIC2018.3/compile-server
@@ -1244,9 +1243,9 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 		Context("when cmdLine execution returns an error, and insufficient details present to determine Vendor and Version", func() {
 			BeforeEach(func() {
 				options = tasks.Options{}
-				p.findProcessByName = func(string) ([]process.Process, error) {
-					return []process.Process{
-						process.Process{
+				p.findProcessByName = func(string) ([]tasks.RunningProcess, error) {
+					return []tasks.RunningProcess{
+						tasks.RunningProcess{
 							Pid: 1,
 						},
 					}, nil
@@ -1255,7 +1254,7 @@ var _ = Describe("JavaJVMVendorsVersions", func() {
 					return []byte(""), errors.New("Duke wuz here")
 				}
 
-				p.getCmdLineArgs = func(process.Process) (string, error) {
+				p.getCmdLineArgs = func(tasks.RunningProcess) (string, error) {
 					// parseable java executable (but we're going to get an error running it from the cmdExec mock)
 					// fallback available but missing -Djava.vm.name
 					return `/home/duke/java -Xmx700m -Djava.version=1.8 -Djava.awt.headless=true -Djava.endorsed.dirs="" --exclude-dir=.svn`, nil
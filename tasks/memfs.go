@@ -0,0 +1,231 @@
+package tasks
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MemFS is an in-memory FileSystem for tests, so a task's test can declare the files of the host it diagnoses:
+//
+//	defer tasks.UseFileSystem(tasks.NewMemFS(map[string]string{
+//		"/etc/newrelic/newrelic.cfg": "pidfile=/var/run/newrelic-daemon.pid",
+//		"/var/log/newrelic/":         "",
+//	}))()
+type MemFS struct {
+	entries map[string]*memEntry
+}
+
+type memEntry struct {
+	name    string
+	data    []byte
+	mode    os.FileMode
+	modTime time.Time
+	target  string
+}
+
+// memModTime is the modification time of the entries of a MemFS unless it is changed with Chtimes
+var memModTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewMemFS creates a MemFS holding the given files and their contents. Paths ending with a slash are empty directories.
+// The parent directories of every path are created
+func NewMemFS(files map[string]string) *MemFS {
+	m := &MemFS{entries: make(map[string]*memEntry)}
+	for path, content := range files {
+		if strings.HasSuffix(filepath.ToSlash(path), "/") {
+			m.mkdirAll(filepath.Clean(path))
+			continue
+		}
+		m.add(filepath.Clean(path), &memEntry{data: []byte(content), mode: 0644})
+	}
+	return m
+}
+
+// Symlink creates newname as a symbolic link to oldname
+func (m *MemFS) Symlink(oldname, newname string) {
+	m.add(filepath.Clean(newname), &memEntry{target: oldname, mode: os.ModeSymlink | 0777})
+}
+
+// Chtimes changes the modification time of a file
+func (m *MemFS) Chtimes(name string, modTime time.Time) error {
+	entry, ok := m.entries[filepath.Clean(name)]
+	if !ok {
+		return &os.PathError{Op: "chtimes", Path: name, Err: os.ErrNotExist}
+	}
+	entry.modTime = modTime
+	return nil
+}
+
+func (m *MemFS) add(path string, entry *memEntry) {
+	entry.name = filepath.Base(path)
+	entry.modTime = memModTime
+	m.mkdirAll(filepath.Dir(path))
+	m.entries[path] = entry
+}
+
+func (m *MemFS) mkdirAll(path string) {
+	for {
+		if _, ok := m.entries[path]; ok {
+			return
+		}
+		m.entries[path] = &memEntry{name: filepath.Base(path), mode: os.ModeDir | 0755, modTime: memModTime}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return
+		}
+		path = parent
+	}
+}
+
+// resolve follows the symlinks in every element of the path, and in the last one too if followLast is set
+func (m *MemFS) resolve(name string, followLast bool) (string, *memEntry, error) {
+	path := filepath.Clean(name)
+	for links := 0; links <= maxSymlinks; links++ {
+		resolved, entry, restart := m.resolveOnce(path, followLast)
+		if !restart {
+			if entry == nil {
+				return resolved, nil, os.ErrNotExist
+			}
+			return resolved, entry, nil
+		}
+		path = resolved
+	}
+	return "", nil, errors.New("too many links")
+}
+
+// resolveOnce walks the path up to the first symlink to follow and returns the path with that link replaced by its target
+func (m *MemFS) resolveOnce(path string, followLast bool) (string, *memEntry, bool) {
+	elements := strings.Split(path, string(filepath.Separator))
+	current := ""
+	for i, element := range elements {
+		if i == 0 && element == "" {
+			current = string(filepath.Separator)
+			continue
+		}
+		current = filepath.Join(current, element)
+		entry, ok := m.entries[current]
+		if !ok {
+			return current, nil, false
+		}
+		last := i == len(elements)-1
+		if entry.mode&os.ModeSymlink != 0 && (!last || followLast) {
+			target := entry.target
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(current), target)
+			}
+			return filepath.Join(append([]string{target}, elements[i+1:]...)...), nil, true
+		}
+		if last {
+			return current, entry, false
+		}
+		if !entry.mode.IsDir() {
+			return current, nil, false
+		}
+	}
+	return current, m.entries[current], false
+}
+
+func (m *MemFS) entry(op string, name string, followLast bool) (string, *memEntry, error) {
+	path, entry, err := m.resolve(name, followLast)
+	if err != nil {
+		return "", nil, &os.PathError{Op: op, Path: name, Err: err}
+	}
+	return path, entry, nil
+}
+
+// Open opens a file for reading
+func (m *MemFS) Open(name string) (File, error) {
+	_, entry, err := m.entry("open", name, true)
+	if err != nil {
+		return nil, err
+	}
+	return &memFile{Reader: bytes.NewReader(entry.data), info: entry.info()}, nil
+}
+
+// Stat returns the FileInfo of a file, following symlinks
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	_, entry, err := m.entry("stat", name, true)
+	if err != nil {
+		return nil, err
+	}
+	return entry.info(), nil
+}
+
+// Lstat returns the FileInfo of a file without following a symlink in its last element
+func (m *MemFS) Lstat(name string) (os.FileInfo, error) {
+	_, entry, err := m.entry("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+	return entry.info(), nil
+}
+
+// ReadDir lists the entries of a directory sorted by name
+func (m *MemFS) ReadDir(name string) ([]os.FileInfo, error) {
+	path, entry, err := m.entry("readdir", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.mode.IsDir() {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	var infos []os.FileInfo
+	for childPath, child := range m.entries {
+		if childPath != path && filepath.Dir(childPath) == path {
+			infos = append(infos, child.info())
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// Readlink returns the target of a symlink
+func (m *MemFS) Readlink(name string) (string, error) {
+	_, entry, err := m.entry("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	if entry.mode&os.ModeSymlink == 0 {
+		return "", &os.PathError{Op: "readlink", Path: name, Err: errors.New("invalid argument")}
+	}
+	return entry.target, nil
+}
+
+// EvalSymlinks returns the path after following every symlink in it
+func (m *MemFS) EvalSymlinks(name string) (string, error) {
+	path, _, err := m.entry("lstat", name, true)
+	return path, err
+}
+
+func (e *memEntry) info() os.FileInfo {
+	return memFileInfo{entry: e}
+}
+
+type memFileInfo struct {
+	entry *memEntry
+}
+
+func (i memFileInfo) Name() string       { return i.entry.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.entry.data)) }
+func (i memFileInfo) Mode() os.FileMode  { return i.entry.mode }
+func (i memFileInfo) ModTime() time.Time { return i.entry.modTime }
+func (i memFileInfo) IsDir() bool        { return i.entry.mode.IsDir() }
+func (i memFileInfo) Sys() interface{}   { return nil }
+
+type memFile struct {
+	*bytes.Reader
+	info os.FileInfo
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *memFile) Close() error {
+	return nil
+}
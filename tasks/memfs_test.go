@@ -0,0 +1,123 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func newTestMemFS() *MemFS {
+	memFS := NewMemFS(map[string]string{
+		"/etc/newrelic-infra.yml":                      "license_key: abc123\n",
+		"/etc/newrelic-infra/integrations.d/nginx.yml": "integrations:\n  - name: nri-nginx\n",
+		"/opt/logs/newrelic-daemon.log":                "daemon started\n",
+		"/var/log/newrelic/":                           "",
+	})
+	memFS.Symlink("/opt/logs", "/var/log/nrlogs")
+	memFS.Symlink("../../../opt/logs/newrelic-daemon.log", "/var/log/newrelic/newrelic-daemon.log")
+	return memFS
+}
+
+func TestMemFS(t *testing.T) {
+	defer UseFileSystem(newTestMemFS())()
+
+	if content := ReadFile("/etc/newrelic-infra.yml"); content != "license_key: abc123\n" {
+		t.Errorf("ReadFile() = %q, want the content of the file", content)
+	}
+	if content := ReadFile("/var/log/nrlogs/newrelic-daemon.log"); content != "daemon started\n" {
+		t.Errorf("ReadFile() through a symlinked directory = %q, want the content of the file", content)
+	}
+	if content := ReadFile("/var/log/newrelic/newrelic-daemon.log"); content != "daemon started\n" {
+		t.Errorf("ReadFile() of a relative symlink = %q, want the content of the file", content)
+	}
+	if !FileExists("/var/log/newrelic") || FileExists("/var/log/missing.log") {
+		t.Error("FileExists() expected to find only the files of the in-memory filesystem")
+	}
+	if status := ValidatePath("/etc/newrelic-infra"); status.IsValid {
+		t.Error("ValidatePath() expected a directory to be invalid")
+	}
+	if status := ValidatePath("/etc/newrelic-infra.yml"); !status.IsValid {
+		t.Errorf("ValidatePath() = %+v, want a valid file", status)
+	}
+
+	info, err := FS.Lstat("/var/log/nrlogs")
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Lstat() = %v, %v, want a symlink", info, err)
+	}
+	if _, err := FS.Stat("/etc/newrelic-infra.yml/child"); !os.IsNotExist(err) {
+		t.Errorf("Stat() of a path below a file error = %v, want not exist", err)
+	}
+}
+
+func TestMemFS_FindFiles(t *testing.T) {
+	defer UseFileSystem(newTestMemFS())()
+
+	tests := []struct {
+		name     string
+		patterns []string
+		paths    []string
+		want     []string
+	}{
+		{
+			name:     "walks subdirectories",
+			patterns: []string{".+[.]y(a)?ml$"},
+			paths:    []string{"/etc"},
+			want:     []string{"/etc/newrelic-infra.yml", "/etc/newrelic-infra/integrations.d/nginx.yml"},
+		},
+		{
+			name:     "resolves a symlinked search path",
+			patterns: []string{"[.]log$"},
+			paths:    []string{"/var/log/nrlogs"},
+			want:     []string{"/opt/logs/newrelic-daemon.log"},
+		},
+		{
+			name:     "missing search path",
+			patterns: []string{".*"},
+			paths:    []string{"/var/db/newrelic-infra"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindFiles(tt.patterns, tt.paths)
+			sort.Strings(got)
+			var want []string
+			for _, path := range tt.want {
+				want = append(want, filepath.FromSlash(path))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("FindFiles() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMemFS_ReadDir(t *testing.T) {
+	memFS := newTestMemFS()
+	modTime := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	if err := memFS.Chtimes("/var/log/newrelic/newrelic-daemon.log", modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := memFS.ReadDir("/var/log")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	if want := []string{"newrelic", "nrlogs"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ReadDir() = %v, want %v", names, want)
+	}
+
+	info, err := memFS.Lstat("/var/log/newrelic/newrelic-daemon.log")
+	if err != nil || !info.ModTime().Equal(modTime) {
+		t.Errorf("Lstat() = %v, %v, want the time set with Chtimes", info, err)
+	}
+	if _, err := memFS.ReadDir("/etc/newrelic-infra.yml"); err == nil {
+		t.Error("ReadDir() of a file expected an error")
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
 )
 
 // RegisterWith - will register any plugins in this package
@@ -10,4 +11,6 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 	log.Debug("Registering Node/Config/*")
 
 	registrationFunc(NodeConfigAgent{}, true)
+	registrationFunc(config.NewValidateSettingsTask("Node/Config/ValidateSettings", "Node/Config/Agent", "node"), true)
+	config.RegisterSettingsSpec("node")
 }
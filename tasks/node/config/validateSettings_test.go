@@ -0,0 +1,73 @@
+package config
+
+import (
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node/Config/ValidateSettings", func() {
+	p := config.NewValidateSettingsTask("Node/Config/ValidateSettings", "Node/Config/Agent", "node")
+
+	Describe("Execute()", func() {
+		var (
+			result   tasks.Result
+			options  tasks.Options
+			upstream map[string]tasks.Result
+		)
+
+		JustBeforeEach(func() {
+			result = p.Execute(options, upstream)
+		})
+
+		Context("when no config file was found", func() {
+			BeforeEach(func() {
+				options = tasks.Options{}
+				upstream = map[string]tasks.Result{
+					"Node/Config/Agent": {Status: tasks.None},
+				}
+			})
+			It("should return None", func() {
+				Expect(result.Status).To(Equal(tasks.None))
+			})
+		})
+
+		Context("when validating newrelic.js with the builtin spec", func() {
+			BeforeEach(func() {
+				options = tasks.Options{}
+				validated := config.BaseConfigValidate{}.Execute(options, map[string]tasks.Result{
+					"Base/Config/Collect": {
+						Status:  tasks.Success,
+						Payload: []config.ConfigElement{{FileName: "newrelic.js", FilePath: "../../fixtures/node/"}},
+					},
+				})
+				upstream = map[string]tasks.Result{
+					"Node/Config/Agent": {Status: tasks.Success, Payload: validated.Payload},
+				}
+			})
+			It("should return Success", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+				validations := result.Payload.([]config.SettingsValidation)
+				Expect(validations).To(HaveLen(1))
+				var problems []string
+				for _, problem := range validations[0].Problems {
+					problems = append(problems, string(problem.Status)+" "+problem.Key)
+				}
+				Expect(problems).To(Equal([]string(nil)))
+			})
+		})
+
+		Context("when the spec file given with the specFile option doesn't exist", func() {
+			BeforeEach(func() {
+				options = tasks.Options{Options: map[string]string{"specFile": "../../fixtures/node/missing_spec.yml"}}
+				upstream = map[string]tasks.Result{
+					"Node/Config/Agent": {Status: tasks.Success, Payload: []config.ValidateElement{{}}},
+				}
+			})
+			It("should return Error", func() {
+				Expect(result.Status).To(Equal(tasks.Error))
+			})
+		})
+	})
+})
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/php/env"
+)
+
+// PHPIniSetting - a value set in an ini file
+type PHPIniSetting struct {
+	Value string
+	File  string
+	Line  int
+}
+
+func (s PHPIniSetting) location() string {
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
+
+// PHPSAPISettings - the New Relic settings a PHP SAPI runs with
+type PHPSAPISettings struct {
+	SAPI        string
+	Version     string `json:",omitempty"`
+	AgentLoaded bool
+	// Settings are the newrelic.* settings, with the value of the file loaded last
+	Settings map[string]PHPIniSetting
+	Problems []string
+}
+
+// PHPConfigSAPIs - compares the New Relic settings of the ini files each PHP SAPI loads
+type PHPConfigSAPIs struct {
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p PHPConfigSAPIs) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("PHP/Config/SAPIs")
+}
+
+// Explain - Returns the help text for each individual task
+func (p PHPConfigSAPIs) Explain() string {
+	return "Check each PHP SAPI loads the New Relic agent, and for conflicting newrelic.* settings across its ini files"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p PHPConfigSAPIs) Dependencies() []string {
+	return []string{
+		"PHP/Env/SAPIs",
+	}
+}
+
+// Execute - The core work within each task
+func (p PHPConfigSAPIs) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	sapis, ok := upstream["PHP/Env/SAPIs"].Payload.([]env.PHPSAPI)
+	if !ok || len(sapis) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No PHP SAPIs were found",
+		}
+	}
+
+	var results []PHPSAPISettings
+	for _, sapi := range sapis {
+		results = append(results, checkSAPISettings(sapi))
+	}
+
+	var problems []string
+	for _, result := range results {
+		for _, problem := range result.Problems {
+			problems = append(problems, "\t"+sapiLabel(result)+": "+problem)
+		}
+	}
+	problems = append(problems, compareEnabled(results)...)
+
+	if len(problems) > 0 {
+		return tasks.Result{
+			Status:  tasks.Warning,
+			Summary: "Found differences in how the PHP SAPIs load the New Relic agent:\n" + strings.Join(problems, "\n"),
+			URL:     "https://docs.newrelic.com/docs/agents/php-agent/configuration/php-agent-configuration",
+			Payload: results,
+		}
+	}
+	return tasks.Result{
+		Status:  tasks.Success,
+		Summary: fmt.Sprintf("The New Relic agent is loaded with consistent settings by %d PHP SAPI(s)", len(results)),
+		Payload: results,
+	}
+}
+
+func sapiLabel(settings PHPSAPISettings) string {
+	if settings.Version != "" {
+		return settings.SAPI + " " + settings.Version
+	}
+	return settings.SAPI
+}
+
+// checkSAPISettings - reads the ini files of a SAPI in load order, reporting settings set by more than one of them
+func checkSAPISettings(sapi env.PHPSAPI) PHPSAPISettings {
+	result := PHPSAPISettings{SAPI: sapi.SAPI, Version: sapi.Version, Settings: make(map[string]PHPIniSetting)}
+	files := sapi.IniFiles
+	if sapi.LoadedIni != "" {
+		files = append([]string{sapi.LoadedIni}, files...)
+	}
+
+	set := make(map[string][]PHPIniSetting)
+	for _, file := range files {
+		content, err := tasks.ReadFileBytes(file)
+		if err != nil {
+			log.Debug("Unable to read", file, err)
+			continue
+		}
+		for _, setting := range parsePHPIni(file, content) {
+			if isNewRelicExtension(setting.key, setting.Value) {
+				result.AgentLoaded = true
+			}
+			if strings.HasPrefix(setting.key, "newrelic.") {
+				set[setting.key] = append(set[setting.key], setting.PHPIniSetting)
+			}
+		}
+	}
+
+	if !result.AgentLoaded {
+		result.Problems = append(result.Problems, "newrelic.so isn't loaded by any of its ini files")
+	}
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values := set[key]
+		result.Settings[key] = values[len(values)-1]
+		if len(values) < 2 {
+			continue
+		}
+		var locations []string
+		conflicting := false
+		for _, value := range values {
+			locations = append(locations, fmt.Sprintf("'%s' in %s", value.Value, value.location()))
+			conflicting = conflicting || value.Value != values[0].Value
+		}
+		if conflicting {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s is set to %s, the last one wins", key, strings.Join(locations, ", ")))
+		} else {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s is set more than once, to %s", key, strings.Join(locations, ", ")))
+		}
+	}
+	return result
+}
+
+// compareEnabled - reports newrelic.enabled when the SAPIs loading the agent don't agree on it
+func compareEnabled(results []PHPSAPISettings) []string {
+	var values []string
+	distinct := make(map[bool]bool)
+	for _, result := range results {
+		if !result.AgentLoaded {
+			continue
+		}
+		setting, ok := result.Settings["newrelic.enabled"]
+		enabled := !ok || isIniTrue(setting.Value)
+		distinct[enabled] = true
+		source := "default"
+		if ok {
+			source = setting.location()
+		}
+		values = append(values, fmt.Sprintf("\t\t%s: %t (%s)", sapiLabel(result), enabled, source))
+	}
+	if len(distinct) < 2 {
+		return nil
+	}
+	return append([]string{"\tnewrelic.enabled differs between the SAPIs:"}, values...)
+}
+
+type phpIniEntry struct {
+	key string
+	PHPIniSetting
+}
+
+// parsePHPIni - the key = value lines of an ini file, with quotes and trailing comments removed
+func parsePHPIni(file string, content []byte) []phpIniEntry {
+	var entries []phpIniEntry
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "[") {
+			continue
+		}
+		parts := strings.SplitN(text, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+			if end := strings.Index(value[1:], value[:1]); end >= 0 {
+				value = value[1 : end+1]
+			}
+		} else if comment := strings.Index(value, ";"); comment >= 0 {
+			value = strings.TrimSpace(value[:comment])
+		}
+		entries = append(entries, phpIniEntry{
+			key:           strings.TrimSpace(parts[0]),
+			PHPIniSetting: PHPIniSetting{Value: value, File: file, Line: line},
+		})
+	}
+	return entries
+}
+
+func isNewRelicExtension(key string, value string) bool {
+	if key != "extension" && key != "zend_extension" {
+		return false
+	}
+	name := filepath.Base(value)
+	return name == "newrelic.so" || name == "newrelic"
+}
+
+func isIniTrue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "on", "yes", "true":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/php/env"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PHP/Config/SAPIs", func() {
+	var p PHPConfigSAPIs
+
+	Describe("Execute()", func() {
+		var (
+			result tasks.Result
+			files  map[string]string
+			sapis  []env.PHPSAPI
+		)
+
+		BeforeEach(func() {
+			files = map[string]string{
+				"/etc/php/7.4/cli/php.ini":                "[PHP]\nmemory_limit = -1\n",
+				"/etc/php/7.4/cli/conf.d/20-newrelic.ini": "extension = \"newrelic.so\"\n[newrelic]\nnewrelic.license = \"abc\"\nnewrelic.appname = \"Storefront\" ; the app\n",
+				"/etc/php/7.4/fpm/php.ini":                "[PHP]\n",
+				"/etc/php/7.4/fpm/conf.d/20-newrelic.ini": "extension=/usr/lib/php/20190902/newrelic.so\nnewrelic.license = \"abc\"\nnewrelic.appname = \"Storefront\"\n",
+			}
+			sapis = []env.PHPSAPI{
+				{SAPI: "cli", LoadedIni: "/etc/php/7.4/cli/php.ini", IniFiles: []string{"/etc/php/7.4/cli/conf.d/20-newrelic.ini"}},
+				{SAPI: "fpm", LoadedIni: "/etc/php/7.4/fpm/php.ini", IniFiles: []string{"/etc/php/7.4/fpm/conf.d/20-newrelic.ini"}},
+			}
+		})
+
+		JustBeforeEach(func() {
+			defer tasks.UseFileSystem(tasks.NewMemFS(files))()
+			result = p.Execute(tasks.Options{}, map[string]tasks.Result{
+				"PHP/Env/SAPIs": {Status: tasks.Success, Payload: sapis},
+			})
+		})
+
+		Context("when no SAPI was found", func() {
+			BeforeEach(func() {
+				sapis = nil
+			})
+			It("should return None", func() {
+				Expect(result.Status).To(Equal(tasks.None))
+			})
+		})
+
+		Context("when every SAPI loads the agent with the same settings", func() {
+			It("should return Success with the settings of each SAPI", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+				settings := result.Payload.([]PHPSAPISettings)
+				Expect(settings[0].AgentLoaded).To(BeTrue())
+				Expect(settings[0].Settings["newrelic.appname"]).To(Equal(PHPIniSetting{Value: "Storefront", File: "/etc/php/7.4/cli/conf.d/20-newrelic.ini", Line: 4}))
+			})
+		})
+
+		Context("when a SAPI doesn't load newrelic.so", func() {
+			BeforeEach(func() {
+				files["/etc/php/7.4/fpm/conf.d/20-newrelic.ini"] = ";extension=newrelic.so\n"
+			})
+			It("should return Warning naming the SAPI", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Summary).To(ContainSubstring("fpm: newrelic.so isn't loaded by any of its ini files"))
+			})
+		})
+
+		Context("when a setting is set in several files", func() {
+			BeforeEach(func() {
+				files["/etc/php/7.4/cli/php.ini"] = "[PHP]\nnewrelic.appname = \"Legacy\"\n"
+				files["/etc/php/7.4/fpm/php.ini"] = "[PHP]\nnewrelic.license = abc\n"
+			})
+			It("should report conflicting and duplicate values with their files", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				settings := result.Payload.([]PHPSAPISettings)
+				Expect(settings[0].Problems).To(Equal([]string{
+					"newrelic.appname is set to 'Legacy' in /etc/php/7.4/cli/php.ini:2, 'Storefront' in /etc/php/7.4/cli/conf.d/20-newrelic.ini:4, the last one wins",
+				}))
+				Expect(settings[1].Problems).To(Equal([]string{
+					"newrelic.license is set more than once, to 'abc' in /etc/php/7.4/fpm/php.ini:2, 'abc' in /etc/php/7.4/fpm/conf.d/20-newrelic.ini:2",
+				}))
+			})
+		})
+
+		Context("when newrelic.enabled differs between SAPIs", func() {
+			BeforeEach(func() {
+				files["/etc/php/7.4/fpm/conf.d/20-newrelic.ini"] += "newrelic.enabled = Off\n"
+			})
+			It("should return Warning listing the value of each SAPI", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Summary).To(ContainSubstring("newrelic.enabled differs between the SAPIs:\n\t\tcli: true (default)\n\t\tfpm: false (/etc/php/7.4/fpm/conf.d/20-newrelic.ini:4)"))
+			})
+		})
+	})
+})
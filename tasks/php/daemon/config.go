@@ -0,0 +1,336 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+)
+
+// daemonCfgFile is the config file of a daemon started outside of the agent
+const daemonCfgFile = "/etc/newrelic/newrelic.cfg"
+
+// defaultDaemonAddress is the socket the agent and the daemon use when no address is set
+const defaultDaemonAddress = "@newrelic"
+
+// PHPDaemonEndpoint - an address the agent connects to, or the daemon listens on, and where it was set
+type PHPDaemonEndpoint struct {
+	Address string
+	Source  string
+}
+
+// PHPDaemonAgentSettings - the daemon settings of a PHP agent ini file
+type PHPDaemonAgentSettings struct {
+	File    string
+	Address string
+	// DontLaunch is newrelic.daemon.dont_launch, which stops the agent from spawning a daemon
+	DontLaunch string `json:",omitempty"`
+}
+
+// PHPDaemonProcess - a running newrelic-daemon and the flags it was started with
+type PHPDaemonProcess struct {
+	Pid     int32
+	Address string
+	// ConfigFile is the file passed with -c, if any
+	ConfigFile string `json:",omitempty"`
+	// Spawned is true for daemons started by the agent, which pass --agent
+	Spawned bool
+}
+
+// PHPDaemonConfigInfo - the agent and daemon settings compared by PHP/Daemon/Config
+type PHPDaemonConfigInfo struct {
+	Agent     []PHPDaemonAgentSettings
+	Config    *PHPDaemonEndpoint `json:",omitempty"`
+	Daemons   []PHPDaemonProcess
+	Listening []string
+	Problems  []string
+}
+
+// PHPDaemonConfig - compares the daemon address the agent connects to with the daemon's newrelic.cfg, command line
+// and listening sockets
+type PHPDaemonConfig struct {
+	processFinder processFinderFunc
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p PHPDaemonConfig) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("PHP/Daemon/Config")
+}
+
+// Explain - Returns the help text for each individual task
+func (p PHPDaemonConfig) Explain() string {
+	return "Check the PHP agent and the newrelic-daemon are configured to use the same address"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p PHPDaemonConfig) Dependencies() []string {
+	return []string{
+		"PHP/Config/Agent",
+		"PHP/Daemon/Running",
+	}
+}
+
+// Execute - The core work within each task
+func (p PHPDaemonConfig) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	if upstream["PHP/Config/Agent"].Status != tasks.Success {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "PHP Agent was not detected on this host. Skipping daemon configuration check.",
+		}
+	}
+	validations, ok := upstream["PHP/Config/Agent"].Payload.([]config.ValidateElement)
+	if !ok {
+		return tasks.Result{
+			Status:  tasks.Error,
+			Summary: tasks.AssertionErrorSummary,
+		}
+	}
+
+	info := PHPDaemonConfigInfo{}
+	for _, validation := range validations {
+		file := validation.Config.FilePath + validation.Config.FileName
+		if filepath.Ext(file) != ".ini" {
+			continue
+		}
+		address := iniValue(validation.ParsedResult, "newrelic.daemon.address", "newrelic.daemon.port")
+		if address == "" {
+			address = defaultDaemonAddress
+		}
+		info.Agent = append(info.Agent, PHPDaemonAgentSettings{
+			File:       file,
+			Address:    address,
+			DontLaunch: iniValue(validation.ParsedResult, "newrelic.daemon.dont_launch"),
+		})
+	}
+	if content, err := tasks.ReadFileBytes(daemonCfgFile); err == nil {
+		info.Config = &PHPDaemonEndpoint{Address: cfgAddress(content), Source: daemonCfgFile}
+	}
+
+	processes, err := p.processFinder("newrelic-daemon")
+	if err != nil {
+		log.Debug("Error finding newrelic-daemon processes", err)
+	}
+	for _, process := range processes {
+		args, err := process.CmdlineSlice()
+		if err != nil {
+			log.Debug("Error reading the command line of", process.Pid, err)
+			continue
+		}
+		info.Daemons = append(info.Daemons, parseDaemonArgs(process.Pid, args))
+	}
+	if len(info.Daemons) > 0 {
+		info.Listening = listeningAddresses()
+	}
+
+	failed := checkDaemonAddresses(&info)
+	checkDaemonLaunch(&info)
+
+	if len(info.Problems) == 0 {
+		return tasks.Result{
+			Status:  tasks.Success,
+			Summary: "The PHP agent and the newrelic-daemon are configured with the same address",
+			Payload: info,
+		}
+	}
+	status := tasks.Warning
+	if failed {
+		status = tasks.Failure
+	}
+	return tasks.Result{
+		Status:  status,
+		Summary: "The PHP agent and the newrelic-daemon configurations don't agree:\n\t" + strings.Join(info.Problems, "\n\t"),
+		URL:     "https://docs.newrelic.com/docs/agents/php-agent/configuration/proxy-daemon-newreliccfg-settings",
+		Payload: info,
+	}
+}
+
+// checkDaemonAddresses - reports the addresses the agent would connect to that the daemon doesn't use, returning true
+// if there are any
+func checkDaemonAddresses(info *PHPDaemonConfigInfo) bool {
+	var daemonSide []PHPDaemonEndpoint
+	if info.Config != nil {
+		daemonSide = append(daemonSide, *info.Config)
+	}
+	for _, daemon := range info.Daemons {
+		// a daemon started with -c reads its address from the file, newrelic.cfg is already compared
+		if daemon.Address == "" && daemon.ConfigFile != "" {
+			if daemon.ConfigFile == daemonCfgFile && info.Config != nil {
+				continue
+			}
+			content, err := tasks.ReadFileBytes(daemon.ConfigFile)
+			if err != nil {
+				log.Debug("Error reading the config file of newrelic-daemon", daemon.Pid, err)
+				continue
+			}
+			daemonSide = append(daemonSide, PHPDaemonEndpoint{Address: cfgAddress(content), Source: fmt.Sprintf("%s, read by newrelic-daemon (pid %d),", daemon.ConfigFile, daemon.Pid)})
+			continue
+		}
+		address := daemon.Address
+		if address == "" {
+			address = defaultDaemonAddress
+		}
+		daemonSide = append(daemonSide, PHPDaemonEndpoint{Address: address, Source: fmt.Sprintf("the command line of newrelic-daemon (pid %d)", daemon.Pid)})
+	}
+
+	mismatch := false
+	for _, agent := range info.Agent {
+		for _, daemon := range daemonSide {
+			if normalizeDaemonAddress(agent.Address) != normalizeDaemonAddress(daemon.Address) {
+				info.Problems = append(info.Problems, fmt.Sprintf("%s sets the daemon address to '%s', but %s uses '%s'", agent.File, agent.Address, daemon.Source, daemon.Address))
+				mismatch = true
+			}
+		}
+		if len(info.Daemons) > 0 && !isListening(agent.Address, info.Listening) {
+			info.Problems = append(info.Problems, fmt.Sprintf("%s sets the daemon address to '%s', but nothing is listening on it", agent.File, agent.Address))
+			mismatch = true
+		}
+	}
+	return mismatch
+}
+
+// checkDaemonLaunch - reports an agent that will spawn its own daemon alongside a running one that was started
+// separately, or that may do so once a daemon is started from newrelic.cfg
+func checkDaemonLaunch(info *PHPDaemonConfigInfo) {
+	running := false
+	for _, daemon := range info.Daemons {
+		running = running || !daemon.Spawned
+	}
+	if !running && info.Config == nil {
+		return
+	}
+	for _, agent := range info.Agent {
+		// 3 stops the agent from launching the daemon from any SAPI
+		if agent.DontLaunch == "3" {
+			continue
+		}
+		if running {
+			info.Problems = append(info.Problems, fmt.Sprintf("%s doesn't set newrelic.daemon.dont_launch = 3, the agent will spawn its own daemon alongside the one started separately", agent.File))
+		} else {
+			info.Problems = append(info.Problems, fmt.Sprintf("%s doesn't set newrelic.daemon.dont_launch = 3, the agent may spawn its own daemon alongside one started from %s", agent.File, daemonCfgFile))
+		}
+	}
+}
+
+func iniValue(blob tasks.ValidateBlob, keys ...string) string {
+	for _, key := range keys {
+		if found := blob.FindKey(key); len(found) > 0 {
+			value := found[0].Value()
+			if comment := strings.Index(value, ";"); comment >= 0 {
+				value = value[:comment]
+			}
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// cfgAddress - the address or, for older daemons, the port set in newrelic.cfg
+func cfgAddress(content []byte) string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		values[strings.TrimSpace(parts[0])] = strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+	}
+	for _, key := range []string{"address", "port"} {
+		if values[key] != "" {
+			return values[key]
+		}
+	}
+	return defaultDaemonAddress
+}
+
+// parseDaemonArgs - reads the address and config file flags of a newrelic-daemon command line, e.g.
+//
+//	/usr/bin/newrelic-daemon --agent --pidfile /var/run/newrelic-daemon.pid --port @newrelic
+//	/usr/bin/newrelic-daemon -c /etc/newrelic/newrelic.cfg --pidfile /var/run/newrelic-daemon.pid
+func parseDaemonArgs(pid int32, args []string) PHPDaemonProcess {
+	daemon := PHPDaemonProcess{Pid: pid}
+	for i := 1; i < len(args); i++ {
+		name, value := args[i], ""
+		if parts := strings.SplitN(name, "=", 2); len(parts) == 2 && strings.HasPrefix(name, "--") {
+			name, value = parts[0], parts[1]
+		} else if i+1 < len(args) {
+			value = args[i+1]
+		}
+		switch name {
+		case "--agent":
+			daemon.Spawned = true
+		case "--address", "--port", "-p":
+			daemon.Address = value
+		case "-c", "--cfg":
+			daemon.ConfigFile = value
+		}
+	}
+	return daemon
+}
+
+// normalizeDaemonAddress - a bare port and a localhost address are the same TCP socket
+func normalizeDaemonAddress(address string) string {
+	if _, err := strconv.Atoi(address); err == nil {
+		return "localhost:" + address
+	}
+	if strings.HasPrefix(address, "127.0.0.1:") {
+		return "localhost:" + strings.TrimPrefix(address, "127.0.0.1:")
+	}
+	return address
+}
+
+var tcpListenLine = regexp.MustCompile(`^\s*\d+:\s+[0-9A-F]+:([0-9A-F]{4})\s+[0-9A-F]+:[0-9A-F]{4}\s+0A\s`)
+
+// listeningAddresses - the unix socket paths and TCP ports listened on, from /proc/net. Abstract unix sockets
+// start with @, TCP ports are returned as localhost:<port>
+func listeningAddresses() []string {
+	var addresses []string
+	if content, err := tasks.ReadFileBytes("/proc/net/unix"); err == nil {
+		for i, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			// the header has no path, and sockets that aren't bound have no path column
+			if i == 0 || len(fields) < 8 {
+				continue
+			}
+			addresses = append(addresses, fields[7])
+		}
+	}
+	for _, file := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		content, err := tasks.ReadFileBytes(file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			if match := tcpListenLine.FindStringSubmatch(line); match != nil {
+				port, _ := strconv.ParseInt(match[1], 16, 32)
+				addresses = append(addresses, fmt.Sprintf("localhost:%d", port))
+			}
+		}
+	}
+	return addresses
+}
+
+func isListening(address string, listening []string) bool {
+	address = normalizeDaemonAddress(address)
+	if host := strings.LastIndex(address, ":"); host > 0 && !strings.HasPrefix(address, "/") && !strings.HasPrefix(address, "@") {
+		// a TCP address listened on by any interface
+		address = "localhost:" + address[host+1:]
+	}
+	for _, socket := range listening {
+		if socket == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package daemon
+
+import (
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var procNetUnix = `Num       RefCount Protocol Flags    Type St Inode Path
+0000000000000000: 00000002 00000000 00010000 0001 01 23456 @newrelic
+0000000000000000: 00000003 00000000 00000000 0001 03 23457
+`
+
+var procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 34567 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F91 0100007F:C350 01 00000000:00000000 00:00000000 00000000     0        0 34568 1 0000000000000000 20 4 30 10 -1
+`
+
+func phpIni(settings map[string]string) config.ValidateElement {
+	blob := tasks.ValidateBlob{}
+	for key, value := range settings {
+		blob.Children = append(blob.Children, tasks.ValidateBlob{Key: key, RawValue: value})
+	}
+	return config.ValidateElement{
+		Config:       config.ConfigElement{FileName: "newrelic.ini", FilePath: "/etc/php/7.4/mods-available/"},
+		ParsedResult: blob,
+	}
+}
+
+var _ = Describe("PHP/Daemon/Config", func() {
+	var p PHPDaemonConfig
+
+	Describe("Execute()", func() {
+		var (
+			result    tasks.Result
+			files     map[string]string
+			processes string
+			ini       map[string]string
+		)
+
+		BeforeEach(func() {
+			p = PHPDaemonConfig{processFinder: tasks.FindProcessByName}
+			files = map[string]string{
+				"/proc/net/unix": procNetUnix,
+				"/proc/net/tcp":  procNetTCP,
+			}
+			processes = `
+processes:
+  - pid: 700
+    name: newrelic-daemon
+    cmdline: [/usr/bin/newrelic-daemon, --agent, --pidfile, /var/run/newrelic-daemon.pid, --port, "@newrelic"]
+`
+			ini = map[string]string{"newrelic.license": "abc"}
+		})
+
+		JustBeforeEach(func() {
+			source, err := tasks.NewFakeProcessSource([]byte(processes))
+			Expect(err).NotTo(HaveOccurred())
+			defer tasks.UseProcessSource(source)()
+			defer tasks.UseFileSystem(tasks.NewMemFS(files))()
+			result = p.Execute(tasks.Options{}, map[string]tasks.Result{
+				"PHP/Config/Agent":   {Status: tasks.Success, Payload: []config.ValidateElement{phpIni(ini)}},
+				"PHP/Daemon/Running": {Status: tasks.Success},
+			})
+		})
+
+		Context("when the PHP agent was not detected", func() {
+			It("should return None", func() {
+				result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"PHP/Config/Agent": {Status: tasks.None},
+				})
+				Expect(result.Status).To(Equal(tasks.None))
+			})
+		})
+
+		Context("when the agent spawned a daemon on the default socket", func() {
+			It("should return Success", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+				info := result.Payload.(PHPDaemonConfigInfo)
+				Expect(info.Daemons).To(Equal([]PHPDaemonProcess{{Pid: 700, Address: "@newrelic", Spawned: true}}))
+				Expect(info.Listening).To(Equal([]string{"@newrelic", "localhost:8080"}))
+			})
+		})
+
+		Context("when newrelic.cfg sets a port the agent doesn't use", func() {
+			BeforeEach(func() {
+				files["/etc/newrelic/newrelic.cfg"] = "# daemon settings\naddress=8080\n"
+				ini["newrelic.daemon.dont_launch"] = "3"
+				processes = `
+processes:
+  - pid: 700
+    name: newrelic-daemon
+    cmdline: [/usr/bin/newrelic-daemon, -c, /etc/newrelic/newrelic.cfg]
+`
+			})
+			It("should return Failure naming both addresses", func() {
+				Expect(result.Status).To(Equal(tasks.Failure))
+				Expect(result.Payload.(PHPDaemonConfigInfo).Problems).To(Equal([]string{
+					"/etc/php/7.4/mods-available/newrelic.ini sets the daemon address to '@newrelic', but /etc/newrelic/newrelic.cfg uses '8080'",
+				}))
+			})
+		})
+
+		Context("when the agent uses the TCP port of newrelic.cfg", func() {
+			BeforeEach(func() {
+				files["/etc/newrelic/newrelic.cfg"] = "port=\"8080\"\n"
+				ini["newrelic.daemon.address"] = "127.0.0.1:8080 ; local daemon"
+				ini["newrelic.daemon.dont_launch"] = "3"
+				processes = `
+processes:
+  - pid: 700
+    name: newrelic-daemon
+    cmdline: [/usr/bin/newrelic-daemon, -c, /etc/newrelic/newrelic.cfg]
+`
+			})
+			It("should return Success", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+			})
+		})
+
+		Context("when the daemon reads a config file of its own", func() {
+			BeforeEach(func() {
+				files["/etc/newrelic/newrelic.cfg"] = "address=@newrelic\n"
+				files["/opt/newrelic/daemon.cfg"] = "address=/tmp/.newrelic.sock\n"
+				ini["newrelic.daemon.dont_launch"] = "3"
+				processes = `
+processes:
+  - pid: 700
+    name: newrelic-daemon
+    cmdline: [/usr/bin/newrelic-daemon, -c, /opt/newrelic/daemon.cfg]
+`
+			})
+			It("should return Failure naming the file it reads", func() {
+				Expect(result.Status).To(Equal(tasks.Failure))
+				Expect(result.Payload.(PHPDaemonConfigInfo).Problems).To(Equal([]string{
+					"/etc/php/7.4/mods-available/newrelic.ini sets the daemon address to '@newrelic', but /opt/newrelic/daemon.cfg, read by newrelic-daemon (pid 700), uses '/tmp/.newrelic.sock'",
+				}))
+			})
+		})
+
+		Context("when nothing listens on the address of a running daemon", func() {
+			BeforeEach(func() {
+				ini["newrelic.daemon.address"] = "/tmp/.newrelic.sock"
+				processes = `
+processes:
+  - pid: 700
+    name: newrelic-daemon
+    cmdline: [/usr/bin/newrelic-daemon, --agent, --address=/tmp/.newrelic.sock]
+`
+			})
+			It("should return Failure", func() {
+				Expect(result.Status).To(Equal(tasks.Failure))
+				Expect(result.Summary).To(ContainSubstring("sets the daemon address to '/tmp/.newrelic.sock', but nothing is listening on it"))
+			})
+		})
+
+		Context("when newrelic.cfg exists and the agent may launch its own daemon", func() {
+			BeforeEach(func() {
+				files["/etc/newrelic/newrelic.cfg"] = "address=@newrelic\n"
+				processes = "processes: []\n"
+			})
+			It("should return Warning about a possible conflict", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Payload.(PHPDaemonConfigInfo).Problems).To(Equal([]string{
+					"/etc/php/7.4/mods-available/newrelic.ini doesn't set newrelic.daemon.dont_launch = 3, the agent may spawn its own daemon alongside one started from /etc/newrelic/newrelic.cfg",
+				}))
+			})
+		})
+
+		Context("when a daemon started separately is running and the agent may launch its own", func() {
+			BeforeEach(func() {
+				processes = `
+processes:
+  - pid: 700
+    name: newrelic-daemon
+    cmdline: [/usr/bin/newrelic-daemon, --pidfile, /var/run/newrelic-daemon.pid]
+`
+			})
+			It("should return Warning about the conflict", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Payload.(PHPDaemonConfigInfo).Problems).To(Equal([]string{
+					"/etc/php/7.4/mods-available/newrelic.ini doesn't set newrelic.daemon.dont_launch = 3, the agent will spawn its own daemon alongside the one started separately",
+				}))
+			})
+		})
+	})
+})
+
+var _ = Describe("parseDaemonArgs()", func() {
+	It("should read the flags in both forms", func() {
+		Expect(parseDaemonArgs(1, []string{"newrelic-daemon", "--port=9000", "--cfg", "/opt/newrelic.cfg"})).To(Equal(
+			PHPDaemonProcess{Pid: 1, Address: "9000", ConfigFile: "/opt/newrelic.cfg"},
+		))
+	})
+})
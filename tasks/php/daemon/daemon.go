@@ -13,4 +13,7 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 		processFinder:     tasks.FindProcessByName,
 		fileExistsChecker: tasks.FileExists,
 	}, true)
+	registrationFunc(PHPDaemonConfig{
+		processFinder: tasks.FindProcessByName,
+	}, true)
 }
@@ -3,7 +3,6 @@ package daemon
 import (
 	"fmt"
 
-	"github.com/shirou/gopsutil/process"
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
@@ -14,7 +13,7 @@ type PHPDaemonRunning struct {
 	fileExistsChecker fileExistsCheckerFunc
 }
 
-type processFinderFunc func(string) ([]process.Process, error)
+type processFinderFunc func(string) ([]tasks.RunningProcess, error)
 type fileExistsCheckerFunc func(string) bool
 
 type PHPDaemonInfo struct {
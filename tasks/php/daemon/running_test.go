@@ -8,7 +8,6 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/shirou/gopsutil/process"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
 
@@ -17,37 +16,37 @@ func TestPHPDaemonRunning(t *testing.T) {
 	RunSpecs(t, "PHP/Daemon/Running test suite")
 }
 
-// type processFinderFunc func(string) ([]process.Process, error)
+// type processFinderFunc func(string) ([]tasks.RunningProcess, error)
 // type fileExistsCheckerFunc func(string) bool
 
-func mockProcessFinderZeroDaemon(name string) ([]process.Process, error) {
-	return []process.Process{}, nil
+func mockProcessFinderZeroDaemon(name string) ([]tasks.RunningProcess, error) {
+	return []tasks.RunningProcess{}, nil
 
 }
 
-func mockProcessFinderOneDaemon(name string) ([]process.Process, error) {
-	return []process.Process{
-		process.Process{Pid: 1},
+func mockProcessFinderOneDaemon(name string) ([]tasks.RunningProcess, error) {
+	return []tasks.RunningProcess{
+		tasks.RunningProcess{Pid: 1},
 	}, nil
 }
 
-func mockProcessFinderTwoDaemon(name string) ([]process.Process, error) {
-	return []process.Process{
-		process.Process{Pid: 1},
-		process.Process{Pid: 2},
+func mockProcessFinderTwoDaemon(name string) ([]tasks.RunningProcess, error) {
+	return []tasks.RunningProcess{
+		tasks.RunningProcess{Pid: 1},
+		tasks.RunningProcess{Pid: 2},
 	}, nil
 }
 
-func mockProcessFinderThreeDaemon(name string) ([]process.Process, error) {
-	return []process.Process{
-		process.Process{Pid: 1},
-		process.Process{Pid: 2},
-		process.Process{Pid: 3},
+func mockProcessFinderThreeDaemon(name string) ([]tasks.RunningProcess, error) {
+	return []tasks.RunningProcess{
+		tasks.RunningProcess{Pid: 1},
+		tasks.RunningProcess{Pid: 2},
+		tasks.RunningProcess{Pid: 3},
 	}, nil
 }
 
-func mockProcessFinderError(name string) ([]process.Process, error) {
-	return []process.Process{}, errors.New("Couldn't find daemon")
+func mockProcessFinderError(name string) ([]tasks.RunningProcess, error) {
+	return []tasks.RunningProcess{}, errors.New("Couldn't find daemon")
 }
 
 func mockFileExistsCheckerTrue(filename string) bool {
@@ -0,0 +1,224 @@
+package env
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+// PHPSAPI - the ini files a PHP server API loads, in the order it loads them
+type PHPSAPI struct {
+	// SAPI is the server API, e.g. cli, fpm or apache2
+	SAPI string
+	// Version is the PHP version of a Debian style /etc/php/<version>/<sapi> layout
+	Version string `json:",omitempty"`
+	// Source is the command run to find the files, or the directory they were found in
+	Source    string
+	LoadedIni string
+	ScanDir   string
+	IniFiles  []string
+}
+
+// phpFpmBinaryDirs are searched for php-fpm binaries, which are often versioned, e.g. php-fpm7.4
+var phpFpmBinaryDirs = []string{"/usr/sbin", "/usr/local/sbin"}
+
+var phpFpmBinary = regexp.MustCompile(`^php-fpm[0-9.]*$`)
+
+// phpSAPIDirs are the per SAPI config directories of the Debian and Ubuntu packages, in /etc/php/<version>/
+var phpSAPIDirs = []string{"apache2", "fpm", "cgi", "cli", "embed"}
+
+// PHPEnvSAPIs - finds the ini files each installed PHP SAPI loads, since php-fpm and mod_php often load a
+// different set than the CLI
+type PHPEnvSAPIs struct {
+	cmdExec tasks.CmdExecFunc
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p PHPEnvSAPIs) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("PHP/Env/SAPIs")
+}
+
+// Explain - Returns the help text for each individual task
+func (p PHPEnvSAPIs) Explain() string {
+	return "Collect the ini files loaded by each PHP SAPI (CLI, FPM, Apache)"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p PHPEnvSAPIs) Dependencies() []string {
+	return []string{
+		"PHP/Config/Agent",
+		"PHP/Env/PHPinfoCLI",
+	}
+}
+
+// Execute - The core work within each task
+func (p PHPEnvSAPIs) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	if upstream["PHP/Config/Agent"].Status != tasks.Success {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "PHP Agent was not detected on this host. Skipping PHP SAPI check.",
+		}
+	}
+
+	var sapis []PHPSAPI
+	if phpInfo, ok := upstream["PHP/Env/PHPinfoCLI"].Payload.(string); ok && upstream["PHP/Env/PHPinfoCLI"].Status == tasks.Success {
+		sapis = append(sapis, parsePHPInfoSAPI(phpInfo, "php -i"))
+	}
+	for _, binary := range findPHPFpmBinaries() {
+		output, err := p.cmdExec(binary, "-i")
+		if err != nil {
+			log.Debug("Error running", binary, "-i:", err)
+			continue
+		}
+		sapis = append(sapis, parsePHPInfoSAPI(string(output), binary+" -i"))
+	}
+	sapis = append(sapis, findSAPIDirs(sapis)...)
+
+	if len(sapis) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No PHP SAPIs were found",
+		}
+	}
+	var found []string
+	for _, sapi := range sapis {
+		found = append(found, sapi.SAPI+" ("+sapi.Source+")")
+	}
+	return tasks.Result{
+		Status:  tasks.Success,
+		Summary: "Found the ini files of the PHP SAPIs: " + strings.Join(found, ", "),
+		Payload: sapis,
+	}
+}
+
+// parsePHPInfoSAPI - reads the server API and its ini files from the text output of php -i, where the additional ini
+// files are listed over several lines:
+//
+//	Additional .ini files parsed => /etc/php/7.4/cli/conf.d/10-opcache.ini,
+//	/etc/php/7.4/cli/conf.d/20-newrelic.ini
+func parsePHPInfoSAPI(output string, source string) PHPSAPI {
+	sapi := PHPSAPI{Source: source}
+	additional := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		parts := strings.SplitN(line, "=>", 2)
+		if len(parts) != 2 {
+			if additional && line != "" {
+				sapi.IniFiles = append(sapi.IniFiles, iniFileList(line)...)
+				continue
+			}
+			additional = false
+			continue
+		}
+		additional = false
+		name, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if value == "(none)" {
+			value = ""
+		}
+		switch name {
+		case "Server API":
+			sapi.SAPI = sapiName(value)
+		case "Loaded Configuration File":
+			sapi.LoadedIni = value
+		case "Scan this dir for additional .ini files":
+			sapi.ScanDir = value
+		case "Additional .ini files parsed":
+			sapi.IniFiles = append(sapi.IniFiles, iniFileList(value)...)
+			additional = true
+		}
+	}
+	return sapi
+}
+
+func iniFileList(value string) []string {
+	var files []string
+	for _, file := range strings.Split(value, ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+func sapiName(serverAPI string) string {
+	switch serverAPI {
+	case "Command Line Interface":
+		return "cli"
+	case "FPM/FastCGI":
+		return "fpm"
+	case "Apache 2.0 Handler":
+		return "apache2"
+	case "CGI/FastCGI":
+		return "cgi"
+	}
+	return strings.ToLower(serverAPI)
+}
+
+// findPHPFpmBinaries - the php-fpm binaries to ask for their ini files. The binaries of a host mounted with -root
+// would run with this host's libraries and ini files, so none are run and findSAPIDirs finds their SAPIs instead.
+func findPHPFpmBinaries() []string {
+	if tasks.GetRoot() != "" {
+		return nil
+	}
+	var binaries []string
+	for _, dir := range phpFpmBinaryDirs {
+		entries, err := tasks.FS.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && phpFpmBinary.MatchString(entry.Name()) {
+				binaries = append(binaries, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return binaries
+}
+
+// findSAPIDirs - the SAPIs of the /etc/php/<version>/<sapi> directories that weren't already found by running them,
+// mod_php in particular can't be asked for its ini files from the command line
+func findSAPIDirs(known []PHPSAPI) []PHPSAPI {
+	loaded := make(map[string]bool)
+	for _, sapi := range known {
+		loaded[filepath.Dir(sapi.LoadedIni)] = true
+		loaded[sapi.ScanDir] = true
+	}
+
+	var sapis []PHPSAPI
+	versions, err := tasks.FS.ReadDir(tasks.RootPath("/etc/php"))
+	if err != nil {
+		return nil
+	}
+	for _, version := range versions {
+		if !version.IsDir() {
+			continue
+		}
+		for _, name := range phpSAPIDirs {
+			dir := filepath.Join("/etc/php", version.Name(), name)
+			scanDir := filepath.Join(dir, "conf.d")
+			if loaded[dir] || loaded[scanDir] || !tasks.FileExists(dir) {
+				continue
+			}
+			sapi := PHPSAPI{SAPI: name, Version: version.Name(), Source: dir}
+			if tasks.FileExists(filepath.Join(dir, "php.ini")) {
+				sapi.LoadedIni = filepath.Join(dir, "php.ini")
+			}
+			if entries, err := tasks.FS.ReadDir(tasks.RootPath(scanDir)); err == nil {
+				sapi.ScanDir = scanDir
+				for _, entry := range entries {
+					if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".ini") {
+						sapi.IniFiles = append(sapi.IniFiles, filepath.Join(scanDir, entry.Name()))
+					}
+				}
+				// PHP loads the scanned files in alphabetical order
+				sort.Strings(sapi.IniFiles)
+			}
+			sapis = append(sapis, sapi)
+		}
+	}
+	return sapis
+}
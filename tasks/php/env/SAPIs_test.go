@@ -0,0 +1,139 @@
+package env
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+var phpInfoCLI = `phpinfo()
+PHP Version => 7.4.3
+
+System => Linux web-1 5.4.0-100-generic #113-Ubuntu SMP x86_64
+Server API => Command Line Interface
+Virtual Directory Support => disabled
+Configuration File (php.ini) Path => /etc/php/7.4/cli
+Loaded Configuration File => /etc/php/7.4/cli/php.ini
+Scan this dir for additional .ini files => /etc/php/7.4/cli/conf.d
+Additional .ini files parsed => /etc/php/7.4/cli/conf.d/10-opcache.ini,
+/etc/php/7.4/cli/conf.d/20-newrelic.ini
+
+PHP API => 20190902
+`
+
+var phpInfoFPM = `phpinfo()
+PHP Version => 7.4.3
+Server API => FPM/FastCGI
+Loaded Configuration File => /etc/php/7.4/fpm/php.ini
+Scan this dir for additional .ini files => /etc/php/7.4/fpm/conf.d
+Additional .ini files parsed => /etc/php/7.4/fpm/conf.d/10-opcache.ini
+`
+
+func TestPHPEnvSAPIs_Execute(t *testing.T) {
+	defer tasks.UseFileSystem(tasks.NewMemFS(map[string]string{
+		"/usr/sbin/php-fpm7.4":                        "",
+		"/usr/sbin/phpdismod":                         "",
+		"/etc/php/7.4/cli/php.ini":                    "",
+		"/etc/php/7.4/fpm/php.ini":                    "",
+		"/etc/php/7.4/apache2/php.ini":                "",
+		"/etc/php/7.4/apache2/conf.d/20-newrelic.ini": "",
+		"/etc/php/7.4/apache2/conf.d/10-opcache.ini":  "",
+		"/etc/php/7.4/apache2/conf.d/README":          "",
+		"/etc/php/7.4/mods-available/newrelic.ini":    "",
+		"/etc/php/7.4/mods-available/opcache.ini":     "",
+	}))()
+
+	var ran []string
+	p := PHPEnvSAPIs{cmdExec: func(name string, arg ...string) ([]byte, error) {
+		ran = append(ran, name)
+		if name == "/usr/sbin/php-fpm7.4" {
+			return []byte(phpInfoFPM), nil
+		}
+		return nil, errors.New("not found")
+	}}
+	result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+		"PHP/Config/Agent":   {Status: tasks.Success},
+		"PHP/Env/PHPinfoCLI": {Status: tasks.Success, Payload: phpInfoCLI},
+	})
+
+	want := []PHPSAPI{
+		{
+			SAPI:      "cli",
+			Source:    "php -i",
+			LoadedIni: "/etc/php/7.4/cli/php.ini",
+			ScanDir:   "/etc/php/7.4/cli/conf.d",
+			IniFiles:  []string{"/etc/php/7.4/cli/conf.d/10-opcache.ini", "/etc/php/7.4/cli/conf.d/20-newrelic.ini"},
+		},
+		{
+			SAPI:      "fpm",
+			Source:    "/usr/sbin/php-fpm7.4 -i",
+			LoadedIni: "/etc/php/7.4/fpm/php.ini",
+			ScanDir:   "/etc/php/7.4/fpm/conf.d",
+			IniFiles:  []string{"/etc/php/7.4/fpm/conf.d/10-opcache.ini"},
+		},
+		{
+			SAPI:      "apache2",
+			Version:   "7.4",
+			Source:    "/etc/php/7.4/apache2",
+			LoadedIni: "/etc/php/7.4/apache2/php.ini",
+			ScanDir:   "/etc/php/7.4/apache2/conf.d",
+			IniFiles:  []string{"/etc/php/7.4/apache2/conf.d/10-opcache.ini", "/etc/php/7.4/apache2/conf.d/20-newrelic.ini"},
+		},
+	}
+	if result.Status != tasks.Success {
+		t.Fatalf("Execute() status = %s, want Success: %s", result.Status.StatusToString(), result.Summary)
+	}
+	if !reflect.DeepEqual(result.Payload, want) {
+		t.Errorf("Execute() payload = %+v, want %+v", result.Payload, want)
+	}
+	if !reflect.DeepEqual(ran, []string{"/usr/sbin/php-fpm7.4"}) {
+		t.Errorf("Execute() ran %v, want only the php-fpm binary", ran)
+	}
+}
+
+func TestPHPEnvSAPIs_Execute_root(t *testing.T) {
+	defer tasks.UseFileSystem(tasks.NewMemFS(map[string]string{
+		"/host/usr/sbin/php-fpm7.4":                    "",
+		"/host/etc/php/7.4/fpm/php.ini":                "",
+		"/host/etc/php/7.4/fpm/conf.d/20-newrelic.ini": "",
+	}))()
+	if err := tasks.SetRoot("/host"); err != nil {
+		t.Fatal(err)
+	}
+	defer tasks.SetRoot("")
+
+	var ran []string
+	p := PHPEnvSAPIs{cmdExec: func(name string, arg ...string) ([]byte, error) {
+		ran = append(ran, name)
+		return []byte(phpInfoFPM), nil
+	}}
+	result := p.Execute(tasks.Options{}, map[string]tasks.Result{
+		"PHP/Config/Agent": {Status: tasks.Success},
+	})
+
+	want := []PHPSAPI{{
+		SAPI:      "fpm",
+		Version:   "7.4",
+		Source:    "/etc/php/7.4/fpm",
+		LoadedIni: "/etc/php/7.4/fpm/php.ini",
+		ScanDir:   "/etc/php/7.4/fpm/conf.d",
+		IniFiles:  []string{"/etc/php/7.4/fpm/conf.d/20-newrelic.ini"},
+	}}
+	if len(ran) > 0 {
+		t.Errorf("Execute() ran %v, want no binaries of the mounted host", ran)
+	}
+	if !reflect.DeepEqual(result.Payload, want) {
+		t.Errorf("Execute() payload = %+v, want %+v", result.Payload, want)
+	}
+}
+
+func TestPHPEnvSAPIs_Execute_noAgent(t *testing.T) {
+	result := PHPEnvSAPIs{}.Execute(tasks.Options{}, map[string]tasks.Result{
+		"PHP/Config/Agent": {Status: tasks.None},
+	})
+	if result.Status != tasks.None {
+		t.Errorf("Execute() status = %s, want None", result.Status.StatusToString())
+	}
+}
@@ -11,4 +11,7 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 	registrationFunc(PHPEnvPHPinfoCLI{
 		cmdExec: tasks.CmdExecutor,
 	}, true)
+	registrationFunc(PHPEnvSAPIs{
+		cmdExec: tasks.CmdExecutor,
+	}, true)
 }
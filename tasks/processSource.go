@@ -0,0 +1,285 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/process"
+	"gopkg.in/yaml.v3"
+)
+
+// ProcessSource is the process table tasks look processes up in. Tasks go through Processes instead of gopsutil
+// so their tests can declare the running processes with a FakeProcessSource
+type ProcessSource interface {
+	Pids() ([]int32, error)
+	Name(pid int32) (string, error)
+	Cmdline(pid int32) ([]string, error)
+	Environ(pid int32) (map[string]string, error)
+	Cwd(pid int32) (string, error)
+	Username(pid int32) (string, error)
+	Uids(pid int32) ([]int32, error)
+	Gids(pid int32) ([]int32, error)
+	OpenFiles(pid int32) ([]string, error)
+}
+
+// Processes is the process table the task helpers read from, the one of the local machine by default
+var Processes ProcessSource = GopsutilProcessSource{}
+
+// UseProcessSource replaces Processes and returns a function restoring the previous one, e.g. `defer tasks.UseProcessSource(fake)()` in a test
+func UseProcessSource(source ProcessSource) func() {
+	previous := Processes
+	Processes = source
+	return func() {
+		Processes = previous
+	}
+}
+
+// RunningProcess is a process found in Processes, its methods look it up there
+type RunningProcess struct {
+	Pid int32 `json:"pid"`
+}
+
+// Name returns the name of the process executable
+func (p RunningProcess) Name() (string, error) {
+	return Processes.Name(p.Pid)
+}
+
+// Cmdline returns the command line of the process with the arguments joined by spaces
+func (p RunningProcess) Cmdline() (string, error) {
+	args, err := Processes.Cmdline(p.Pid)
+	return strings.Join(args, " "), err
+}
+
+// CmdlineSlice returns the command line arguments of the process, starting with the executable
+func (p RunningProcess) CmdlineSlice() ([]string, error) {
+	return Processes.Cmdline(p.Pid)
+}
+
+// Cwd returns the working directory of the process
+func (p RunningProcess) Cwd() (string, error) {
+	return Processes.Cwd(p.Pid)
+}
+
+// Username returns the name of the user owning the process
+func (p RunningProcess) Username() (string, error) {
+	return Processes.Username(p.Pid)
+}
+
+// Uids returns the real, effective, saved and filesystem user ids of the process
+func (p RunningProcess) Uids() ([]int32, error) {
+	return Processes.Uids(p.Pid)
+}
+
+// Gids returns the real, effective, saved and filesystem group ids of the process
+func (p RunningProcess) Gids() ([]int32, error) {
+	return Processes.Gids(p.Pid)
+}
+
+// OpenFiles returns the paths of the files the process has open
+func (p RunningProcess) OpenFiles() ([]string, error) {
+	return Processes.OpenFiles(p.Pid)
+}
+
+// GopsutilProcessSource is the process table of the machine nrdiag runs on, read with gopsutil. With -root it is read from <root>/proc
+type GopsutilProcessSource struct{}
+
+// Pids - process.Pids
+func (GopsutilProcessSource) Pids() ([]int32, error) {
+	return process.Pids()
+}
+
+// Name - process.Process.Name
+func (GopsutilProcessSource) Name(pid int32) (string, error) {
+	return (&process.Process{Pid: pid}).Name()
+}
+
+// Cmdline - process.Process.CmdlineSlice
+func (GopsutilProcessSource) Cmdline(pid int32) ([]string, error) {
+	return (&process.Process{Pid: pid}).CmdlineSlice()
+}
+
+// Environ reads the environment the process was started with from /proc. It is not implemented for other platforms
+func (GopsutilProcessSource) Environ(pid int32) (map[string]string, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errors.New("GetProcessEnvVars is not implemented for " + runtime.GOOS)
+	}
+	environFile, err := ReadFileBytes(filepath.Join("/proc", strconv.FormatInt(int64(pid), 10), "environ"))
+	if err != nil {
+		return nil, err
+	}
+
+	envVars := make(map[string]string)
+	for _, line := range strings.Split(string(environFile), "\x00") {
+		split := strings.Split(line, "=")
+		if len(split) > 1 {
+			envVars[split[0]] = split[1]
+		}
+	}
+	return envVars, nil
+}
+
+// Cwd - process.Process.Cwd
+func (GopsutilProcessSource) Cwd(pid int32) (string, error) {
+	return (&process.Process{Pid: pid}).Cwd()
+}
+
+// Username - process.Process.Username
+func (GopsutilProcessSource) Username(pid int32) (string, error) {
+	return (&process.Process{Pid: pid}).Username()
+}
+
+// Uids - process.Process.Uids
+func (GopsutilProcessSource) Uids(pid int32) ([]int32, error) {
+	return (&process.Process{Pid: pid}).Uids()
+}
+
+// Gids - process.Process.Gids
+func (GopsutilProcessSource) Gids(pid int32) ([]int32, error) {
+	return (&process.Process{Pid: pid}).Gids()
+}
+
+// OpenFiles - process.Process.OpenFiles
+func (GopsutilProcessSource) OpenFiles(pid int32) ([]string, error) {
+	openFiles, err := (&process.Process{Pid: pid}).OpenFiles()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, openFile := range openFiles {
+		paths = append(paths, openFile.Path)
+	}
+	return paths, nil
+}
+
+// FakeProcess is a process of a FakeProcessSource
+type FakeProcess struct {
+	Pid       int32             `yaml:"pid"`
+	Name      string            `yaml:"name"`
+	Cmdline   []string          `yaml:"cmdline"`
+	Env       map[string]string `yaml:"env"`
+	Cwd       string            `yaml:"cwd"`
+	Username  string            `yaml:"username"`
+	Uid       int32             `yaml:"uid"`
+	Gid       int32             `yaml:"gid"`
+	OpenFiles []string          `yaml:"openFiles"`
+}
+
+// FakeProcessSource is a process table for tests, seeded from YAML:
+//
+//	processes:
+//	  - pid: 1234
+//	    name: java
+//	    cmdline: [java, -javaagent:/opt/newrelic/newrelic.jar, -jar, app.jar]
+//	    env: {NEW_RELIC_APP_NAME: my app}
+//	    cwd: /opt/app
+//	    username: app
+//	    uid: 1000
+//	    gid: 1000
+//	    openFiles: [/opt/newrelic/logs/newrelic_agent.log]
+type FakeProcessSource struct {
+	Processes []FakeProcess `yaml:"processes"`
+}
+
+// NewFakeProcessSource parses a process table from YAML
+func NewFakeProcessSource(data []byte) (*FakeProcessSource, error) {
+	source := &FakeProcessSource{}
+	if err := yaml.Unmarshal(data, source); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// LoadFakeProcessSource reads a process table from a YAML file, e.g. a test fixture
+func LoadFakeProcessSource(path string) (*FakeProcessSource, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewFakeProcessSource(data)
+}
+
+func (f *FakeProcessSource) process(pid int32) (FakeProcess, error) {
+	for _, proc := range f.Processes {
+		if proc.Pid == pid {
+			return proc, nil
+		}
+	}
+	return FakeProcess{}, fmt.Errorf("process %d not found", pid)
+}
+
+// Pids returns the pids of the processes in ascending order
+func (f *FakeProcessSource) Pids() ([]int32, error) {
+	var pids []int32
+	for _, proc := range f.Processes {
+		pids = append(pids, proc.Pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return pids, nil
+}
+
+// Name returns the name of a process
+func (f *FakeProcessSource) Name(pid int32) (string, error) {
+	proc, err := f.process(pid)
+	return proc.Name, err
+}
+
+// Cmdline returns the command line arguments of a process
+func (f *FakeProcessSource) Cmdline(pid int32) ([]string, error) {
+	proc, err := f.process(pid)
+	return proc.Cmdline, err
+}
+
+// Environ returns the environment variables of a process
+func (f *FakeProcessSource) Environ(pid int32) (map[string]string, error) {
+	proc, err := f.process(pid)
+	if err != nil {
+		return nil, err
+	}
+	envVars := make(map[string]string)
+	for key, value := range proc.Env {
+		envVars[key] = value
+	}
+	return envVars, nil
+}
+
+// Cwd returns the working directory of a process
+func (f *FakeProcessSource) Cwd(pid int32) (string, error) {
+	proc, err := f.process(pid)
+	return proc.Cwd, err
+}
+
+// Username returns the user owning a process
+func (f *FakeProcessSource) Username(pid int32) (string, error) {
+	proc, err := f.process(pid)
+	return proc.Username, err
+}
+
+// Uids returns the uid of a process as its real, effective, saved and filesystem user ids
+func (f *FakeProcessSource) Uids(pid int32) ([]int32, error) {
+	proc, err := f.process(pid)
+	if err != nil {
+		return nil, err
+	}
+	return []int32{proc.Uid, proc.Uid, proc.Uid, proc.Uid}, nil
+}
+
+// Gids returns the gid of a process as its real, effective, saved and filesystem group ids
+func (f *FakeProcessSource) Gids(pid int32) ([]int32, error) {
+	proc, err := f.process(pid)
+	if err != nil {
+		return nil, err
+	}
+	return []int32{proc.Gid, proc.Gid, proc.Gid, proc.Gid}, nil
+}
+
+// OpenFiles returns the files a process has open
+func (f *FakeProcessSource) OpenFiles(pid int32) ([]string, error) {
+	proc, err := f.process(pid)
+	return proc.OpenFiles, err
+}
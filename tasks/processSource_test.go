@@ -0,0 +1,83 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func useFixtureProcesses(t *testing.T) {
+	source, err := LoadFakeProcessSource("fixtures/processes/java_ruby_dotnet.yml")
+	if err != nil {
+		t.Fatalf("LoadFakeProcessSource() error = %v", err)
+	}
+	t.Cleanup(UseProcessSource(source))
+}
+
+func TestFindProcessByName_fake(t *testing.T) {
+	useFixtureProcesses(t)
+
+	tests := []struct {
+		name string
+		want []RunningProcess
+	}{
+		{name: "java", want: []RunningProcess{{Pid: 1200}}},
+		{name: "ruby", want: []RunningProcess{{Pid: 1300}}},
+		{name: "dotnet.exe", want: nil},
+		{name: "python", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindProcessByName(tt.name)
+			if err != nil {
+				t.Fatalf("FindProcessByName() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindProcessByName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunningProcess_fake(t *testing.T) {
+	useFixtureProcesses(t)
+	proc := RunningProcess{Pid: 1300}
+
+	if cmdline, _ := proc.Cmdline(); cmdline != "ruby bin/rails server" {
+		t.Errorf("Cmdline() = %q, want the joined arguments", cmdline)
+	}
+	if cwd, _ := proc.Cwd(); cwd != "/srv/storefront" {
+		t.Errorf("Cwd() = %q, want /srv/storefront", cwd)
+	}
+	if uids, _ := proc.Uids(); len(uids) == 0 || uids[0] != 1002 {
+		t.Errorf("Uids() = %v, want 1002", uids)
+	}
+	if _, err := (RunningProcess{Pid: 1}).Name(); err == nil {
+		t.Error("Name() of a missing process expected an error")
+	}
+
+	envVars, err := GetProcessEnvVars(1300)
+	if err != nil {
+		t.Fatalf("GetProcessEnvVars() error = %v", err)
+	}
+	if envVars.All["RAILS_ENV"] != "production" || envVars.Scope != Process || envVars.PID != 1300 {
+		t.Errorf("GetProcessEnvVars() = %+v, want the environment of process 1300", envVars)
+	}
+}
+
+func TestGetNewRelicSystemProps_fake(t *testing.T) {
+	useFixtureProcesses(t)
+
+	javaProcArgs := GetJavaProcArgs()
+	if len(javaProcArgs) != 1 || javaProcArgs[0].ProcID != 1200 || len(javaProcArgs[0].Args) != 6 {
+		t.Fatalf("GetJavaProcArgs() = %+v, want the arguments of process 1200", javaProcArgs)
+	}
+
+	sysProps := GetNewRelicSystemProps()
+	if len(sysProps) == 0 {
+		t.Fatal("GetNewRelicSystemProps() found no system properties")
+	}
+	want := map[string]string{"-Dnewrelic.config.app_name": "billing", "-Djava.io.tmpdir": "/var/tmp"}
+	if sysProps[0].ProcID != 1200 || !reflect.DeepEqual(sysProps[0].SysPropsKeyToVal, want) {
+		t.Errorf("GetNewRelicSystemProps() = %+v, want %v for process 1200", sysProps[0], want)
+	}
+}
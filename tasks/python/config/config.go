@@ -3,6 +3,7 @@ package config
 import (
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
 )
 
 // RegisterWith - will register any plugins in this package
@@ -10,4 +11,7 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 	log.Debug("Registering Python/Config/*")
 
 	registrationFunc(PythonConfigAgent{}, true)
+	registrationFunc(PythonConfigEnvironment{}, true)
+	registrationFunc(config.NewValidateSettingsTask("Python/Config/ValidateSettings", "Python/Config/Environment", "python"), true)
+	config.RegisterSettingsSpec("python")
 }
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+)
+
+const (
+	pythonSettingsSection = "newrelic"
+	pythonEnvironmentVar  = "NEW_RELIC_ENVIRONMENT"
+)
+
+// pythonServerNames are the executables besides python* that Python apps commonly run as
+var pythonServerNames = []string{"gunicorn", "uwsgi", "celery"}
+
+// PythonConfigEnvironment - resolves the settings of newrelic.ini the Python agent reads, the [newrelic] section with
+// the [newrelic:<environment>] section picked by the NEW_RELIC_ENVIRONMENT of the Python processes on top of it
+type PythonConfigEnvironment struct {
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p PythonConfigEnvironment) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("Python/Config/Environment")
+}
+
+// Explain - Returns the help text for each individual task
+func (p PythonConfigEnvironment) Explain() string {
+	return "Resolve the Python agent settings for the environment the Python processes set in NEW_RELIC_ENVIRONMENT"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p PythonConfigEnvironment) Dependencies() []string {
+	return []string{
+		"Python/Config/Agent",
+		"Base/Env/CollectEnvVars",
+	}
+}
+
+// Execute - The core work within each task
+func (p PythonConfigEnvironment) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	configs, ok := upstream["Python/Config/Agent"].Payload.([]config.ValidateElement)
+	if !ok || len(configs) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No Python agent config files found",
+		}
+	}
+	envVars, _ := upstream["Base/Env/CollectEnvVars"].Payload.(map[string]string)
+	environments := pythonEnvironments(envVars)
+
+	var resolved []config.ValidateElement
+	var missing []string
+	for _, element := range configs {
+		file := element.Config.FilePath + element.Config.FileName
+		content, err := tasks.ReadFileBytes(file)
+		if err != nil {
+			log.Debug("Unable to read", file, err)
+			resolved = append(resolved, element)
+			continue
+		}
+		sections := parseIniSections(content)
+		for _, environment := range environments {
+			overlay := ""
+			if environment != "" {
+				overlay = pythonSettingsSection + ":" + environment
+				if _, ok := sections[overlay]; !ok {
+					missing = append(missing, "["+overlay+"] in "+file)
+					overlay = ""
+				}
+			}
+			environmentElement := element
+			environmentElement.Status = tasks.Success
+			environmentElement.Error = ""
+			environmentElement.ParsedResult = mergeIniSections(sections[pythonSettingsSection], sections[overlay])
+			resolved = append(resolved, environmentElement)
+		}
+	}
+
+	if len(missing) > 0 {
+		return tasks.Result{
+			Status: tasks.Warning,
+			Summary: pythonEnvironmentVar + " is set to " + strings.Join(quoteEnvironments(environments), ", ") + ", but these sections are missing:\n\t" +
+				strings.Join(missing, "\n\t") + "\nThe agent only reads the [" + pythonSettingsSection + "] section of these files.",
+			URL:     "https://docs.newrelic.com/docs/agents/python-agent/configuration/python-agent-configuration#environment-variables",
+			Payload: resolved,
+		}
+	}
+	summary := "Resolved the Python agent settings of the [" + pythonSettingsSection + "] section"
+	for _, environment := range environments {
+		if environment != "" {
+			summary += "\n\twith the [" + pythonSettingsSection + ":" + environment + "] section on top"
+		}
+	}
+	return tasks.Result{
+		Status:  tasks.Success,
+		Summary: summary,
+		Payload: resolved,
+	}
+}
+
+// pythonEnvironments - the distinct NEW_RELIC_ENVIRONMENT values of the running Python processes, "" for the processes
+// without one, as the agent reads the variable from its own process. The shell's value is only used when no Python
+// process is found.
+func pythonEnvironments(shellEnvVars map[string]string) []string {
+	seen := make(map[string]bool)
+	var environments []string
+	for _, pid := range pythonProcesses() {
+		envVars, err := tasks.GetProcessEnvVars(pid)
+		if err != nil {
+			continue
+		}
+		environment := envVars.All[pythonEnvironmentVar]
+		if !seen[environment] {
+			seen[environment] = true
+			environments = append(environments, environment)
+		}
+	}
+	if len(environments) == 0 {
+		return []string{shellEnvVars[pythonEnvironmentVar]}
+	}
+	sort.Strings(environments)
+	return environments
+}
+
+// pythonProcesses - the pids of the processes running python, or one of pythonServerNames
+func pythonProcesses() []int32 {
+	pids, err := tasks.Processes.Pids()
+	if err != nil {
+		log.Debug("Unable to list the processes:", err)
+		return nil
+	}
+	var pythonPids []int32
+	for _, pid := range pids {
+		if !tasks.IsTargetPid(pid) {
+			continue
+		}
+		name, err := tasks.Processes.Name(pid)
+		if err != nil {
+			continue
+		}
+		name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+		if strings.HasPrefix(name, "python") || tasks.ContainsString(pythonServerNames, name) {
+			pythonPids = append(pythonPids, pid)
+		}
+	}
+	return pythonPids
+}
+
+func quoteEnvironments(environments []string) []string {
+	var quoted []string
+	for _, environment := range environments {
+		if environment != "" {
+			quoted = append(quoted, "'"+environment+"'")
+		}
+	}
+	return quoted
+}
+
+// parseIniSections - the settings of each section of an ini file. Like the ConfigParser the agent uses, keys are
+// delimited by = or :, comment lines start with # or ; and indented lines continue the previous value.
+func parseIniSections(content []byte) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	var section map[string]string
+	lastKey := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if sections[name] == nil {
+				sections[name] = make(map[string]string)
+			}
+			section = sections[name]
+			lastKey = ""
+			continue
+		}
+		if section == nil {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if lastKey != "" {
+				section[lastKey] = strings.TrimSpace(section[lastKey] + "\n" + trimmed)
+			}
+			continue
+		}
+		delimiter := strings.IndexAny(trimmed, "=:")
+		if delimiter < 1 {
+			continue
+		}
+		lastKey = strings.TrimSpace(trimmed[:delimiter])
+		section[lastKey] = strings.TrimSpace(trimmed[delimiter+1:])
+	}
+	return sections
+}
+
+// mergeIniSections - the settings of base with those of overlay on top, as a blob shaped like Base/Config/Validate's
+func mergeIniSections(base map[string]string, overlay map[string]string) tasks.ValidateBlob {
+	merged := make(map[string]string)
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		merged[key] = value
+	}
+	blob := tasks.ValidateBlob{Children: []tasks.ValidateBlob{}}
+	for key, value := range merged {
+		blob.Children = append(blob.Children, tasks.ValidateBlob{Key: key, RawValue: value})
+	}
+	blob.Sort()
+	return blob
+}
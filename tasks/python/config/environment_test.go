@@ -0,0 +1,132 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+)
+
+func TestPythonConfigEnvironment_Execute(t *testing.T) {
+	agent := tasks.Result{
+		Status:  tasks.Success,
+		Payload: []config.ValidateElement{{Config: config.ConfigElement{FileName: "newrelic.ini", FilePath: "../../fixtures/python/"}, Status: tasks.Success}},
+	}
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		processes   string
+		wantStatus  tasks.Status
+		wantAppName string
+		wantMonitor string
+	}{
+		{
+			name:        "no environment reads the newrelic section",
+			wantStatus:  tasks.Success,
+			wantAppName: "My Python App",
+			wantMonitor: "true",
+		},
+		{
+			name:        "environment section overrides the newrelic section",
+			envVars:     map[string]string{"NEW_RELIC_ENVIRONMENT": "staging"},
+			wantStatus:  tasks.Success,
+			wantAppName: "Python Application (Staging)",
+			wantMonitor: "true",
+		},
+		{
+			name:        "environment section overrides only its settings",
+			envVars:     map[string]string{"NEW_RELIC_ENVIRONMENT": "development"},
+			wantStatus:  tasks.Success,
+			wantAppName: "My Python App",
+			wantMonitor: "false",
+		},
+		{
+			name:        "missing environment section",
+			envVars:     map[string]string{"NEW_RELIC_ENVIRONMENT": "qa"},
+			wantStatus:  tasks.Warning,
+			wantAppName: "My Python App",
+			wantMonitor: "true",
+		},
+		{
+			name:        "the environment of the python process wins over the shell's",
+			envVars:     map[string]string{"NEW_RELIC_ENVIRONMENT": "qa"},
+			processes:   "processes: [{pid: 7, name: python3.8, env: {NEW_RELIC_ENVIRONMENT: staging}}]",
+			wantStatus:  tasks.Success,
+			wantAppName: "Python Application (Staging)",
+			wantMonitor: "true",
+		},
+		{
+			name:        "a python process without an environment reads the newrelic section",
+			envVars:     map[string]string{"NEW_RELIC_ENVIRONMENT": "staging"},
+			processes:   "processes: [{pid: 7, name: gunicorn, env: {}}, {pid: 8, name: ruby, env: {NEW_RELIC_ENVIRONMENT: development}}]",
+			wantStatus:  tasks.Success,
+			wantAppName: "My Python App",
+			wantMonitor: "true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.processes == "" {
+				tt.processes = "processes: []"
+			}
+			source, err := tasks.NewFakeProcessSource([]byte(tt.processes))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tasks.UseProcessSource(source)()
+			result := PythonConfigEnvironment{}.Execute(tasks.Options{}, map[string]tasks.Result{
+				"Python/Config/Agent":     agent,
+				"Base/Env/CollectEnvVars": {Status: tasks.Info, Payload: tt.envVars},
+			})
+			if result.Status != tt.wantStatus {
+				t.Fatalf("Execute() status = %s, want %s: %s", result.Status.StatusToString(), tt.wantStatus.StatusToString(), result.Summary)
+			}
+			parsed := result.Payload.([]config.ValidateElement)[0].ParsedResult
+			if got := parsed.FindKey("app_name")[0].Value(); got != tt.wantAppName {
+				t.Errorf("Execute() app_name = %q, want %q", got, tt.wantAppName)
+			}
+			if got := parsed.FindKey("monitor_mode")[0].Value(); got != tt.wantMonitor {
+				t.Errorf("Execute() monitor_mode = %q, want %q", got, tt.wantMonitor)
+			}
+		})
+	}
+}
+
+func TestPythonConfigEnvironment_ExecuteResolvesEachProcessEnvironment(t *testing.T) {
+	source, err := tasks.NewFakeProcessSource([]byte(`
+processes:
+  - {pid: 7, name: python, env: {NEW_RELIC_ENVIRONMENT: staging}}
+  - {pid: 8, name: python, env: {NEW_RELIC_ENVIRONMENT: development}}
+  - {pid: 9, name: python, env: {NEW_RELIC_ENVIRONMENT: staging}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tasks.UseProcessSource(source)()
+	result := PythonConfigEnvironment{}.Execute(tasks.Options{}, map[string]tasks.Result{
+		"Python/Config/Agent": {
+			Status:  tasks.Success,
+			Payload: []config.ValidateElement{{Config: config.ConfigElement{FileName: "newrelic.ini", FilePath: "../../fixtures/python/"}, Status: tasks.Success}},
+		},
+	})
+	elements := result.Payload.([]config.ValidateElement)
+	var appNames []string
+	for _, element := range elements {
+		appNames = append(appNames, element.ParsedResult.FindKey("app_name")[0].Value())
+	}
+	if want := []string{"My Python App", "Python Application (Staging)"}; !reflect.DeepEqual(appNames, want) {
+		t.Errorf("Execute() app_name per environment = %v, want %v", appNames, want)
+	}
+}
+
+func Test_parseIniSections(t *testing.T) {
+	content := []byte("; comment\nignored = before any section\n[newrelic]\nlicense_key = abc\napp_name: one;\n  two\n# comment\n[newrelic:staging]\napp_name = staging\n")
+	want := map[string]map[string]string{
+		"newrelic":         {"license_key": "abc", "app_name": "one;\ntwo"},
+		"newrelic:staging": {"app_name": "staging"},
+	}
+	if got := parseIniSections(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIniSections() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+)
+
+func TestPythonConfigValidateSettings_Execute(t *testing.T) {
+	validated := config.BaseConfigValidate{}.Execute(tasks.Options{}, map[string]tasks.Result{
+		"Base/Config/Collect": {
+			Status:  tasks.Success,
+			Payload: []config.ConfigElement{{FileName: "newrelic.ini", FilePath: "../../fixtures/python/"}},
+		},
+	})
+	resolved := PythonConfigEnvironment{}.Execute(tasks.Options{}, map[string]tasks.Result{
+		"Python/Config/Agent": {Status: tasks.Success, Payload: validated.Payload},
+	})
+
+	tests := []struct {
+		name         string
+		options      tasks.Options
+		upstream     map[string]tasks.Result
+		wantStatus   tasks.Status
+		wantProblems []string
+	}{
+		{
+			name:       "no config file found",
+			upstream:   map[string]tasks.Result{"Python/Config/Environment": {Status: tasks.None}},
+			wantStatus: tasks.None,
+		},
+		{
+			name:       "fixture newrelic.ini",
+			upstream:   map[string]tasks.Result{"Python/Config/Environment": {Status: tasks.Success, Payload: resolved.Payload}},
+			wantStatus: tasks.Warning,
+			wantProblems: []string{
+				"Deprecated error_collector.ignore_errors",
+				"Removed ssl",
+			},
+		},
+		{
+			name:       "missing spec file",
+			options:    tasks.Options{Options: map[string]string{"specFile": "../../fixtures/python/missing_spec.yml"}},
+			upstream:   map[string]tasks.Result{"Python/Config/Environment": {Status: tasks.Success, Payload: resolved.Payload}},
+			wantStatus: tasks.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := config.NewValidateSettingsTask("Python/Config/ValidateSettings", "Python/Config/Environment", "python").Execute(tt.options, tt.upstream)
+			if result.Status != tt.wantStatus {
+				t.Fatalf("Execute() status = %s, want %s: %s", result.Status.StatusToString(), tt.wantStatus.StatusToString(), result.Summary)
+			}
+			validations, ok := result.Payload.([]config.SettingsValidation)
+			if !ok {
+				return
+			}
+			var problems []string
+			for _, problem := range validations[0].Problems {
+				problems = append(problems, string(problem.Status)+" "+problem.Key)
+			}
+			if !reflect.DeepEqual(problems, tt.wantProblems) {
+				t.Errorf("Execute() problems = %v, want %v", problems, tt.wantProblems)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// rootPath is the directory the host being diagnosed is mounted at, set with -root. Empty means /
+var rootPath string
+
+// maxSymlinks is the number of links followed when resolving a path inside the root before giving up
+const maxSymlinks = 40
+
+// hostEnvVars are the environment variables gopsutil reads to find the host's pseudo filesystems
+var hostEnvVars = map[string]string{
+	"HOST_PROC": "/proc",
+	"HOST_SYS":  "/sys",
+	"HOST_ETC":  "/etc",
+	"HOST_VAR":  "/var",
+	"HOST_RUN":  "/run",
+	"HOST_DEV":  "/dev",
+}
+
+// SetRoot makes every filesystem and process lookup use the host mounted at root, e.g. /host when running in a container.
+// An empty root restores lookups on /
+func SetRoot(root string) error {
+	if root == "" {
+		rootPath = ""
+		for envVar := range hostEnvVars {
+			os.Unsetenv(envVar)
+		}
+		return nil
+	}
+	if runtime.GOOS == "windows" {
+		return errors.New("-root is not supported on Windows")
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	info, err := FS.Stat(absRoot)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New(root + " is not a directory")
+	}
+
+	rootPath = filepath.Clean(absRoot)
+	for envVar, path := range hostEnvVars {
+		os.Setenv(envVar, filepath.Join(rootPath, path))
+	}
+	return nil
+}
+
+// GetRoot returns the directory set with SetRoot, or an empty string if lookups are done on /
+func GetRoot() string {
+	return rootPath
+}
+
+// RootPath rebases an absolute path of the host being diagnosed onto the root set with SetRoot. Relative paths are returned unchanged.
+// The symlinks of the path are resolved inside the root, so the OS never follows a link out of it when the rebased path is read.
+// The paths tasks exchange, such as the ones returned by FindFiles, are host paths, so RootPath is called once, where the file is read
+func RootPath(path string) string {
+	if rootPath == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	if resolved, err := evalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return joinRoot(path)
+}
+
+func joinRoot(path string) string {
+	return filepath.Join(rootPath, path)
+}
+
+// hostPath returns the host path of a path read below the root, the reverse of RootPath. Paths outside of the root are returned unchanged
+func hostPath(path string) string {
+	if rootPath == "" {
+		return path
+	}
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.Join(string(filepath.Separator), rel)
+}
+
+// evalSymlinks resolves the symlinks of a path. Inside a root every component is resolved the way the host would, as if
+// chrooted: absolute link targets and ".." never leave the root. The resolved path is a host path
+func evalSymlinks(path string) (string, error) {
+	if rootPath == "" || !filepath.IsAbs(path) {
+		return FS.EvalSymlinks(path)
+	}
+
+	resolved := "/"
+	remaining := strings.Split(path, "/")
+	links := 0
+	for len(remaining) > 0 {
+		name := remaining[0]
+		remaining = remaining[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			// the parent of / is / itself
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, name)
+		info, err := FS.Lstat(joinRoot(next))
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", errors.New("too many links resolving " + path)
+		}
+		target, err := FS.Readlink(joinRoot(next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+		remaining = append(strings.Split(target, "/"), remaining...)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,177 @@
+package tasks
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+)
+
+// setFixtureRoot points lookups at the fixture host tree under fixtures/root until the test ends
+func setFixtureRoot(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("-root is not supported on Windows")
+	}
+	if err := SetRoot("fixtures/root"); err != nil {
+		t.Fatalf("SetRoot() error = %v", err)
+	}
+	t.Cleanup(func() { SetRoot("") })
+	root, _ := filepath.Abs("fixtures/root")
+	return root
+}
+
+func TestSetRoot_invalid(t *testing.T) {
+	if err := SetRoot("fixtures/root/does-not-exist"); err == nil {
+		t.Error("SetRoot() expected an error for a missing directory")
+	}
+	if err := SetRoot("fixtures/root/etc/newrelic-infra.yml"); err == nil {
+		t.Error("SetRoot() expected an error for a file")
+	}
+	if GetRoot() != "" {
+		t.Errorf("GetRoot() = %s after invalid roots, want it unset", GetRoot())
+	}
+}
+
+func TestRootPath(t *testing.T) {
+	root := setFixtureRoot(t)
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "absolute path is rebased", path: "/etc/newrelic-infra.yml", want: filepath.Join(root, "etc/newrelic-infra.yml")},
+		{name: "path starting with the root is still a host path", path: filepath.Join(root, "var/log"), want: filepath.Join(root, root, "var/log")},
+		{name: "relative path is unchanged", path: "newrelic.yml", want: "newrelic.yml"},
+		{name: "sibling of the root is rebased", path: root + "-other/file", want: filepath.Join(root, root+"-other/file")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RootPath(tt.path); got != tt.want {
+				t.Errorf("RootPath(%s) = %s, want %s", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoot_fileLookups(t *testing.T) {
+	setFixtureRoot(t)
+
+	if !FileExists("/etc/newrelic-infra.yml") {
+		t.Error("FileExists() expected /etc/newrelic-infra.yml to be found under the root")
+	}
+	if FileExists("/etc/newrelic-infra/newrelic-infra.yml") {
+		t.Error("FileExists() found a file missing from the root")
+	}
+	if content := ReadFile("/var/log/newrelic/php_agent.log"); content == "" {
+		t.Error("ReadFile() expected the content of /var/log/newrelic/php_agent.log under the root")
+	}
+	if !FindStringInFile("php agent log", "/var/log/newrelic/php_agent.log") {
+		t.Error("FindStringInFile() expected to find the string in the file under the root")
+	}
+
+	status := ValidatePath("/var/log/newrelic/php_agent.log")
+	if !status.IsValid || status.Path != "/var/log/newrelic/php_agent.log" {
+		t.Errorf("ValidatePath() = %+v, want the valid host path", status)
+	}
+	if status := ValidatePath("/var/log/newrelic"); status.IsValid {
+		t.Error("ValidatePath() expected a directory under the root to be invalid")
+	}
+}
+
+func TestRoot_FindFiles(t *testing.T) {
+	setFixtureRoot(t)
+
+	tests := []struct {
+		name     string
+		patterns []string
+		paths    []string
+		want     []string
+	}{
+		{
+			name:     "finds files under the root and returns their host paths",
+			patterns: []string{".+[.]y(a)?ml$"},
+			paths:    []string{"/etc/newrelic-infra/"},
+			want:     []string{"/etc/newrelic-infra/integrations.d/nginx-config.yml"},
+		},
+		{
+			name:     "absolute symlink is resolved inside the root",
+			patterns: []string{"newrelic-daemon[.]log$"},
+			paths:    []string{"/var/log/nrlogs"},
+			want:     []string{"/opt/logs/newrelic-daemon.log"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindFiles(tt.patterns, tt.paths)
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindFiles() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("FindFiles() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRoot_evalSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("-root is not supported on Windows")
+	}
+	memFS := NewMemFS(map[string]string{
+		"/host/etc/shadow":               "host shadow",
+		"/host/opt/app-2/logs/agent.log": "agent log",
+		"/etc/shadow":                    "local shadow",
+	})
+	memFS.Symlink("../../../../etc/shadow", "/host/var/log/escape")
+	memFS.Symlink("/opt/app-2", "/host/opt/current")
+	memFS.Symlink("current/logs/../../app-2", "/host/opt/relative")
+	memFS.Symlink("/loop", "/host/loop")
+	defer UseFileSystem(memFS)()
+	if err := SetRoot("/host"); err != nil {
+		t.Fatalf("SetRoot() error = %v", err)
+	}
+	defer SetRoot("")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "relative target is clamped to the root", path: "/var/log/escape", want: "/etc/shadow"},
+		{name: "link in an intermediate component", path: "/opt/current/logs/agent.log", want: "/opt/app-2/logs/agent.log"},
+		{name: "relative target with links and parents", path: "/opt/relative/logs", want: "/opt/app-2/logs"},
+		{name: "parent of the root", path: "/../../opt/app-2", want: "/opt/app-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := evalSymlinks(tt.path); err != nil || got != tt.want {
+				t.Errorf("evalSymlinks(%s) = %s, %v, want %s", tt.path, got, err, tt.want)
+			}
+		})
+	}
+	if content := ReadFile("/var/log/escape"); content == "local shadow" {
+		t.Error("ReadFile() followed a relative link out of the root")
+	}
+	if _, err := evalSymlinks("/loop"); err == nil {
+		t.Error("evalSymlinks() expected an error for a link loop")
+	}
+}
+
+func TestRoot_GetProcessEnvVars(t *testing.T) {
+	setFixtureRoot(t)
+	if runtime.GOOS != "linux" {
+		t.Skip("GetProcessEnvVars reads /proc")
+	}
+
+	envVars, err := GetProcessEnvVars(4242)
+	if err != nil {
+		t.Fatalf("GetProcessEnvVars() error = %v", err)
+	}
+	if envVars.All["NEW_RELIC_APP_NAME"] != "fixture app" {
+		t.Errorf("GetProcessEnvVars() = %v, want the environment from <root>/proc/4242/environ", envVars.All)
+	}
+}
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"os"
 
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
@@ -58,13 +57,7 @@ func (t RubyConfigCollect) Execute(options tasks.Options, upstream map[string]ta
 func findGemfiles() ([]string, error) {
 	//return a slice of files (like an array, but any number of elements. Arrays are a defined length)
 	gemfiles := []string{"Gemfile$", "Gemfile.lock"}
-	localPath, err := os.Getwd()
-	if err != nil {
-		log.Debug("Error reading local working directory")
-		return []string{""}, err
-	}
-
-	filepaths := tasks.FindFiles(gemfiles, []string{localPath})
+	filepaths := tasks.FindFiles(gemfiles, tasks.GetDiscoveryDirectories())
 	log.Debug(filepaths)
 	return filepaths, nil
 }
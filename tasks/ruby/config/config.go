@@ -3,6 +3,7 @@ package config
 import (
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
 )
 
 // RegisterWith - will register any plugins in this package
@@ -12,4 +13,7 @@ func RegisterWith(registrationFunc func(tasks.Task, bool)) {
 	registrationFunc(RubyConfigAgent{}, true)
 	registrationFunc(RubyConfigCollect{}, true)
 	registrationFunc(RubyConfigIncompatibleGems{}, true)
+	registrationFunc(config.NewValidateSettingsTask("Ruby/Config/ValidateSettings", "Ruby/Config/Agent", "ruby"), true)
+	config.RegisterSettingsSpec("ruby")
+	registrationFunc(RubyConfigEnvironment{}, true)
 }
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/ruby/env"
+)
+
+var erbEnvRegex = regexp.MustCompile(`<%=\s*ENV\[\s*["']([^"']+)["']\s*\]\s*%>`)
+
+// RubyEnvironmentConfig - the settings of newrelic.yml a Ruby process runs with
+type RubyEnvironmentConfig struct {
+	PID         int32
+	Environment string
+	ConfigFile  string
+	Settings    map[string]string
+	Problems    []string
+}
+
+// RubyConfigEnvironment - resolves the newrelic.yml section each Ruby process reads, and checks it reports data
+type RubyConfigEnvironment struct {
+}
+
+// Identifier - This returns the Category, Subcategory and Name of each task
+func (p RubyConfigEnvironment) Identifier() tasks.Identifier {
+	return tasks.IdentifierFromString("Ruby/Config/Environment")
+}
+
+// Explain - Returns the help text for each individual task
+func (p RubyConfigEnvironment) Explain() string {
+	return "Check the newrelic.yml settings of the environment each Ruby process runs in"
+}
+
+// Dependencies - Returns the dependencies for each task.
+func (p RubyConfigEnvironment) Dependencies() []string {
+	return []string{
+		"Ruby/Config/Agent",
+		"Ruby/Env/Process",
+	}
+}
+
+// Execute - The core work within each task
+func (p RubyConfigEnvironment) Execute(options tasks.Options, upstream map[string]tasks.Result) tasks.Result {
+	configs, ok := upstream["Ruby/Config/Agent"].Payload.([]config.ValidateElement)
+	if !ok || len(configs) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No Ruby agent config files found",
+		}
+	}
+	procs, ok := upstream["Ruby/Env/Process"].Payload.([]env.RubyPidEnvVars)
+	if !ok || len(procs) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No running Ruby processes found",
+		}
+	}
+
+	var resolved []RubyEnvironmentConfig
+	for _, proc := range procs {
+		environment := config.RubyEnvironment(proc.EnvVars)
+		for _, element := range processConfigs(proc, configs) {
+			resolved = append(resolved, resolveRubyEnvironment(proc, environment, element))
+		}
+	}
+	if len(resolved) == 0 {
+		return tasks.Result{
+			Status:  tasks.None,
+			Summary: "No parsed Ruby agent config files found",
+		}
+	}
+
+	var problems []string
+	for _, config := range resolved {
+		for _, problem := range config.Problems {
+			problems = append(problems, fmt.Sprintf("\tpid %d (%s environment) %s: %s", config.PID, config.Environment, config.ConfigFile, problem))
+		}
+	}
+	if len(problems) > 0 {
+		return tasks.Result{
+			Status:  tasks.Warning,
+			Summary: "The Ruby agent won't report data for these processes:\n" + strings.Join(problems, "\n"),
+			URL:     "https://docs.newrelic.com/docs/agents/ruby-agent/configuration/ruby-agent-configuration",
+			Payload: resolved,
+		}
+	}
+	return tasks.Result{
+		Status:  tasks.Success,
+		Summary: "The newrelic.yml environment section of every Ruby process has a license key and monitor_mode enabled",
+		Payload: resolved,
+	}
+}
+
+// processConfigs - the parsed config files in the working directory of a process, or all of them when none are
+func processConfigs(proc env.RubyPidEnvVars, configs []config.ValidateElement) []config.ValidateElement {
+	var parsed, local []config.ValidateElement
+	for _, element := range configs {
+		if element.Status != tasks.Success {
+			continue
+		}
+		parsed = append(parsed, element)
+		if proc.Cwd != "" && strings.HasPrefix(filepath.Clean(element.Config.FilePath), filepath.Clean(proc.Cwd)) {
+			local = append(local, element)
+		}
+	}
+	if len(local) > 0 {
+		return local
+	}
+	return parsed
+}
+
+// resolveRubyEnvironment - the settings of the environment section, which already hold the ones merged in from
+// common, with the ENV references of the ERB tags filled in from the environment of the process
+func resolveRubyEnvironment(proc env.RubyPidEnvVars, environment string, element config.ValidateElement) RubyEnvironmentConfig {
+	resolved := RubyEnvironmentConfig{
+		PID:         proc.Proc.Pid,
+		Environment: environment,
+		ConfigFile:  element.Config.FilePath + element.Config.FileName,
+		Settings:    make(map[string]string),
+	}
+	section := element.ParsedResult.FindKeyByPath("/" + environment)
+	if section.Key == "" {
+		resolved.Problems = append(resolved.Problems, "there is no "+environment+" section, so the agent has no settings")
+	}
+	for _, setting := range section.Children {
+		if setting.IsLeaf() {
+			resolved.Settings[setting.Key] = resolveErbEnv(setting.Value(), proc.EnvVars)
+		}
+	}
+	log.Debug("Resolved", resolved.ConfigFile, "for", environment, resolved.Settings)
+
+	if strings.TrimSpace(resolved.Settings["license_key"]) == "" && proc.EnvVars["NEW_RELIC_LICENSE_KEY"] == "" {
+		resolved.Problems = append(resolved.Problems, "no license_key is set")
+	}
+	monitorMode := resolved.Settings["monitor_mode"]
+	if value, ok := proc.EnvVars["NEW_RELIC_MONITOR_MODE"]; ok {
+		monitorMode = value
+	}
+	if strings.EqualFold(strings.TrimSpace(monitorMode), "false") {
+		resolved.Problems = append(resolved.Problems, "monitor_mode is false")
+	}
+	return resolved
+}
+
+// resolveErbEnv - fills in the <%= ENV["NAME"] %> tags of a value, which render empty when the variable isn't set
+func resolveErbEnv(value string, envVars map[string]string) string {
+	return erbEnvRegex.ReplaceAllStringFunc(value, func(tag string) string {
+		return envVars[erbEnvRegex.FindStringSubmatch(tag)[1]]
+	})
+}
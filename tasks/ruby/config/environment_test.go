@@ -0,0 +1,90 @@
+package config
+
+import (
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks/ruby/env"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ruby/Config/Environment", func() {
+	var p RubyConfigEnvironment
+
+	Describe("Execute()", func() {
+		var (
+			result  tasks.Result
+			envVars map[string]string
+			agent   tasks.Result
+		)
+
+		BeforeEach(func() {
+			validated := config.BaseConfigValidate{}.Execute(tasks.Options{}, map[string]tasks.Result{
+				"Base/Config/Collect": {
+					Status:  tasks.Success,
+					Payload: []config.ConfigElement{{FileName: "newrelic.yml", FilePath: "../../fixtures/ruby/config/"}},
+				},
+			})
+			agent = tasks.Result{Status: tasks.Success, Payload: validated.Payload}
+			envVars = map[string]string{"LICENSE_KEY_VAL_RUBY": "license-key-val-ruby"}
+		})
+
+		JustBeforeEach(func() {
+			result = p.Execute(tasks.Options{}, map[string]tasks.Result{
+				"Ruby/Config/Agent": agent,
+				"Ruby/Env/Process": {Status: tasks.Success, Payload: []env.RubyPidEnvVars{
+					{Proc: tasks.RunningProcess{Pid: 1300}, Cwd: "/srv/storefront", EnvVars: envVars},
+				}},
+			})
+		})
+
+		Context("when no Ruby process is running", func() {
+			It("should return None", func() {
+				result = p.Execute(tasks.Options{}, map[string]tasks.Result{
+					"Ruby/Config/Agent": agent,
+					"Ruby/Env/Process":  {Status: tasks.Success},
+				})
+				Expect(result.Status).To(Equal(tasks.None))
+			})
+		})
+
+		Context("when the process runs in production", func() {
+			BeforeEach(func() {
+				envVars["RAILS_ENV"] = "production"
+			})
+			It("should return Success with the settings merged in from common", func() {
+				Expect(result.Status).To(Equal(tasks.Success))
+				resolved := result.Payload.([]RubyEnvironmentConfig)
+				Expect(resolved[0].Environment).To(Equal("production"))
+				Expect(resolved[0].Settings["license_key"]).To(Equal("license-key-val-ruby"))
+			})
+		})
+
+		Context("when no environment variable is set", func() {
+			It("should return Warning for the monitor_mode of the development section", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Payload.([]RubyEnvironmentConfig)[0].Problems).To(Equal([]string{"monitor_mode is false"}))
+			})
+		})
+
+		Context("when the license key variable is not set", func() {
+			BeforeEach(func() {
+				envVars = map[string]string{"RACK_ENV": "staging"}
+			})
+			It("should return Warning for the missing license key", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Payload.([]RubyEnvironmentConfig)[0].Problems).To(Equal([]string{"no license_key is set"}))
+			})
+		})
+
+		Context("when the environment has no section", func() {
+			BeforeEach(func() {
+				envVars["NEW_RELIC_ENV"] = "qa"
+			})
+			It("should return Warning", func() {
+				Expect(result.Status).To(Equal(tasks.Warning))
+				Expect(result.Summary).To(ContainSubstring("there is no qa section"))
+			})
+		})
+	})
+})
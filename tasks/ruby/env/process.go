@@ -1,8 +1,6 @@
 package env
 
 import (
-	"github.com/shirou/gopsutil/process"
-
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
 	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
 )
@@ -11,8 +9,9 @@ import (
 type RubyEnvProcess struct {
 }
 
-type rubyPidEnvVars struct {
-	Proc    process.Process
+// RubyPidEnvVars - a running Ruby process, its working directory and the environment variables it runs with
+type RubyPidEnvVars struct {
+	Proc    tasks.RunningProcess
 	Cwd     string
 	EnvVars map[string]string
 }
@@ -52,10 +51,15 @@ func (t RubyEnvProcess) Execute(options tasks.Options, upstream map[string]tasks
 	//Get list of running processes
 	processes := getRubyProcesses()
 
-	var procs []rubyPidEnvVars
+	var procs []RubyPidEnvVars
 	for _, process := range processes {
 		cwd, _ := process.Cwd()
-		procs = append(procs, rubyPidEnvVars{Proc: process, Cwd: cwd, EnvVars: envVars})
+		// the shell's variables stand in for those of a process we aren't allowed to read
+		procEnvVars := envVars
+		if processEnv, err := tasks.GetProcessEnvVars(process.Pid); err == nil {
+			procEnvVars = processEnv.All
+		}
+		procs = append(procs, RubyPidEnvVars{Proc: process, Cwd: cwd, EnvVars: procEnvVars})
 	}
 	result.Payload = procs
 	result.Status = tasks.Success
@@ -65,7 +69,7 @@ func (t RubyEnvProcess) Execute(options tasks.Options, upstream map[string]tasks
 	return
 }
 
-func getRubyProcesses() []process.Process {
+func getRubyProcesses() []tasks.RunningProcess {
 	processes, err := tasks.FindProcessByName("ruby")
 	if err != nil {
 		log.Debug("Failed to get list of processes")
@@ -0,0 +1,45 @@
+package env
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+func TestRubyEnvProcess_Execute(t *testing.T) {
+	processes, err := tasks.NewFakeProcessSource([]byte(`
+processes:
+  - pid: 1300
+    name: ruby
+    cwd: /srv/storefront
+    env: {RAILS_ENV: production}
+  - pid: 1301
+    name: ruby
+    cwd: /srv/admin
+    env: {RAILS_ENV: staging, NEW_RELIC_MONITOR_MODE: "false"}
+  - pid: 1400
+    name: java
+    cwd: /opt/billing
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tasks.UseProcessSource(processes)()
+
+	envVars := map[string]string{"RAILS_ENV": "development"}
+	upstream := map[string]tasks.Result{
+		"Ruby/Config/Agent":       {Status: tasks.Success},
+		"Base/Env/CollectEnvVars": {Status: tasks.Info, Payload: envVars},
+	}
+
+	result := RubyEnvProcess{}.Execute(tasks.Options{}, upstream)
+
+	want := []RubyPidEnvVars{
+		{Proc: tasks.RunningProcess{Pid: 1300}, Cwd: "/srv/storefront", EnvVars: map[string]string{"RAILS_ENV": "production"}},
+		{Proc: tasks.RunningProcess{Pid: 1301}, Cwd: "/srv/admin", EnvVars: map[string]string{"RAILS_ENV": "staging", "NEW_RELIC_MONITOR_MODE": "false"}},
+	}
+	if result.Status != tasks.Success || !reflect.DeepEqual(result.Payload, want) {
+		t.Errorf("Execute() = %+v, want a Success with the ruby processes and their own environment %+v", result, want)
+	}
+}
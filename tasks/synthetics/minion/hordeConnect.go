@@ -30,6 +30,11 @@ func (p SyntheticsMinionHordeConnect) Explain() string {
 	return "Check network connection to New Relic Synthetics horde endpoint for private minions (legacy)"
 }
 
+// RequiresNetwork - This task connects to Horde, so it is skipped with -offline
+func (p SyntheticsMinionHordeConnect) RequiresNetwork() bool {
+	return true
+}
+
 // Dependencies - Returns the dependencies for ech task. When executed by name each dependency will be executed as well and the results from that dependency passed in to the downstream task
 func (p SyntheticsMinionHordeConnect) Dependencies() []string {
 	return []string{
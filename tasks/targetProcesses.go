@@ -0,0 +1,101 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+)
+
+// targetPids are the processes selected with -pid. When empty every process is diagnosed
+var targetPids []int32
+
+// SetTargetPids limits process discovery to the given processes, as selected with -pid. It returns an error if one of them isn't running
+func SetTargetPids(pids []int32) error {
+	for _, pid := range pids {
+		if _, err := Processes.Name(pid); err != nil {
+			return fmt.Errorf("no running process found with PID %d", pid)
+		}
+	}
+	targetPids = pids
+	return nil
+}
+
+// TargetPids returns the processes selected with -pid, or nil if every process is diagnosed
+func TargetPids() []int32 {
+	return targetPids
+}
+
+// IsTargetPid returns true if process discovery includes the process: any process unless -pid was used
+func IsTargetPid(pid int32) bool {
+	if len(targetPids) == 0 {
+		return true
+	}
+	for _, targetPid := range targetPids {
+		if targetPid == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeTargetProcesses names the processes selected with -pid, e.g. "1234 (java -jar app.jar)"
+func DescribeTargetProcesses() []string {
+	var descriptions []string
+	for _, pid := range targetPids {
+		description := strconv.Itoa(int(pid))
+		if cmdline, err := (RunningProcess{Pid: pid}).Cmdline(); err == nil && cmdline != "" {
+			description += " (" + cmdline + ")"
+		} else if name, err := (RunningProcess{Pid: pid}).Name(); err == nil {
+			description += " (" + name + ")"
+		}
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
+
+// GetDiscoveryDirectories returns the directories configs and logs are searched for in: the working directories of the processes selected
+// with -pid, or the current directory otherwise
+func GetDiscoveryDirectories() []string {
+	if len(targetPids) == 0 {
+		localPath, err := os.Getwd()
+		if err != nil {
+			log.Debug("Error reading local working directory")
+		}
+		return []string{localPath}
+	}
+
+	var directories []string
+	for _, pid := range targetPids {
+		cwd, err := (RunningProcess{Pid: pid}).Cwd()
+		if err != nil || cwd == "" {
+			log.Debug("Error reading working directory of process", pid, err)
+			continue
+		}
+		if !ContainsString(directories, cwd) {
+			directories = append(directories, cwd)
+		}
+	}
+	return directories
+}
+
+// GetTargetProcessEnvVars returns the environment variables the processes selected with -pid were started with, combined.
+// It is empty if -pid wasn't used
+func GetTargetProcessEnvVars() EnvironmentVariables {
+	envVars := EnvironmentVariables{All: make(map[string]string), Scope: Process}
+	for _, pid := range targetPids {
+		processEnvVars, err := GetProcessEnvVars(pid)
+		if err != nil {
+			log.Debug(err)
+			continue
+		}
+		for key, value := range processEnvVars.All {
+			envVars.All[key] = value
+		}
+	}
+	if len(targetPids) == 1 {
+		envVars.PID = targetPids[0]
+	}
+	return envVars
+}
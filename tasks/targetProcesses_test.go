@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+)
+
+// setFixtureTargets selects processes of the fixture process table as if they were passed with -pid, until the test ends
+func setFixtureTargets(t *testing.T, pids ...int32) {
+	useFixtureProcesses(t)
+	if err := SetTargetPids(pids); err != nil {
+		t.Fatalf("SetTargetPids() error = %v", err)
+	}
+	t.Cleanup(func() { SetTargetPids(nil) })
+}
+
+func TestSetTargetPids_notRunning(t *testing.T) {
+	useFixtureProcesses(t)
+
+	if err := SetTargetPids([]int32{1300, 9999}); err == nil {
+		t.Error("SetTargetPids() expected an error for a process that isn't running")
+	}
+	if TargetPids() != nil {
+		t.Errorf("TargetPids() = %v after an invalid PID, want them unset", TargetPids())
+	}
+}
+
+func TestFindProcessByName_targetPids(t *testing.T) {
+	setFixtureTargets(t, 1300)
+
+	if got, _ := FindProcessByName("java"); got != nil {
+		t.Errorf("FindProcessByName(java) = %v, want no process outside of -pid", got)
+	}
+	if got, _ := FindProcessByName("ruby"); !reflect.DeepEqual(got, []RunningProcess{{Pid: 1300}}) {
+		t.Errorf("FindProcessByName(ruby) = %v, want the selected process", got)
+	}
+}
+
+func TestGetDiscoveryDirectories_targetPids(t *testing.T) {
+	setFixtureTargets(t, 1200, 1300)
+
+	want := []string{"/opt/billing", "/srv/storefront"}
+	if got := GetDiscoveryDirectories(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDiscoveryDirectories() = %v, want %v", got, want)
+	}
+}
+
+func TestGetTargetProcessEnvVars(t *testing.T) {
+	setFixtureTargets(t, 1300)
+
+	envVars := GetTargetProcessEnvVars()
+	if envVars.PID != 1300 || envVars.Scope != Process {
+		t.Errorf("GetTargetProcessEnvVars() = %+v, want the variables of process 1300", envVars)
+	}
+	if envVars.All["RAILS_ENV"] != "production" {
+		t.Errorf("GetTargetProcessEnvVars() RAILS_ENV = %q, want production", envVars.All["RAILS_ENV"])
+	}
+}
+
+func TestDescribeTargetProcesses(t *testing.T) {
+	setFixtureTargets(t, 1300)
+
+	want := []string{"1300 (ruby bin/rails server)"}
+	if got := DescribeTargetProcesses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DescribeTargetProcesses() = %v, want %v", got, want)
+	}
+}
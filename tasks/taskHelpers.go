@@ -6,14 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"reflect"
 	"regexp"
-	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,7 +19,6 @@ import (
 
 	"github.com/newrelic/newrelic-diagnostics-cli/helpers/httpHelper"
 	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
-	"github.com/shirou/gopsutil/process"
 )
 
 // OsFunc - for dependency injecting osGetwd
@@ -29,17 +26,18 @@ type OsFunc func() (string, error)
 
 // FindFiles - looks for files in the standard search paths that match the given string.
 // automatically dedupes matches and attempts to resolve any symlinks in the paths slice.
+// When a root is set with -root the paths are searched inside it and the files found are returned as host paths, without the root prefix.
 func FindFiles(patterns []string, paths []string) []string {
 	// map to automatically dedupe file matches
 	foundFiles := make(map[string]interface{})
 
 	for _, path := range paths {
 		//Check if path is a symlink and if so, set symPath as path
-		symPath, err := filepath.EvalSymlinks(path)
+		symPath, err := evalSymlinks(path)
 		if err == nil {
 			path = symPath
 		}
-		filepath.Walk(path, func(pathInfo string, fileInfo os.FileInfo, walkErr error) error {
+		Walk(RootPath(path), func(pathInfo string, fileInfo os.FileInfo, walkErr error) error {
 			if walkErr != nil {
 				// log the error and move on to next item to be walked
 				log.Debug("Error when walking filesystem:", walkErr)
@@ -51,7 +49,7 @@ func FindFiles(patterns []string, paths []string) []string {
 					var validID = regexp.MustCompile(pattern)
 					match := validID.MatchString(fileInfo.Name())
 					if match {
-						foundFiles[pathInfo] = struct{}{} // empty struct is smallest memory footprint
+						foundFiles[hostPath(pathInfo)] = struct{}{} // empty struct is smallest memory footprint
 					}
 				}
 			}
@@ -69,21 +67,25 @@ func FindFiles(patterns []string, paths []string) []string {
 }
 
 // FindProcessByNameFunc - allows FindProcessByName to be dependency injected
-type FindProcessByNameFunc func(string) ([]process.Process, error)
+type FindProcessByNameFunc func(string) ([]RunningProcess, error)
 
 // FindProcessByName - returns array of processes matching string name, or an error if we can't gather a list of processes, or an empty slice and nil if we found no processes with that specific name
-func FindProcessByName(name string) ([]process.Process, error) {
-	var processList []process.Process
+func FindProcessByName(name string) ([]RunningProcess, error) {
+	var processList []RunningProcess
 
-	processIDs, err := process.Pids()
+	processIDs, err := Processes.Pids()
 
 	if err != nil {
 		log.Debug("error", err)
 		return processList, err
 	}
 	for _, PID := range processIDs {
+		// -pid limits discovery to the selected processes
+		if !IsTargetPid(PID) {
+			continue
+		}
 
-		processID := process.Process{Pid: PID}
+		processID := RunningProcess{Pid: PID}
 		processName, err := processID.Name()
 
 		if err != nil {
@@ -106,7 +108,7 @@ func FindProcessByName(name string) ([]process.Process, error) {
 
 //ReadFile - reads file from path to string
 func ReadFile(file string) string {
-	content, err := ioutil.ReadFile(file)
+	content, err := ReadFileBytes(file)
 	if err != nil {
 		log.Debug("error reading file", err)
 		return ""
@@ -119,7 +121,7 @@ type FileExistsFunc func(string) bool
 
 //FileExists - checks for existence of file
 func FileExists(name string) bool {
-	if _, err := os.Stat(name); err != nil {
+	if _, err := FS.Stat(RootPath(name)); err != nil {
 		if os.IsNotExist(err) {
 			return false
 		}
@@ -213,6 +215,9 @@ type ValidateBlob struct {
 	Children []ValidateBlob
 }
 
+// DynamicValue - the source of a config value that is only known when the agent runs, e.g. process.env.NEW_RELIC_APP_NAME
+type DynamicValue string
+
 //ByChild is a sort helper to sort an array of ValidateBlobs by their child nodes
 type ByChild []ValidateBlob
 
@@ -266,6 +271,8 @@ func (v ValidateBlob) Value() string {
 	switch value := v.RawValue.(type) {
 	case string:
 		return value
+	case DynamicValue:
+		return string(value)
 	case bool:
 		if value {
 			return "true"
@@ -306,6 +313,12 @@ func (v ValidateBlob) Value() string {
 	return ""
 }
 
+// IsDynamic - returns true if the value is an expression the agent evaluates at runtime rather than a literal
+func (v ValidateBlob) IsDynamic() bool {
+	_, ok := v.RawValue.(DynamicValue)
+	return ok
+}
+
 // PathAndKey - returns the Path and Key of a ValidateBlob as a string
 func (v ValidateBlob) PathAndKey() string {
 	return v.Path + "/" + v.Key
@@ -689,33 +702,16 @@ func GetProcessEnvVars(pid int32) (envVars EnvironmentVariables, retErr error) {
 	envVars.Scope = Process
 	envVars.PID = pid
 
-	switch runtime.GOOS {
-	case "linux":
-		pidString := strconv.FormatInt(int64(pid), 10)
-		path := filepath.Join("/proc", pidString, "environ")
-		environFile, err := ioutil.ReadFile(path)
-		if err != nil {
-			errorString := "Error reading process env variables: " + err.Error()
-			log.Debug(errorString)
-			retErr = errors.New(errorString)
-			return
-		}
-		envVarString := string(environFile)
-		lines := strings.Split(envVarString, "\x00")
-		for _, line := range lines {
-			split := strings.Split(line, "=")
-			if len(split) > 1 {
-				name := split[0]
-				val := split[1]
-				envVars.All[name] = val
-			}
-		}
-	default:
-		errorString := "GetProcessEnvVars is not implemented for " + runtime.GOOS
+	all, err := Processes.Environ(pid)
+	if err != nil {
+		errorString := "Error reading process env variables: " + err.Error()
 		log.Debug(errorString)
 		retErr = errors.New(errorString)
 		return
 	}
+	for name, val := range all {
+		envVars.All[name] = val
+	}
 
 	return
 }
@@ -740,7 +736,7 @@ func GetShellEnvVars() (envVars EnvironmentVariables, retErr error) {
 }
 
 // GetCmdLineArgs is a wrapper for Process.Cmdline
-func GetCmdLineArgs(proc process.Process) ([]string, error) {
+func GetCmdLineArgs(proc RunningProcess) ([]string, error) {
 	return proc.CmdlineSlice() //Keep in mind that If an single argument looked like this: -Dnewrelic.config.app_name="my appname", Go for darwin will still separate by spaces and will split it into 2 arguments even if they were enclosed by quotes.
 }
 
@@ -1042,7 +1038,7 @@ func ValidatePaths(paths []string) []CollectFileStatus {
 
 //ValidatePath takes a string to check if customer is providing us with paths(that come from either env var or config file) from which can collect a file. It returns a FileToCollect which informs if the file is invalid and the error we found
 func ValidatePath(path string) CollectFileStatus {
-	fileInfo, err := os.Stat(path)
+	fileInfo, err := FS.Stat(RootPath(path))
 	if err != nil {
 		return CollectFileStatus{path, false, err}
 	}
@@ -1051,7 +1047,7 @@ func ValidatePath(path string) CollectFileStatus {
 		return CollectFileStatus{path, false, errors.New("Is directory and not a path to a file")}
 	}
 
-	file, err := os.Open(path)
+	file, err := FS.Open(RootPath(path))
 	if err != nil {
 		return CollectFileStatus{path, false, err}
 	}
@@ -14,6 +14,8 @@ func (s Status) GetColor() color.Color {
 		return color.LightBlue
 	case Info:
 		return color.White
+	case Skipped:
+		return color.Gray
 	default:
 		return color.Clear
 	}
@@ -21,7 +23,7 @@ func (s Status) GetColor() color.Color {
 
 //StatusToString takes in integer, returns relevant Status statusEnum in a human readable string.
 func (s Status) StatusToString() string {
-	statuses := []string{"None", "Success", "Warning", "Failure", "Error", "Info"}
+	statuses := []string{"None", "Success", "Warning", "Failure", "Error", "Info", "Skipped"}
 	return statuses[s]
 }
 
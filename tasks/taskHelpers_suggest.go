@@ -0,0 +1,199 @@
+package tasks
+
+import (
+	"sort"
+	"strings"
+)
+
+// KnownKeys - the settings of an agent config file, used to suggest corrections for keys the agent won't read
+type KnownKeys struct {
+	// Sections are the top-level keys holding the settings, the defaults first, e.g. common in newrelic.yml
+	Sections []string
+	// Keys are the setting names relative to a section, nested keys joined with dots, e.g. transaction_tracer.record_sql
+	Keys []string
+}
+
+// KeySuggestion - a key of a config file that the agent won't read, and the key it was most likely meant to be
+type KeySuggestion struct {
+	Key        string
+	Suggestion string
+	Reason     string
+}
+
+// SuggestKeys - walks the blob and returns a suggestion for each key that looks like a misspelled known key, or
+// is a known key outside the sections holding the settings. Keys that are merely unknown are not reported.
+func (v ValidateBlob) SuggestKeys(known KnownKeys) []KeySuggestion {
+	keys := make(map[string]bool)
+	parents := make(map[string]bool)
+	for _, key := range known.Keys {
+		keys[key] = true
+		// the parents of nested keys are known as well, e.g. transaction_tracer
+		for i := strings.Index(key, "."); i > 0; i = nextDot(key, i) {
+			parents[key[:i]] = true
+		}
+	}
+	var candidates []string
+	for _, set := range []map[string]bool{keys, parents} {
+		for key := range set {
+			candidates = append(candidates, key)
+		}
+	}
+	sort.Strings(candidates)
+
+	var suggestions []KeySuggestion
+	// sections usually inherit the defaults with YAML merge keys, so each misspelling is reported for one section only
+	reported := make(map[string]bool)
+	for _, child := range v.Children {
+		if isSection(child.Key, known.Sections) && !child.IsLeaf() {
+			for _, suggestion := range suggestKeys(child.Children, child.Key, "", keys, parents, candidates) {
+				relative := strings.TrimPrefix(suggestion.Key, child.Key+".")
+				if !reported[relative] {
+					reported[relative] = true
+					suggestions = append(suggestions, suggestion)
+				}
+			}
+			continue
+		}
+		if len(known.Sections) > 0 && (keys[child.Key] || parents[child.Key]) {
+			suggestions = append(suggestions, KeySuggestion{
+				Key:        child.Key,
+				Suggestion: known.Sections[0] + "." + child.Key,
+				Reason:     "is outside of the " + known.Sections[0] + " section, so the agent doesn't read it",
+			})
+			continue
+		}
+		if len(known.Sections) > 0 {
+			if section := closestKey(child.Key, known.Sections); section != "" && !child.IsLeaf() {
+				suggestions = append(suggestions, KeySuggestion{Key: child.Key, Suggestion: section, Reason: "is not a known section"})
+				continue
+			}
+		}
+		for _, suggestion := range suggestKeys([]ValidateBlob{child}, "", "", keys, parents, candidates) {
+			if len(known.Sections) > 0 {
+				// the settings belong in a section, not only spelled correctly
+				suggestion.Suggestion = known.Sections[0] + "." + suggestion.Suggestion
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions
+}
+
+func suggestKeys(blobs []ValidateBlob, section string, parent string, keys map[string]bool, parents map[string]bool, candidates []string) []KeySuggestion {
+	var suggestions []KeySuggestion
+	for _, blob := range blobs {
+		name := blob.Key
+		if parent != "" {
+			name = parent + "." + blob.Key
+		}
+		if keys[name] || hasKnownPrefix(name, keys) {
+			// settings can hold maps of their own, e.g. labels.team
+			continue
+		}
+		if parents[name] {
+			suggestions = append(suggestions, suggestKeys(blob.Children, section, name, keys, parents, candidates)...)
+			continue
+		}
+		if closest := closestKey(name, candidates); closest != "" {
+			suggestions = append(suggestions, KeySuggestion{
+				Key:        sectionKey(section, name),
+				Suggestion: sectionKey(section, closest),
+				Reason:     "is not a known setting",
+			})
+		}
+	}
+	return suggestions
+}
+
+func nextDot(key string, i int) int {
+	next := strings.Index(key[i+1:], ".")
+	if next < 0 {
+		return -1
+	}
+	return i + 1 + next
+}
+
+func isSection(key string, sections []string) bool {
+	for _, section := range sections {
+		if key == section {
+			return true
+		}
+	}
+	return false
+}
+
+func sectionKey(section string, key string) string {
+	if section == "" {
+		return key
+	}
+	return section + "." + key
+}
+
+func hasKnownPrefix(name string, keys map[string]bool) bool {
+	for i := strings.Index(name, "."); i > 0; i = nextDot(name, i) {
+		if keys[name[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// closestKey - returns the candidate within a couple of typos of the key, or an empty string. Very short keys are
+// skipped since almost any other short key is within one typo of them.
+func closestKey(key string, candidates []string) string {
+	if len(key) < 4 {
+		return ""
+	}
+	maxEdits := len(key) / 4
+	if maxEdits > 2 {
+		maxEdits = 2
+	}
+	if maxEdits < 1 {
+		maxEdits = 1
+	}
+	closest := ""
+	for _, candidate := range candidates {
+		if distance := editDistance(strings.ToLower(key), candidate); distance <= maxEdits {
+			if closest == "" || distance < editDistance(strings.ToLower(key), closest) {
+				closest = candidate
+			}
+		}
+	}
+	return closest
+}
+
+// editDistance - the number of insertions, deletions, substitutions or swaps of adjacent characters turning a into b
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
@@ -0,0 +1,108 @@
+package tasks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateBlob_SuggestKeys(t *testing.T) {
+	leaf := func(path string, key string, value interface{}) ValidateBlob {
+		return ValidateBlob{Key: key, Path: path, RawValue: value}
+	}
+	node := func(path string, key string, children ...ValidateBlob) ValidateBlob {
+		return ValidateBlob{Key: key, Path: path, Children: children}
+	}
+	yml := KnownKeys{
+		Sections: []string{"common", "production"},
+		Keys:     []string{"license_key", "app_name", "log_level", "labels", "transaction_tracer.record_sql"},
+	}
+	ini := KnownKeys{
+		Keys: []string{"license_key", "app_name", "log_level", "transaction_tracer.record_sql"},
+	}
+
+	tests := []struct {
+		name  string
+		blob  ValidateBlob
+		known KnownKeys
+		want  []KeySuggestion
+	}{
+		{
+			name: "known keys in a section",
+			blob: node("", "", node("", "common",
+				leaf("/common", "license_key", "abc"),
+				node("/common", "labels", leaf("/common/labels", "team", "api")),
+				node("/common", "transaction_tracer", leaf("/common/transaction_tracer", "record_sql", "raw")),
+			)),
+			known: yml,
+		},
+		{
+			name: "misspelled keys",
+			blob: node("", "", node("", "production",
+				leaf("/production", "licence_key", "abc"),
+				leaf("/production", "app_nmae", "app"),
+				node("/production", "transaction_tracer", leaf("/production/transaction_tracer", "record_sq", "raw")),
+				leaf("/production", "something_else", "value"),
+			)),
+			known: yml,
+			want: []KeySuggestion{
+				{Key: "production.licence_key", Suggestion: "production.license_key", Reason: "is not a known setting"},
+				{Key: "production.app_nmae", Suggestion: "production.app_name", Reason: "is not a known setting"},
+				{Key: "production.transaction_tracer.record_sq", Suggestion: "production.transaction_tracer.record_sql", Reason: "is not a known setting"},
+			},
+		},
+		{
+			name: "keys outside of the sections",
+			blob: node("", "",
+				node("", "comon", leaf("/comon", "app_name", "app")),
+				leaf("", "license_key", "abc"),
+				leaf("", "log_levle", "info"),
+			),
+			known: yml,
+			want: []KeySuggestion{
+				{Key: "comon", Suggestion: "common", Reason: "is not a known section"},
+				{Key: "license_key", Suggestion: "common.license_key", Reason: "is outside of the common section, so the agent doesn't read it"},
+				{Key: "log_levle", Suggestion: "common.log_level", Reason: "is not a known setting"},
+			},
+		},
+		{
+			name: "flat dotted keys",
+			blob: node("", "",
+				leaf("", "transaction_tracer.record_sql", "raw"),
+				leaf("", "Log_Level", "info"),
+				leaf("", "transaction_tracr.record_sql", "raw"),
+				leaf("", "port", "80"),
+			),
+			known: ini,
+			want: []KeySuggestion{
+				{Key: "Log_Level", Suggestion: "log_level", Reason: "is not a known setting"},
+				{Key: "transaction_tracr.record_sql", Suggestion: "transaction_tracer.record_sql", Reason: "is not a known setting"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.blob.SuggestKeys(tt.known); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateBlob.SuggestKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"license_key", "license_key", 0},
+		{"licence_key", "license_key", 1},
+		{"app_nmae", "app_name", 1},
+		{"log_levle", "log_level", 1},
+		{"loglevel", "log_level", 1},
+		{"appname", "license_key", 9},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -201,6 +201,14 @@ func GetProcInfoByName(name string) ([]ProcInfoStruct, error) {
 	var procInfoStruct []ProcInfoStruct
 	query := "SELECT Name,CommandLine,ExecutablePath,ProcessId FROM Win32_Process WHERE name LIKE \"" + name + "\""
 	err := wmi.Query(query, &procInfoStruct)
+	// only keep the processes selected with -pid
+	var targetProcInfos []ProcInfoStruct
+	for _, procInfo := range procInfoStruct {
+		if IsTargetPid(int32(procInfo.ProcessId)) {
+			targetProcInfos = append(targetProcInfos, procInfo)
+		}
+	}
+	procInfoStruct = targetProcInfos
 	if err == nil && len(procInfoStruct) < 1 {
 		err = errors.New("No matching process found ")
 		return procInfoStruct, err
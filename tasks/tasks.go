@@ -15,6 +15,7 @@ type Result struct {
 	URL         string             // a URL pointing to documention about this task; "required" on Warning or Failure, desireable on any status
 	FilesToCopy []FileCopyEnvelope // List of files identified by the task to be included in zip file
 	Payload     interface{}        // task defined list of returned data. This is what is used by downstream tasks so data format agreements are between tasks
+	Fixes       []ConfigFix        `json:",omitempty"` // mechanical changes to config files that resolve what the task found, written as diffs to nrdiag-fixes/
 }
 
 // Status statusEnum listing of valid values for status
@@ -34,6 +35,8 @@ const (
 	Error
 	//Info - A task has completed, but it has only collected information, no "judgements" here
 	Info
+	//Skipped - the task was not executed because it needs network access and -offline was used
+	Skipped
 )
 
 // Equals verifies two Result objects match each other. It purposefully does not verify payloads match exact since ordering may be non-deterministic but all other values are compared.
@@ -57,12 +60,12 @@ func (r Result) Equals(result Result) bool {
 }
 
 func (r Result) IsFailure() bool {
-	return r.Status != None && r.Status != Success && r.Status != Info
+	return r.Status != None && r.Status != Success && r.Status != Info && r.Status != Skipped
 }
 
 // HasPayload will check if a upstream task.Result has a payload we can work with. Notice status 'Warning' is not included here and it's because a lot of the time it has payload. But HasPayload may not be applicable to some tasks.
 func (r Result) HasPayload() bool {
-	return r.Status != None && r.Status != Error && r.Status != Failure
+	return r.Status != None && r.Status != Error && r.Status != Failure && r.Status != Skipped
 }
 
 //MarshalJSON - custom JSON marshaling for this task, in this case we ignore the parsed config
@@ -78,6 +81,18 @@ type Task interface {
 	Execute(Options, map[string]Result) Result
 }
 
+// NetworkTask is implemented by tasks that declare whether they need to reach a remote endpoint, e.g. a collector or
+// a cloud metadata service, to get a result. Tasks that need the network are skipped with -offline
+type NetworkTask interface {
+	RequiresNetwork() bool
+}
+
+// RequiresNetwork returns true if the task declares that it needs network access. Tasks that don't implement NetworkTask don't
+func RequiresNetwork(t Task) bool {
+	networkTask, ok := t.(NetworkTask)
+	return ok && networkTask.RequiresNetwork()
+}
+
 //ByIdentifier is a sort helper to sort an array of tasks by their identifiers
 type ByIdentifier []Task
 
@@ -0,0 +1,27 @@
+package main
+
+import (
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/output"
+	"github.com/newrelic/newrelic-diagnostics-cli/output/color"
+)
+
+// processVerify checks a nrdiag zip file against its manifest and returns the exit code for the result
+func processVerify(archivePath string) int {
+	problems, err := output.VerifyArchive(archivePath)
+	if err != nil {
+		log.Infof("Unable to verify %s: %s\n", archivePath, err.Error())
+		return exitCodeBadInput
+	}
+
+	if len(problems) == 0 {
+		log.Info(color.ColorString(color.Green, "All files in "+archivePath+" match the manifest."))
+		return exitCodeSuccess
+	}
+
+	log.Info(color.ColorString(color.Red, "The following files in "+archivePath+" do not match the manifest:"))
+	for _, problem := range problems {
+		log.Info(" - " + problem)
+	}
+	return exitCodeVerifyError
+}
@@ -114,7 +114,8 @@ func processAutoVersionCheck(log logger.API, getOnlineVersion func(logger.API) s
 func processVersion(log logger.API, promptUser func(input string) bool, getOnlineVersion func(logger.API) string, getLatestVersion func(logger.API) error) {
 	logVersionString(log)
 
-	if config.Flags.SkipVersionCheck {
+	// -offline also sets SkipVersionCheck, it is checked here as well so -version never reaches the network with it
+	if config.Flags.SkipVersionCheck || config.Flags.Offline {
 		return
 	}
 
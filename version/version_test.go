@@ -138,3 +138,14 @@ func Test_processVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_processVersion_offline(t *testing.T) {
+	config.Flags.Offline = true
+	defer func() { config.Flags.Offline = false }()
+
+	getOnlineVersionOffline := func(logger.API) string {
+		t.Error("The online version was looked up with -offline")
+		return ""
+	}
+	processVersion(logCapture, promptUserAllow, getOnlineVersionOffline, getLatestVersionTest)
+}
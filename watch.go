@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/config"
+	"github.com/newrelic/newrelic-diagnostics-cli/engine"
+	log "github.com/newrelic/newrelic-diagnostics-cli/logger"
+	"github.com/newrelic/newrelic-diagnostics-cli/output"
+	"github.com/newrelic/newrelic-diagnostics-cli/output/color"
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+	baseConfig "github.com/newrelic/newrelic-diagnostics-cli/tasks/base/config"
+)
+
+// watchHistoryName is the file every status change seen with -watch is appended to. It is kept across runs of nrdiag
+const watchHistoryName = "nrdiag-watch-history.json"
+
+// watchHistoryLimit is the number of status changes kept in the history file, older ones are dropped
+const watchHistoryLimit = 1000
+
+// statusTransition is a change in the status of a task between two -watch runs, stored as one JSON line in the history file
+type statusTransition struct {
+	Time       time.Time
+	Run        int
+	Identifier string
+	From       string
+	To         string
+	Summary    string
+	status     tasks.Status
+}
+
+// watch re-runs the tasks that produced firstResults every -watch interval, adding every run's results to the zip file.
+// It returns the results of the last run and the results of every run combined.
+func watch(zipfile *output.Archive, options tasks.Options, overrides []override, firstResults []registration.TaskResult) ([]registration.TaskResult, []registration.TaskResult) {
+	output.CopyWatchIntervalToZip(zipfile, 1, firstResults)
+
+	// rerun exactly the tasks from the first run, so suites and wildcards don't need to be resolved again
+	var identifiers []string
+	for _, result := range firstResults {
+		identifiers = append(identifiers, result.Task.Identifier().String())
+	}
+
+	watchOptions := engine.Options{
+		Tasks:       identifiers,
+		TaskOptions: make(map[string]string),
+		Overrides:   toEngineOverrides(overrides),
+		Offline:     config.Flags.Offline,
+	}
+	for key, value := range options.Options {
+		watchOptions.TaskOptions[key] = value
+	}
+	// files are only collected on the first run, so there is no need to ask about secure files again
+	watchOptions.TaskOptions[baseConfig.SecureFilesOption] = ""
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	log.Infof("\nWatching %d tasks every %s, press Ctrl+C to stop\n", len(identifiers), config.Flags.Watch)
+
+	allResults := firstResults
+	previous := firstResults
+	runStart := time.Now()
+	for run := 2; config.Flags.WatchCount == 0 || run <= config.Flags.WatchCount; run++ {
+		select {
+		case <-interrupt:
+			log.Info("Stopping watch")
+			return previous, allResults
+		case <-time.After(config.Flags.Watch - time.Since(runStart)):
+		}
+
+		runStart = time.Now()
+		results, err := engine.Run(context.Background(), watchOptions)
+		if err != nil {
+			log.Info("Error running watched tasks:", err)
+		}
+		output.CopyWatchIntervalToZip(zipfile, run, results)
+
+		transitions := findTransitions(previous, results, run, runStart)
+		printTransitions(transitions)
+		if err := appendWatchHistory(filepath.Join(config.Flags.OutputPath, watchHistoryName), transitions, watchHistoryLimit); err != nil {
+			log.Info("Error writing watch history:", err)
+		}
+
+		allResults = append(allResults, results...)
+		previous = results
+	}
+	return previous, allResults
+}
+
+// findTransitions lists the tasks whose status differs from the previous run. Tasks missing from the previous run count as None
+func findTransitions(previous []registration.TaskResult, current []registration.TaskResult, run int, runTime time.Time) []statusTransition {
+	previousStatus := make(map[string]tasks.Status)
+	for _, result := range previous {
+		previousStatus[result.Task.Identifier().String()] = result.Result.Status
+	}
+
+	var transitions []statusTransition
+	for _, result := range current {
+		identifier := result.Task.Identifier().String()
+		from := previousStatus[identifier]
+		if from == result.Result.Status {
+			continue
+		}
+		transitions = append(transitions, statusTransition{
+			Time:       runTime,
+			Run:        run,
+			Identifier: identifier,
+			From:       from.StatusToString(),
+			To:         result.Result.StatusToString(),
+			Summary:    strings.TrimSpace(result.Result.Summary),
+			status:     result.Result.Status,
+		})
+	}
+	return transitions
+}
+
+func printTransitions(transitions []statusTransition) {
+	for _, transition := range transitions {
+		log.Infof("%s  %s: %s -> %s\n", transition.Time.Format("15:04:05"), transition.Identifier, transition.From, color.ColorString(transition.status, transition.To))
+	}
+}
+
+// appendWatchHistory adds the transitions to the history file as JSON lines, keeping only the last limit lines
+func appendWatchHistory(historyFile string, transitions []statusTransition, limit int) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	var lines []string
+	existing, err := os.Open(historyFile)
+	if err == nil {
+		scanner := bufio.NewScanner(existing)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		existing.Close()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, transition := range transitions {
+		line, err := json.Marshal(transition)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(line))
+	}
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+
+	return ioutil.WriteFile(historyFile, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/newrelic-diagnostics-cli/registration"
+	"github.com/newrelic/newrelic-diagnostics-cli/tasks"
+)
+
+func watchResult(identifier string, status tasks.Status) registration.TaskResult {
+	return registration.TaskResult{
+		Task:   testResultTask{identifier: tasks.IdentifierFromString(identifier)},
+		Result: tasks.Result{Status: status, Summary: identifier + " summary\n"},
+	}
+}
+
+func Test_findTransitions(t *testing.T) {
+	runTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	previous := []registration.TaskResult{
+		watchResult("Php/Daemon/Running", tasks.Success),
+		watchResult("Base/Collector/ConnectUS", tasks.Success),
+		watchResult("Infra/Env/ClockSkew", tasks.Warning),
+	}
+	current := []registration.TaskResult{
+		watchResult("Php/Daemon/Running", tasks.Failure),
+		watchResult("Base/Collector/ConnectUS", tasks.Success),
+		watchResult("Infra/Env/ClockSkew", tasks.Success),
+		watchResult("Base/Env/HostInfo", tasks.Info),
+	}
+
+	got := findTransitions(previous, current, 2, runTime)
+
+	want := []statusTransition{
+		{Time: runTime, Run: 2, Identifier: "Php/Daemon/Running", From: "Success", To: "Failure", Summary: "Php/Daemon/Running summary", status: tasks.Failure},
+		{Time: runTime, Run: 2, Identifier: "Infra/Env/ClockSkew", From: "Warning", To: "Success", Summary: "Infra/Env/ClockSkew summary", status: tasks.Success},
+		{Time: runTime, Run: 2, Identifier: "Base/Env/HostInfo", From: "None", To: "Info", Summary: "Base/Env/HostInfo summary", status: tasks.Info},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findTransitions() returned %d transitions, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("findTransitions()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_appendWatchHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nrdiag-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	historyFile := filepath.Join(dir, watchHistoryName)
+
+	for run := 1; run <= 4; run++ {
+		transitions := []statusTransition{{Run: run, Identifier: "Php/Daemon/Running", From: "Success", To: "Failure"}}
+		if err := appendWatchHistory(historyFile, transitions, 3); err != nil {
+			t.Fatalf("appendWatchHistory() error = %v", err)
+		}
+	}
+	// no transitions leaves the file untouched
+	if err := appendWatchHistory(historyFile, nil, 3); err != nil {
+		t.Fatalf("appendWatchHistory() error = %v", err)
+	}
+
+	content, err := ioutil.ReadFile(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("history has %d lines, want the last 3:\n%s", len(lines), content)
+	}
+	var first statusTransition
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Run != 2 {
+		t.Errorf("oldest kept transition is from run %d, want 2", first.Run)
+	}
+}